@@ -1,6 +1,16 @@
 -- Banco de dados compartilhado no monólito
 -- Todos os domínios (payments, notifications) usam o mesmo banco
+--
+-- NOTE: No monólito, ambos os domínios compartilham o mesmo banco
+-- Isso quebra a autonomia de dados, mas é aceitável em um monólito inicial
+--
+-- Schema completo de referência, GERADO a partir das migrações em
+-- monolith/infra/database/migrations/sql (a fonte da verdade, aplicadas com
+-- "migrate up"). Não edite: rode "go test ./infra/database/migrations -update-schema".
 
+-- 0001_create_pix_payments
+-- Pagamentos PIX. IF NOT EXISTS: num banco que já tem a tabela a migração só
+-- passa a versioná-lo
 CREATE TABLE IF NOT EXISTS pix_payments (
   id BIGSERIAL PRIMARY KEY,
   amount NUMERIC(18,2) NOT NULL,
@@ -8,6 +18,9 @@ CREATE TABLE IF NOT EXISTS pix_payments (
   created_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
+-- 0002_create_notifications
+-- Notificações. No monólito o banco é compartilhado, então a FK para o
+-- pagamento é possível (nos microsserviços é apenas uma referência)
 CREATE TABLE IF NOT EXISTS notifications (
   id BIGSERIAL PRIMARY KEY,
   payment_id BIGINT NOT NULL,
@@ -19,5 +32,69 @@ CREATE TABLE IF NOT EXISTS notifications (
   CONSTRAINT fk_payment FOREIGN KEY (payment_id) REFERENCES pix_payments(id)
 );
 
--- NOTE: No monólito, ambos os domínios compartilham o mesmo banco
--- Isso quebra a autonomia de dados, mas é aceitável em um monólito inicial
+-- 0003_create_webhooks
+-- Webhooks dos lojistas (callbacks de mudança de status dos pagamentos)
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+  id BIGSERIAL PRIMARY KEY,
+  merchant_id TEXT NOT NULL,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  event_types TEXT[] NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Log de entregas: uma linha por tentativa
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id BIGSERIAL PRIMARY KEY,
+  endpoint_id BIGINT NOT NULL REFERENCES webhook_endpoints(id),
+  payment_id BIGINT NOT NULL,
+  event_type TEXT NOT NULL,
+  attempt INT NOT NULL,
+  status_code INT NOT NULL,
+  success BOOLEAN NOT NULL,
+  error TEXT NOT NULL DEFAULT '',
+  next_retry_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_merchant ON webhook_endpoints (merchant_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint ON webhook_deliveries (endpoint_id);
+
+-- 0004_add_merchant_id
+-- Lojista dono do pagamento: cada lojista só vê os próprios pagamentos.
+-- IF NOT EXISTS: num banco que já tem a coluna e o índice a migração não falha
+ALTER TABLE pix_payments ADD COLUMN IF NOT EXISTS merchant_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_pix_payments_merchant ON pix_payments (merchant_id, created_at DESC);
+
+-- 0005_add_payer_id
+-- Pagador, para o limite diário por pagador
+ALTER TABLE pix_payments ADD COLUMN IF NOT EXISTS payer_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_pix_payments_payer ON pix_payments (payer_id, created_at);
+
+-- 0006_add_risk_assessment
+-- Avaliação do antifraude
+ALTER TABLE pix_payments
+  ADD COLUMN IF NOT EXISTS risk_decision TEXT, -- approve/review/deny
+  ADD COLUMN IF NOT EXISTS risk_reasons TEXT[]; -- Regras que dispararam
+
+-- 0007_add_manual_review
+-- Revisão manual dos pagamentos retidos pelo antifraude
+ALTER TABLE pix_payments
+  ADD COLUMN IF NOT EXISTS review_deadline TIMESTAMPTZ, -- Prazo da revisão (PENDING_REVIEW)
+  ADD COLUMN IF NOT EXISTS review_decision TEXT, -- approved/rejected/expired
+  ADD COLUMN IF NOT EXISTS reviewed_by TEXT, -- Identidade do revisor (ou "system" na expiração)
+  ADD COLUMN IF NOT EXISTS review_notes TEXT,
+  ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_pix_payments_pending_review ON pix_payments (review_deadline) WHERE status = 'PENDING_REVIEW';
+
+-- 0008_add_webhook_retries
+-- Retentativas de webhook persistidas: a linha com next_retry_at guarda o corpo
+-- do evento e o worker de retentativas a reenvia quando vence
+ALTER TABLE webhook_deliveries
+  ADD COLUMN IF NOT EXISTS payload BYTEA, -- Corpo enviado, só nas linhas com retentativa agendada
+  ADD COLUMN IF NOT EXISTS retried_at TIMESTAMPTZ; -- Quando a tentativa seguinte foi registrada
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_retry_at) WHERE retried_at IS NULL;
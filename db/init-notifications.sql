@@ -1,6 +1,16 @@
 -- Banco de dados próprio do serviço de notificações
 -- Autonomia de dados: apenas este serviço acessa este banco
+--
+-- NOTE: Cada microsserviço tem seu próprio banco de dados
+-- Isso garante autonomia e evita acoplamento
+--
+-- Schema completo de referência, GERADO a partir das migrações em
+-- microservices/notifications-service/infra/migrations/sql (a fonte da verdade, aplicadas com
+-- "migrate up"). Não edite: rode "go test ./infra/migrations -update-schema".
 
+-- 0001_create_notifications
+-- Notificações. IF NOT EXISTS: bancos criados antes das migrações (pelo
+-- db/init-notifications.sql) já têm a tabela e só passam a ser versionados
 CREATE TABLE IF NOT EXISTS notifications (
   id BIGSERIAL PRIMARY KEY,
   payment_id BIGINT NOT NULL,
@@ -11,6 +21,3 @@ CREATE TABLE IF NOT EXISTS notifications (
   created_at TIMESTAMPTZ NOT NULL DEFAULT now()
   -- NOTE: payment_id é apenas uma referência (sem FK, pois o pagamento está em outro serviço/banco)
 );
-
--- NOTE: Cada microsserviço tem seu próprio banco de dados
--- Isso garante autonomia e evita acoplamento
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"fintech-notifications-service/domain"
+	"fintech-shared/httpapi"
+	"net/http"
+)
+
+// apiErrors é a tabela central que traduz os erros de domínio em respostas;
+// os códigos são estáveis e documentados no README
+var apiErrors = httpapi.ErrorMap{
+	{Err: domain.ErrNotFound, Status: http.StatusNotFound, Code: "notification_not_found"},
+	{Err: domain.ErrInvalidAmount, Status: http.StatusBadRequest, Code: "invalid_amount"},
+	{Err: domain.ErrInvalidTransition, Status: http.StatusConflict, Code: "invalid_transition"},
+}
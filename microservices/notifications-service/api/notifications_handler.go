@@ -1,131 +1,170 @@
 package api
 
 import (
-	"encoding/json"
 	"fintech-notifications-service/application"
 	"fintech-notifications-service/domain"
-	"log"
+	"fintech-shared/auth"
+	"fintech-shared/httpapi"
+	"fintech-shared/logging"
+	"fintech-shared/notificationsapi"
+	"log/slog"
 	"net/http"
 	"strconv"
-	"strings"
 )
 
 type NotificationsHandler struct {
 	createUC *application.CreateNotificationUseCase
 	repo     domain.NotificationRepository
+	authn    *auth.Authenticator
 }
 
-type createNotificationRequest struct {
-	PaymentID int64   `json:"payment_id"`
-	Amount    float64 `json:"amount"`
-	Type      string  `json:"type"`
+func NewNotificationsHandler(createUC *application.CreateNotificationUseCase, repo domain.NotificationRepository, authn *auth.Authenticator) *NotificationsHandler {
+	return &NotificationsHandler{createUC: createUC, repo: repo, authn: authn}
 }
 
-func NewNotificationsHandler(createUC *application.CreateNotificationUseCase, repo domain.NotificationRepository) *NotificationsHandler {
-	return &NotificationsHandler{createUC: createUC, repo: repo}
+func (h *NotificationsHandler) RegisterRoutes(mux httpapi.Mux) {
+	mux.Handle("GET "+notificationsapi.NotificationsPath, h.authn.Middleware(http.HandlerFunc(h.listAll)))
+	mux.Handle("POST "+notificationsapi.NotificationsPath, h.authn.Middleware(http.HandlerFunc(h.create)))
+	mux.Handle("GET "+notificationsapi.NotificationPath, h.authn.Middleware(http.HandlerFunc(h.getByID)))
 }
 
-func (h *NotificationsHandler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/notifications", h.handleNotifications)
-	mux.HandleFunc("/notifications/", h.handleNotificationByID)
-}
-
-func (h *NotificationsHandler) handleNotifications(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.listAll(w, r)
-	case http.MethodPost:
-		h.create(w, r)
-	default:
-		w.WriteHeader(http.StatusMethodNotAllowed)
+// listAll godoc
+// @Summary      Lista as notificações
+// @Description  Retorna todas as notificações registradas. Exige o escopo notifications:read.
+// @Tags         notifications
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Success      200  {array}   notificationsapi.Notification
+// @Failure      401  {object}  httpapi.Problem
+// @Failure      403  {object}  httpapi.Problem
+// @Failure      500  {object}  httpapi.Problem
+// @Router       /notifications [get]
+func (h *NotificationsHandler) listAll(w http.ResponseWriter, r *http.Request) {
+	if _, ok := auth.Authorize(w, r, auth.ScopeNotificationsRead); !ok {
+		return
 	}
-}
 
-func (h *NotificationsHandler) listAll(w http.ResponseWriter, r *http.Request) {
-	log.Printf("INFO: Listing all notifications")
+	slog.InfoContext(r.Context(), "listing notifications")
 
-	notificationsList, err := h.repo.FindAll()
+	notificationsList, err := h.repo.FindAll(r.Context())
 	if err != nil {
-		log.Printf("ERROR: Failed to list notifications: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		slog.ErrorContext(r.Context(), "failed to list notifications", "error", err)
+		apiErrors.Write(w, r, err)
 		return
 	}
 
-	if notificationsList == nil {
-		notificationsList = []*domain.Notification{} // Retorna array vazio ao invés de null
+	response := make([]notificationsapi.Notification, 0, len(notificationsList)) // Array vazio ao invés de null
+	for _, notification := range notificationsList {
+		response = append(response, toResponse(notification))
 	}
 
-	log.Printf("INFO: Found %d notifications", len(notificationsList))
-	writeJSON(w, http.StatusOK, notificationsList)
+	slog.InfoContext(r.Context(), "notifications found", "count", len(notificationsList))
+	notificationsapi.WriteJSON(w, http.StatusOK, response)
 }
 
+// create godoc
+// @Summary      Cria uma notificação
+// @Description  Registra e envia a notificação de um evento de pagamento. Chamado pelo payments-service com um token de serviço (escopo notifications:write).
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        request  body      notificationsapi.CreateNotificationRequest  true  "Evento do pagamento"
+// @Security     BearerAuth
+// @Success      201      {object}  notificationsapi.Notification
+// @Failure      400      {object}  httpapi.Problem  "JSON inválido ou campos inválidos (listados em errors)"
+// @Failure      401      {object}  httpapi.Problem
+// @Failure      403      {object}  httpapi.Problem
+// @Failure      413      {object}  httpapi.Problem  "Corpo acima do limite (64 KiB)"
+// @Failure      415      {object}  httpapi.Problem  "Content-Type diferente de application/json"
+// @Failure      500      {object}  httpapi.Problem
+// @Router       /notifications [post]
 func (h *NotificationsHandler) create(w http.ResponseWriter, r *http.Request) {
-	var req createNotificationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("ERROR: Failed to decode request: %v", err)
-		http.Error(w, "invalid json", http.StatusBadRequest)
+	if _, ok := auth.Authorize(w, r, auth.ScopeNotificationsWrite); !ok {
 		return
 	}
 
-	// Criar mensagem baseada no tipo
-	message := "Payment created successfully"
-	switch req.Type {
-	case "PAYMENT_CREATED":
-		message = "Your payment has been created"
-	case "PAYMENT_AUTHORIZED":
-		message = "Your payment has been authorized"
-	case "PAYMENT_SETTLED":
-		message = "Your payment has been settled"
+	req, err := notificationsapi.DecodeCreateNotification(w, r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "invalid notification request", "error", err)
+		apiErrors.Write(w, r, err)
+		return
 	}
+	message := messages[req.Type]
 
-	log.Printf("INFO: Creating notification - Type: %s, PaymentID: %d", req.Type, req.PaymentID)
+	// O request_id vem do payments-service (X-Request-ID) e liga os logs dos dois serviços
+	ctx := logging.WithPaymentID(r.Context(), req.PaymentID)
+	slog.InfoContext(ctx, "creating notification", "type", req.Type)
 
-	notification, err := h.createUC.Execute(req.PaymentID, req.Type, "user@example.com", message)
+	notification, err := h.createUC.Execute(ctx, req.PaymentID, req.Amount, string(req.Type), "user@example.com", message)
 	if err != nil {
-		log.Printf("ERROR: Failed to create notification: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		slog.ErrorContext(ctx, "failed to create notification", "error", err)
+		apiErrors.Write(w, r, err)
 		return
 	}
 
-	log.Printf("INFO: Notification created successfully - ID: %d, Type: %s", notification.ID, notification.Type)
-	writeJSON(w, http.StatusCreated, notification)
+	slog.InfoContext(ctx, "notification created successfully", "notification_id", notification.ID, "type", notification.Type)
+	notificationsapi.WriteCreated(w, toResponse(notification))
 }
 
-func (h *NotificationsHandler) handleNotificationByID(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		return
-	}
-
-	// Extrair ID da URL: /notifications/{id}
-	path := strings.TrimPrefix(r.URL.Path, "/notifications/")
-	if path == "" {
-		http.Error(w, "notification ID is required", http.StatusBadRequest)
+// getByID godoc
+// @Summary      Busca notificação por ID
+// @Description  Retorna uma notificação pelo seu ID. Exige o escopo notifications:read.
+// @Tags         notifications
+// @Produce      json
+// @Param        id   path      int  true  "ID da notificação"
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Success      200  {object}  notificationsapi.Notification
+// @Failure      400  {object}  httpapi.Problem
+// @Failure      401  {object}  httpapi.Problem
+// @Failure      403  {object}  httpapi.Problem
+// @Failure      404  {object}  httpapi.Problem
+// @Failure      500  {object}  httpapi.Problem
+// @Router       /notifications/{id} [get]
+func (h *NotificationsHandler) getByID(w http.ResponseWriter, r *http.Request) {
+	if _, ok := auth.Authorize(w, r, auth.ScopeNotificationsRead); !ok {
 		return
 	}
 
-	id, err := strconv.ParseInt(path, 10, 64)
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
-		log.Printf("ERROR: Invalid notification ID: %s", path)
-		http.Error(w, "invalid notification ID", http.StatusBadRequest)
+		slog.WarnContext(r.Context(), "invalid notification id", "id", r.PathValue("id"))
+		httpapi.WriteProblem(w, r, http.StatusBadRequest, httpapi.CodeInvalidID, "invalid notification ID")
 		return
 	}
 
-	log.Printf("INFO: Fetching notification with ID: %d", id)
+	slog.InfoContext(r.Context(), "fetching notification", "notification_id", id)
 
-	notification, err := h.repo.FindByID(id)
+	notification, err := h.repo.FindByID(r.Context(), id)
 	if err != nil {
-		log.Printf("ERROR: Failed to find notification %d: %v", id, err)
-		http.Error(w, "notification not found", http.StatusNotFound)
+		slog.WarnContext(r.Context(), "failed to find notification", "notification_id", id, "error", err)
+		apiErrors.Write(w, r, err)
 		return
 	}
 
-	log.Printf("INFO: Notification found - ID: %d, Type: %s", notification.ID, notification.Type)
-	writeJSON(w, http.StatusOK, notification)
+	slog.InfoContext(r.Context(), "notification found", "notification_id", notification.ID, "type", notification.Type)
+	notificationsapi.WriteJSON(w, http.StatusOK, toResponse(notification))
 }
 
-func writeJSON(w http.ResponseWriter, status int, payload any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(payload)
+// messages é o texto enviado para cada tipo aceito (notificationsapi.NotificationTypes)
+var messages = map[notificationsapi.NotificationType]string{
+	notificationsapi.TypePaymentCreated:       "Your payment has been created",
+	notificationsapi.TypePaymentAuthorized:    "Your payment has been authorized",
+	notificationsapi.TypePaymentSettled:       "Your payment has been settled",
+	notificationsapi.TypePaymentPendingReview: "Your payment is under review",
+	notificationsapi.TypePaymentRejected:      "Your payment has been rejected",
+}
+
+// toResponse converte a notificação do domínio no formato publicado no contrato
+func toResponse(n *domain.Notification) notificationsapi.Notification {
+	return notificationsapi.Notification{
+		ID:        n.ID,
+		PaymentID: n.PaymentID,
+		Type:      notificationsapi.NotificationType(n.Type),
+		Recipient: n.Recipient,
+		Message:   n.Message,
+		Status:    string(n.Status),
+		CreatedAt: n.CreatedAt,
+	}
 }
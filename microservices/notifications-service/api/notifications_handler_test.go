@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fintech-notifications-service/application"
+	"fintech-notifications-service/docs"
+	"fintech-notifications-service/domain"
+	"fintech-notifications-service/domain/domaintest"
+	"fintech-shared/auth"
+	"fintech-shared/httpapi"
+	"fintech-shared/notificationsapi/contracttest"
+	"fintech-shared/openapi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func serviceToken(secret, audience string, exp time.Time) string {
+	enc := func(v any) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	unsigned := enc(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + enc(map[string]any{
+		"sub":   "payments-service",
+		"iss":   "payments-service",
+		"aud":   audience,
+		"exp":   exp.Unix(),
+		"scope": auth.ScopeNotificationsWrite,
+	})
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// As requisições rejeitadas nunca chegam ao use case nem ao repositório
+func TestNotificationsHandler_RejectsUnauthenticatedWrites(t *testing.T) {
+	store, _ := auth.ParseAPIKeys("backoffice-key|backoffice|notifications:read")
+	verifier, _ := auth.NewJWTVerifier([]byte("service-secret"), "", "payments-service", auth.NotificationsAudience)
+	mux := http.NewServeMux()
+	NewNotificationsHandler(nil, nil, auth.NewAuthenticator(store, verifier)).RegisterRoutes(mux)
+
+	body := `{"payment_id":1,"amount":10,"type":"PAYMENT_CREATED"}`
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{"no credentials", nil, http.StatusUnauthorized},
+		{"read-only api key", map[string]string{auth.APIKeyHeader: "backoffice-key"}, http.StatusForbidden},
+		{"wrong audience", map[string]string{"Authorization": "Bearer " + serviceToken("service-secret", "payments-service", time.Now().Add(time.Minute))}, http.StatusUnauthorized},
+		{"expired token", map[string]string{"Authorization": "Bearer " + serviceToken("service-secret", auth.NotificationsAudience, time.Now().Add(-time.Hour))}, http.StatusUnauthorized},
+		{"forged token", map[string]string{"Authorization": "Bearer " + serviceToken("guessed-secret", auth.NotificationsAudience, time.Now().Add(time.Minute))}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/notifications", strings.NewReader(body))
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+// brokenRepository simula o banco fora do ar
+type brokenRepository struct {
+	domaintest.NotificationRepository
+}
+
+var errConnRefused = errors.New("dial tcp 10.0.0.5:5432: connect: connection refused")
+
+func (*brokenRepository) FindByID(context.Context, int64) (*domain.Notification, error) {
+	return nil, errConnRefused
+}
+
+func (*brokenRepository) FindAll(context.Context) ([]*domain.Notification, error) {
+	return nil, errConnRefused
+}
+
+// Os erros saem como application/problem+json com código estável; erros de
+// infraestrutura viram 500 sem expor a mensagem do driver
+func TestNotificationsHandler_WritesProblems(t *testing.T) {
+	store, _ := auth.ParseAPIKeys("backoffice-key|backoffice|notifications:read")
+	verifier, _ := auth.NewJWTVerifier([]byte("service-secret"), "", "payments-service", auth.NotificationsAudience)
+	authn := auth.NewAuthenticator(store, verifier)
+	token := serviceToken("service-secret", auth.NotificationsAudience, time.Now().Add(time.Minute))
+
+	repo := domaintest.NewNotificationRepository()
+	mux := http.NewServeMux()
+	NewNotificationsHandler(application.NewCreateNotificationUseCase(repo, nil), repo, authn).RegisterRoutes(mux)
+	broken := &brokenRepository{}
+	brokenMux := http.NewServeMux()
+	NewNotificationsHandler(nil, broken, authn).RegisterRoutes(brokenMux)
+
+	tests := []struct {
+		name        string
+		mux         *http.ServeMux
+		method      string
+		path        string
+		body        string
+		wantStatus  int
+		wantCode    string
+		contentType string // POST: application/json quando vazio
+		wantField   string // Campo esperado em errors (validation_failed)
+	}{
+		{"unknown notification", mux, http.MethodGet, "/notifications/42", "", http.StatusNotFound, "notification_not_found", "", ""},
+		{"invalid id", mux, http.MethodGet, "/notifications/abc", "", http.StatusBadRequest, httpapi.CodeInvalidID, "", ""},
+		{"invalid json", mux, http.MethodPost, "/notifications", "{", http.StatusBadRequest, httpapi.CodeInvalidJSON, "", ""},
+		{"invalid amount", mux, http.MethodPost, "/notifications", `{"payment_id":1,"amount":0,"type":"PAYMENT_CREATED"}`, http.StatusBadRequest, httpapi.CodeValidationFailed, "", "amount"},
+		{"unknown type", mux, http.MethodPost, "/notifications", `{"payment_id":1,"amount":10,"type":"PAYMENT_REFUNDED"}`, http.StatusBadRequest, httpapi.CodeValidationFailed, "", "type"},
+		{"missing payment", mux, http.MethodPost, "/notifications", `{"amount":10,"type":"PAYMENT_CREATED"}`, http.StatusBadRequest, httpapi.CodeValidationFailed, "", "payment_id"},
+		{"unknown field", mux, http.MethodPost, "/notifications", `{"payment_id":1,"amount":10,"type":"PAYMENT_CREATED","recipient":"x"}`, http.StatusBadRequest, httpapi.CodeValidationFailed, "", "recipient"},
+		{"wrong content type", mux, http.MethodPost, "/notifications", `{"payment_id":1,"amount":10,"type":"PAYMENT_CREATED"}`, http.StatusUnsupportedMediaType, httpapi.CodeUnsupportedMediaType, "text/plain", ""},
+		{"body too large", mux, http.MethodPost, "/notifications", `{"type":"` + strings.Repeat("x", httpapi.MaxBodyBytes) + `"}`, http.StatusRequestEntityTooLarge, httpapi.CodeRequestTooLarge, "", ""},
+		{"unauthenticated", mux, http.MethodGet, "/notifications", "", http.StatusUnauthorized, httpapi.CodeUnauthorized, "", ""},
+		{"database down on get", brokenMux, http.MethodGet, "/notifications/1", "", http.StatusInternalServerError, httpapi.CodeInternal, "", ""},
+		{"database down on list", brokenMux, http.MethodGet, "/notifications", "", http.StatusInternalServerError, httpapi.CodeInternal, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			switch {
+			case tt.wantCode == httpapi.CodeUnauthorized:
+			case tt.method == http.MethodPost:
+				req.Header.Set("Authorization", "Bearer "+token)
+				req.Header.Set("Content-Type", "application/json")
+				if tt.contentType != "" {
+					req.Header.Set("Content-Type", tt.contentType)
+				}
+			default:
+				req.Header.Set(auth.APIKeyHeader, "backoffice-key")
+			}
+			rec := httptest.NewRecorder()
+			tt.mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); got != httpapi.ProblemContentType {
+				t.Errorf("Content-Type = %q", got)
+			}
+			var problem httpapi.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Code != tt.wantCode || problem.Status != tt.wantStatus || problem.Instance != req.URL.Path {
+				t.Errorf("problem = %+v", problem)
+			}
+			if tt.wantField != "" && (len(problem.Errors) != 1 || problem.Errors[0].Field != tt.wantField) {
+				t.Errorf("errors = %+v, want field %s", problem.Errors, tt.wantField)
+			}
+			if strings.Contains(rec.Body.String(), "connection refused") {
+				t.Errorf("database error leaked: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+// Teste de contrato do lado provedor: o handler real precisa atender a cada
+// interação que o payments-service registrou no pact
+func TestNotificationsHandler_VerifiesPaymentsServicePact(t *testing.T) {
+	store, _ := auth.ParseAPIKeys("")
+	verifier, _ := auth.NewJWTVerifier([]byte("service-secret"), "", "payments-service", auth.NotificationsAudience)
+	repo := domaintest.NewNotificationRepository()
+	mux := http.NewServeMux()
+	NewNotificationsHandler(application.NewCreateNotificationUseCase(repo, nil), repo, auth.NewAuthenticator(store, verifier)).RegisterRoutes(mux)
+
+	// O pact não registra credenciais: o token é o que o payments-service assina
+	token := serviceToken("service-secret", auth.NotificationsAudience, time.Now().Add(time.Minute))
+	contracttest.VerifyProvider(t, contracttest.PaymentsService(), mux, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	})
+}
+
+// A documentação OpenAPI precisa bater com as rotas que o handler monta.
+// As rotas de health ficam no main e não passam por RegisterRoutes.
+func TestRegisterRoutes_MatchesOpenAPI(t *testing.T) {
+	routes := openapi.NewRoutes()
+	NewNotificationsHandler(nil, nil, nil).RegisterRoutes(routes)
+
+	all, err := openapi.Operations(docs.SwaggerInfo.ReadDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops []openapi.Operation
+	for _, op := range all {
+		if !op.HasTag("health") {
+			ops = append(ops, op)
+		}
+	}
+
+	unmounted, undocumented := routes.Diff(ops)
+	for _, op := range unmounted {
+		t.Errorf("%s %s is documented but not mounted", op.Method, op.Path)
+	}
+	for _, pattern := range undocumented {
+		t.Errorf("%s is mounted but not documented", pattern)
+	}
+}
+
+// Cada rota chega ao handler (401 sem credencial) e os métodos não montados
+// recebem 405 com o Allow do caminho
+func TestRegisterRoutes_RouteTable(t *testing.T) {
+	store, _ := auth.ParseAPIKeys("")
+	mux := httpapi.NewServeMux()
+	NewNotificationsHandler(nil, nil, auth.NewAuthenticator(store, nil)).RegisterRoutes(mux)
+
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+		wantCode   string
+		wantAllow  string
+	}{
+		{http.MethodGet, "/notifications", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodPost, "/notifications", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodGet, "/notifications/7", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodDelete, "/notifications", http.StatusMethodNotAllowed, httpapi.CodeMethodNotAllowed, "GET, HEAD, POST"},
+		{http.MethodPut, "/notifications/7", http.StatusMethodNotAllowed, httpapi.CodeMethodNotAllowed, "GET, HEAD"},
+		{http.MethodGet, "/notifications/", http.StatusNotFound, httpapi.CodeNotFound, ""},
+		{http.MethodGet, "/notifications/7/retries", http.StatusNotFound, httpapi.CodeNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+			var problem httpapi.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", problem.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -1,27 +1,63 @@
 package application
 
-import "fintech-notifications-service/domain"
+import (
+	"context"
+	"fintech-notifications-service/domain"
+	"fintech-shared/logging"
+	"fintech-shared/tracing"
+	"log/slog"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("fintech-notifications-service/application")
 
 type CreateNotificationUseCase struct {
-	repo domain.NotificationRepository
+	repo    domain.NotificationRepository
+	metrics domain.NotificationMetrics
 }
 
-func NewCreateNotificationUseCase(repo domain.NotificationRepository) *CreateNotificationUseCase {
-	return &CreateNotificationUseCase{repo: repo}
+func NewCreateNotificationUseCase(repo domain.NotificationRepository, metrics domain.NotificationMetrics) *CreateNotificationUseCase {
+	if metrics == nil {
+		metrics = domain.NopMetrics{}
+	}
+	return &CreateNotificationUseCase{repo: repo, metrics: metrics}
 }
 
-func (uc *CreateNotificationUseCase) Execute(paymentID int64, notificationType, recipient, message string) (*domain.Notification, error) {
+func (uc *CreateNotificationUseCase) Execute(ctx context.Context, paymentID int64, amount float64, notificationType, recipient, message string) (*domain.Notification, error) {
+	ctx, span := tracer.Start(ctx, "CreateNotification", trace.WithAttributes(
+		attribute.String("notification.type", notificationType),
+		attribute.Int64("payment.id", paymentID),
+	))
+	defer span.End()
+
+	if err := domain.ValidateAmount(amount); err != nil {
+		return nil, err
+	}
+
+	ctx = logging.WithPaymentID(ctx, paymentID)
 	notification := domain.NewNotification(paymentID, notificationType, recipient, message)
 
 	// Salvar no banco próprio do serviço
-	saved, err := uc.repo.Save(notification)
+	saved, err := uc.repo.Save(ctx, notification)
 	if err != nil {
+		tracing.Fail(span, err)
 		return nil, err
 	}
 
 	// Simular envio (em produção, isso seria um worker assíncrono)
-	saved.MarkAsSent()
-	_, _ = uc.repo.Save(saved)
+	if err := saved.MarkAsSent(); err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+	if _, err := uc.repo.Save(ctx, saved); err != nil {
+		slog.ErrorContext(ctx, "failed to mark notification as sent", "notification_id", saved.ID, "error", err)
+	} else {
+		slog.InfoContext(ctx, "notification sent", "notification_id", saved.ID, "type", notificationType)
+		uc.metrics.NotificationSent(notificationType)
+	}
 
 	return saved, nil
 }
@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fintech-notifications-service/domain"
+	"fintech-notifications-service/domain/domaintest"
+	"testing"
+)
+
+// sentCounter conta as notificações enviadas por tipo
+type sentCounter map[string]int
+
+func (c sentCounter) NotificationSent(notificationType string) { c[notificationType]++ }
+
+func TestCreateNotification_SavesOneSentNotification(t *testing.T) {
+	repo := domaintest.NewNotificationRepository()
+	sent := sentCounter{}
+	uc := NewCreateNotificationUseCase(repo, sent)
+
+	notification, err := uc.Execute(context.Background(), 42, 150, "PAYMENT_CREATED", "pagador-1", "Pagamento PIX criado")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notification.ID == 0 || notification.Status != domain.StatusSent {
+		t.Errorf("notification = %+v", notification)
+	}
+
+	// Marcar como enviada atualiza a mesma notificação, sem duplicar
+	all, _ := repo.FindAll(context.Background())
+	if len(all) != 1 || all[0].Status != domain.StatusSent || all[0].PaymentID != 42 {
+		t.Errorf("stored = %+v", all)
+	}
+	if sent["PAYMENT_CREATED"] != 1 {
+		t.Errorf("metrics = %v", sent)
+	}
+}
+
+func TestCreateNotification_RejectsInvalidAmount(t *testing.T) {
+	repo := domaintest.NewNotificationRepository()
+	uc := NewCreateNotificationUseCase(repo, nil)
+
+	for _, amount := range []float64{0, -10} {
+		if _, err := uc.Execute(context.Background(), 42, amount, "PAYMENT_CREATED", "pagador-1", ""); !errors.Is(err, domain.ErrInvalidAmount) {
+			t.Errorf("amount %v: err = %v, want ErrInvalidAmount", amount, err)
+		}
+	}
+	if all, _ := repo.FindAll(context.Background()); len(all) != 0 {
+		t.Errorf("stored %d notifications, want none", len(all))
+	}
+}
@@ -0,0 +1,112 @@
+package domaintest
+
+import (
+	"context"
+	"errors"
+	"fintech-notifications-service/domain"
+	"slices"
+	"testing"
+	"time"
+)
+
+// RunRepositoryTests verifica o contrato de domain.NotificationRepository.
+// Roda contra o NotificationRepository em memória e contra o PostgreSQL, para
+// que o fake não se afaste do SQL. newRepository deve devolver um repositório
+// vazio a cada chamada.
+func RunRepositoryTests(t *testing.T, newRepository func(t *testing.T) domain.NotificationRepository) {
+	// created_at em microssegundos: a precisão do TIMESTAMPTZ
+	base := time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	save := func(t *testing.T, repo domain.NotificationRepository, paymentID int64, createdAt time.Time) *domain.Notification {
+		t.Helper()
+		notification := domain.NewNotification(paymentID, "PAYMENT_CREATED", "user@example.com", "Pagamento criado")
+		notification.CreatedAt = createdAt
+		saved, err := repo.Save(ctx, notification)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return saved
+	}
+	ids := func(found []*domain.Notification) []int64 {
+		var out []int64
+		for _, n := range found {
+			out = append(out, n.ID)
+		}
+		return out
+	}
+
+	t.Run("save without id inserts", func(t *testing.T) {
+		repo := newRepository(t)
+		first := save(t, repo, 42, base)
+		second := save(t, repo, 42, base)
+		if first.ID == 0 || second.ID <= first.ID {
+			t.Errorf("ids = %d, %d, want increasing", first.ID, second.ID)
+		}
+
+		stored, err := repo.FindByID(ctx, first.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.PaymentID != 42 || stored.Type != "PAYMENT_CREATED" || stored.Recipient != "user@example.com" ||
+			stored.Message != "Pagamento criado" || stored.Status != domain.StatusPending || !stored.CreatedAt.Equal(base) {
+			t.Errorf("stored = %+v, want %+v", stored, first)
+		}
+	})
+
+	t.Run("save with id updates", func(t *testing.T) {
+		repo := newRepository(t)
+		notification := save(t, repo, 42, base)
+		if err := notification.MarkAsSent(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.Save(ctx, notification); err != nil {
+			t.Fatal(err)
+		}
+
+		stored, err := repo.FindByID(ctx, notification.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Status != domain.StatusSent {
+			t.Errorf("status = %s, want SENT", stored.Status)
+		}
+		if all, _ := repo.FindAll(ctx); len(all) != 1 {
+			t.Errorf("stored %d notifications, want 1", len(all))
+		}
+	})
+
+	t.Run("save unknown id", func(t *testing.T) {
+		notification := domain.NewNotification(42, "PAYMENT_CREATED", "user@example.com", "Pagamento criado")
+		notification.ID = 999
+		if _, err := newRepository(t).Save(ctx, notification); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("find unknown id", func(t *testing.T) {
+		if _, err := newRepository(t).FindByID(ctx, 999); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("find all newest first", func(t *testing.T) {
+		repo := newRepository(t)
+		if all, err := repo.FindAll(ctx); err != nil || len(all) != 0 {
+			t.Fatalf("empty repository = %v, %v", ids(all), err)
+		}
+
+		older := save(t, repo, 1, base)
+		newest := save(t, repo, 2, base.Add(time.Hour))
+		sameTime := save(t, repo, 3, base)
+
+		all, err := repo.FindAll(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Empate no created_at: o último inserido primeiro
+		if got, want := ids(all), []int64{newest.ID, sameTime.ID, older.ID}; !slices.Equal(got, want) {
+			t.Errorf("ids = %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+// Package domaintest tem implementações em memória das portas do domínio do
+// notifications-service para os testes
+package domaintest
+
+import (
+	"context"
+	"fintech-notifications-service/domain"
+	"sort"
+	"sync"
+)
+
+// NotificationRepository implementa domain.NotificationRepository em memória.
+// Save sem ID insere; com ID, atualiza a notificação (ex.: ao marcar como enviada).
+type NotificationRepository struct {
+	mu            sync.Mutex
+	nextID        int64
+	notifications map[int64]*domain.Notification
+}
+
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{notifications: map[int64]*domain.Notification{}}
+}
+
+func (r *NotificationRepository) Save(ctx context.Context, notification *domain.Notification) (*domain.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if notification.ID == 0 {
+		r.nextID++
+		notification.ID = r.nextID
+	} else if _, ok := r.notifications[notification.ID]; !ok {
+		return nil, domain.ErrNotFound
+	}
+	saved := *notification
+	r.notifications[saved.ID] = &saved
+	return notification, nil
+}
+
+func (r *NotificationRepository) FindByID(ctx context.Context, id int64) (*domain.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notification, ok := r.notifications[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	found := *notification
+	return &found, nil
+}
+
+// FindAll lista as notificações, mais recentes primeiro
+func (r *NotificationRepository) FindAll(ctx context.Context) ([]*domain.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var all []*domain.Notification
+	for _, notification := range r.notifications {
+		found := *notification
+		all = append(all, &found)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].CreatedAt.Equal(all[j].CreatedAt) {
+			return all[i].CreatedAt.After(all[j].CreatedAt)
+		}
+		return all[i].ID > all[j].ID
+	})
+	return all, nil
+}
@@ -0,0 +1,12 @@
+package domaintest
+
+import (
+	"fintech-notifications-service/domain"
+	"testing"
+)
+
+func TestNotificationRepository_Conformance(t *testing.T) {
+	RunRepositoryTests(t, func(t *testing.T) domain.NotificationRepository {
+		return NewNotificationRepository()
+	})
+}
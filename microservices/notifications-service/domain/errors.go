@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"errors"
+	"math"
+)
+
+// Erros de domínio das notificações, traduzidos pela API em uma tabela central
+var (
+	// ErrNotFound indica que a notificação não existe (o repositório o devolve no lugar do erro do driver)
+	ErrNotFound = errors.New("notification not found")
+	// ErrInvalidAmount indica que o valor do pagamento notificado não é positivo
+	ErrInvalidAmount = errors.New("amount must be greater than 0")
+	// ErrInvalidTransition indica uma mudança de status que o ciclo de vida não permite
+	ErrInvalidTransition = errors.New("invalid status transition")
+)
+
+// ValidateAmount confere o valor do pagamento que originou a notificação
+func ValidateAmount(amount float64) error {
+	if !(amount > 0) || math.IsInf(amount, 1) { // Também recusa NaN
+		return ErrInvalidAmount
+	}
+	return nil
+}
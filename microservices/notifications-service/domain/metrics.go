@@ -0,0 +1,13 @@
+package domain
+
+// NotificationMetrics recebe os eventos das notificações para instrumentação.
+// A implementação (Prometheus) fica na infra: o domínio não conhece a biblioteca.
+type NotificationMetrics interface {
+	// NotificationSent conta as notificações enviadas, por tipo
+	NotificationSent(notificationType string)
+}
+
+// NopMetrics descarta as métricas (uso sem instrumentação)
+type NopMetrics struct{}
+
+func (NopMetrics) NotificationSent(string) {}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type Notification struct {
 	ID        int64              `json:"id"`
@@ -31,10 +34,20 @@ func NewNotification(paymentID int64, notificationType, recipient, message strin
 	}
 }
 
-func (n *Notification) MarkAsSent() {
+// MarkAsSent registra o envio; só notificações pendentes podem ser enviadas
+func (n *Notification) MarkAsSent() error {
+	if n.Status != StatusPending {
+		return fmt.Errorf("%w: only PENDING notifications can be sent", ErrInvalidTransition)
+	}
 	n.Status = StatusSent
+	return nil
 }
 
-func (n *Notification) MarkAsFailed() {
+// MarkAsFailed registra a falha no envio de uma notificação pendente
+func (n *Notification) MarkAsFailed() error {
+	if n.Status != StatusPending {
+		return fmt.Errorf("%w: only PENDING notifications can fail", ErrInvalidTransition)
+	}
 	n.Status = StatusFailed
+	return nil
 }
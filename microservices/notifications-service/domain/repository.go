@@ -1,7 +1,9 @@
 package domain
 
+import "context"
+
 type NotificationRepository interface {
-	Save(notification *Notification) (*Notification, error)
-	FindByID(id int64) (*Notification, error)
-	FindAll() ([]*Notification, error)
+	Save(ctx context.Context, notification *Notification) (*Notification, error)
+	FindByID(ctx context.Context, id int64) (*Notification, error)
+	FindAll(ctx context.Context) ([]*Notification, error)
 }
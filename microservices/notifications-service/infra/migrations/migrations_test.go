@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"fintech-shared/migrate"
+	"fintech-shared/migrate/migratetest"
+	"testing"
+)
+
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	migrations, err := migrate.Load(files, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+	// Versões sequenciais a partir de 1, sem buracos
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d has version %d, want %d", i, m.Version, i+1)
+		}
+	}
+}
+
+// schemaHeader abre o db/init-notifications.sql, gerado a partir das migrações
+const schemaHeader = `-- Banco de dados próprio do serviço de notificações
+-- Autonomia de dados: apenas este serviço acessa este banco
+--
+-- NOTE: Cada microsserviço tem seu próprio banco de dados
+-- Isso garante autonomia e evita acoplamento
+--
+-- Schema completo de referência, GERADO a partir das migrações em
+-- microservices/notifications-service/infra/migrations/sql (a fonte da verdade, aplicadas com
+-- "migrate up"). Não edite: rode "go test ./infra/migrations -update-schema".
+`
+
+func TestSchemaFile(t *testing.T) {
+	migratetest.CheckSchemaFile(t, files, "sql", "init-notifications.sql", schemaHeader)
+}
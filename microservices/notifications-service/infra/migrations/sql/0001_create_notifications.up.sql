@@ -0,0 +1,12 @@
+-- Notificações. IF NOT EXISTS: bancos criados antes das migrações (pelo
+-- db/init-notifications.sql) já têm a tabela e só passam a ser versionados
+CREATE TABLE IF NOT EXISTS notifications (
+  id BIGSERIAL PRIMARY KEY,
+  payment_id BIGINT NOT NULL,
+  type TEXT NOT NULL,
+  recipient TEXT NOT NULL,
+  message TEXT NOT NULL,
+  status TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+  -- NOTE: payment_id é apenas uma referência (sem FK, pois o pagamento está em outro serviço/banco)
+);
@@ -2,9 +2,11 @@ package persistence
 
 import (
 	"context"
+	"errors"
 	"fintech-notifications-service/domain"
-	"time"
+	"fintech-shared/tracing"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -16,9 +18,26 @@ func NewPgNotificationRepository(pool *pgxpool.Pool) *PgNotificationRepository {
 	return &PgNotificationRepository{pool: pool}
 }
 
-func (r *PgNotificationRepository) Save(notification *domain.Notification) (*domain.Notification, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Save insere a notificação nova (sem ID) ou atualiza a existente, como ao
+// marcá-la como enviada depois do envio
+func (r *PgNotificationRepository) Save(ctx context.Context, notification *domain.Notification) (*domain.Notification, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "NotificationRepository.Save")
 	defer cancel()
+	defer span.End()
+
+	if notification.ID != 0 {
+		tag, err := r.pool.Exec(ctx,
+			"UPDATE notifications SET payment_id = $1, type = $2, recipient = $3, message = $4, status = $5 WHERE id = $6",
+			notification.PaymentID, notification.Type, notification.Recipient, notification.Message, string(notification.Status), notification.ID,
+		)
+		if err != nil {
+			return nil, tracing.ObserveDB(span, err)
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, domain.ErrNotFound
+		}
+		return notification, nil
+	}
 
 	var id int64
 	err := r.pool.QueryRow(ctx,
@@ -27,16 +46,17 @@ func (r *PgNotificationRepository) Save(notification *domain.Notification) (*dom
 	).Scan(&id)
 
 	if err != nil {
-		return nil, err
+		return nil, tracing.ObserveDB(span, err)
 	}
 
 	notification.ID = id
 	return notification, nil
 }
 
-func (r *PgNotificationRepository) FindByID(id int64) (*domain.Notification, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *PgNotificationRepository) FindByID(ctx context.Context, id int64) (*domain.Notification, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "NotificationRepository.FindByID")
 	defer cancel()
+	defer span.End()
 
 	var notification domain.Notification
 	var status string
@@ -45,23 +65,27 @@ func (r *PgNotificationRepository) FindByID(id int64) (*domain.Notification, err
 		id,
 	).Scan(&notification.ID, &notification.PaymentID, &notification.Type, &notification.Recipient, &notification.Message, &status, &notification.CreatedAt)
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
 	if err != nil {
-		return nil, err
+		return nil, tracing.ObserveDB(span, err)
 	}
 
 	notification.Status = domain.NotificationStatus(status)
 	return &notification, nil
 }
 
-func (r *PgNotificationRepository) FindAll() ([]*domain.Notification, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *PgNotificationRepository) FindAll(ctx context.Context) ([]*domain.Notification, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "NotificationRepository.FindAll")
 	defer cancel()
+	defer span.End()
 
 	rows, err := r.pool.Query(ctx,
-		"SELECT id, payment_id, type, recipient, message, status, created_at FROM notifications ORDER BY created_at DESC",
+		"SELECT id, payment_id, type, recipient, message, status, created_at FROM notifications ORDER BY created_at DESC, id DESC",
 	)
 	if err != nil {
-		return nil, err
+		return nil, tracing.ObserveDB(span, err)
 	}
 	defer rows.Close()
 
@@ -70,14 +94,14 @@ func (r *PgNotificationRepository) FindAll() ([]*domain.Notification, error) {
 		var notification domain.Notification
 		var status string
 		if err := rows.Scan(&notification.ID, &notification.PaymentID, &notification.Type, &notification.Recipient, &notification.Message, &status, &notification.CreatedAt); err != nil {
-			return nil, err
+			return nil, tracing.ObserveDB(span, err)
 		}
 		notification.Status = domain.NotificationStatus(status)
 		notifications = append(notifications, &notification)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, tracing.ObserveDB(span, err)
 	}
 
 	return notifications, nil
@@ -0,0 +1,30 @@
+package persistence
+
+import (
+	"fintech-notifications-service/domain"
+	"fintech-notifications-service/domain/domaintest"
+	"fintech-notifications-service/infra/migrations"
+	"fintech-shared/migrate/migratetest"
+	"fintech-shared/pgtest"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pgtest.Run(m))
+}
+
+func TestPgNotificationRepository_Conformance(t *testing.T) {
+	pgtest.DSN(t) // Sem servidor, pula a suíte inteira de uma vez
+	domaintest.RunRepositoryTests(t, func(t *testing.T) domain.NotificationRepository {
+		return NewPgNotificationRepository(pgtest.NewPool(t, "init-notifications.sql"))
+	})
+}
+
+// Mesma suíte com o schema montado pelas migrações embutidas no binário
+func TestPgNotificationRepository_Migrations(t *testing.T) {
+	pgtest.DSN(t)
+	domaintest.RunRepositoryTests(t, func(t *testing.T) domain.NotificationRepository {
+		return NewPgNotificationRepository(migratetest.NewPool(t, migrations.New))
+	})
+}
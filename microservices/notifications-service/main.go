@@ -4,16 +4,70 @@ import (
 	"context"
 	"fintech-notifications-service/api"
 	app "fintech-notifications-service/application"
+	"fintech-notifications-service/docs"
+	"fintech-notifications-service/infra/migrations"
 	"fintech-notifications-service/infra/persistence"
+	"fintech-shared/auth"
+	"fintech-shared/health"
+	"fintech-shared/httpapi"
+	"fintech-shared/logging"
+	"fintech-shared/metrics"
+	"fintech-shared/migrate"
+	"fintech-shared/openapi"
+	"fintech-shared/tracing"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+//go:generate swag init -g main.go -d .,./api --parseDependency -o docs
+
+// @title           Fintech Notifications Service API
+// @version         1.0
+// @description     Microsserviço de notificações, com banco próprio. Recebe as notificações do payments-service (contrato em shared/notificationsapi).
+
+// @contact.name   Fintech Dev
+// @contact.url    https://github.com/fintechdev
+
+// @host      localhost:8082
+// @BasePath  /
+
+// @schemes   http
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 API key do backoffice (escopo notifications:read)
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 Token de serviço do payments-service no formato "Bearer {token}" (aud notifications-service, escopo notifications:write)
+
 func main() {
+	// Logs estruturados em JSON (nível em LOG_LEVEL)
+	logging.Setup("notifications-service")
+
+	// Subcomando "migrate up|down|status": gerencia o schema e encerra
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate.RunCommand("notifications-service", os.Args[2:], migrations.New); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Tracing distribuído (OpenTelemetry): continua o trace recebido no traceparent
+	shutdownTracing, err := tracing.Setup(context.Background(), "notifications-service")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { _ = shutdownTracing(context.Background()) }()
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -24,6 +78,12 @@ func main() {
 		log.Fatal("DATABASE_URL is required")
 	}
 
+	// Autenticação por API key e/ou token de serviço (ver fintech-shared/auth); /health continua público
+	authenticator, err := auth.NewServiceAuthenticatorFromEnv(auth.NotificationsAudience)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -34,30 +94,98 @@ func main() {
 	}
 	defer pool.Close()
 
+	// Não sobe com o schema desatualizado: rode "notifications-service migrate up" antes
+	migrator, err := migrations.New(pool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := migrator.Check(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	// Métricas Prometheus (/metrics): HTTP, notificações enviadas e pool do banco
+	appMetrics := metrics.New()
+	appMetrics.Register(metrics.NewPoolCollector(pool))
+
 	// Repositório usando banco próprio
 	notificationRepo := persistence.NewPgNotificationRepository(pool)
 
 	// Use case
-	createUC := app.NewCreateNotificationUseCase(notificationRepo)
+	createUC := app.NewCreateNotificationUseCase(notificationRepo, metrics.NewNotifications(appMetrics))
+
+	handler := api.NewNotificationsHandler(createUC, notificationRepo, authenticator)
+
+	// Readiness: banco próprio do serviço e suas migrações
+	readiness := health.NewChecker("notifications-service", 2*time.Second)
+	readiness.Add("postgres", health.PingCheck(pool))
+	readiness.Add("schema", migrator.Check)
+
+	mux := httpapi.NewServeMux()
+	mux.HandleFunc("/health", healthCheck)
+	// Probes do orquestrador (sem autenticação, como o health check)
+	mux.HandleFunc("/livez", livenessCheck)
+	mux.HandleFunc("/readyz", readinessCheck(readiness))
+	// Métricas para o Prometheus (sem autenticação, como o health check)
+	mux.Handle("/metrics", appMetrics.Handler())
 
-	handler := api.NewNotificationsHandler(createUC, notificationRepo)
+	// Documentação OpenAPI 3 (gerada das anotações com swag) e Swagger UI
+	spec, err := openapi.Handler(docs.SwaggerInfo.ReadDoc())
+	if err != nil {
+		log.Fatal(err)
+	}
+	mux.Handle(openapi.Path, spec)
+	mux.HandleFunc("/swagger/", httpSwagger.Handler(
+		httpSwagger.URL(openapi.Path),
+		httpSwagger.DeepLinking(true),
+		httpSwagger.DocExpansion("none"),
+		httpSwagger.DomID("swagger-ui"),
+	))
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"status":"ok","service":"notifications","type":"microservice"}`))
-	})
 	handler.RegisterRoutes(mux)
 
 	srv := &http.Server{
 		Addr:         ":" + port,
-		Handler:      mux,
+		Handler:      logging.RequestIDMiddleware(tracing.Handler(appMetrics.InstrumentMux(mux), mux)),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
 
-	log.Println("Notifications Service listening on :" + port)
-	log.Println("NOTE: This is a microservice with its own database")
+	slog.Info("notifications service listening", "port", port)
 	log.Fatal(srv.ListenAndServe())
 }
+
+// healthCheck godoc
+// @Summary      Health check
+// @Description  Verifica se a API está funcionando
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  map[string]string
+// @Router       /health [get]
+func healthCheck(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`{"status":"ok","service":"notifications","type":"microservice"}`))
+}
+
+// livenessCheck godoc
+// @Summary      Liveness probe
+// @Description  Indica que o processo está de pé (não verifica dependências)
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  map[string]string
+// @Router       /livez [get]
+func livenessCheck(w http.ResponseWriter, r *http.Request) {
+	health.LiveHandler("notifications-service")(w, r)
+}
+
+// readinessCheck godoc
+// @Summary      Readiness probe
+// @Description  Verifica banco e schema, com status e latência de cada dependência
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  health.Report
+// @Failure      503  {object}  health.Report  "Alguma dependência indisponível"
+// @Router       /readyz [get]
+func readinessCheck(checker *health.Checker) http.HandlerFunc {
+	return checker.ReadyHandler()
+}
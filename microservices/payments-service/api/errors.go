@@ -0,0 +1,25 @@
+package api
+
+import (
+	"fintech-shared/httpapi"
+	"fintech-shared/payments"
+	"fintech-shared/webhooks"
+	"net/http"
+)
+
+// apiErrors é a tabela central que traduz os erros de domínio em respostas;
+// os códigos são estáveis e documentados no README. Erros específicos vêm
+// antes dos genéricos que eles embrulham (ErrNotPendingReview embrulha
+// ErrInvalidTransition).
+var apiErrors = httpapi.ErrorMap{
+	{Err: payments.ErrNotFound, Status: http.StatusNotFound, Code: "payment_not_found"},
+	{Err: payments.ErrInvalidPayment, Status: http.StatusBadRequest, Code: "invalid_payment"},
+	{Err: payments.ErrInvalidAmount, Status: http.StatusBadRequest, Code: "invalid_amount"},
+	{Err: payments.ErrLimitExceeded, Status: http.StatusUnprocessableEntity, Code: "limit_exceeded"},
+	{Err: payments.ErrReviewNotesRequired, Status: http.StatusBadRequest, Code: "review_notes_required"},
+	{Err: payments.ErrNotPendingReview, Status: http.StatusConflict, Code: "payment_not_pending_review"},
+	{Err: payments.ErrReviewExpired, Status: http.StatusConflict, Code: "review_expired"},
+	{Err: payments.ErrInvalidTransition, Status: http.StatusConflict, Code: "invalid_transition"},
+	{Err: webhooks.ErrEndpointNotFound, Status: http.StatusNotFound, Code: "webhook_not_found"},
+	{Err: webhooks.ErrInvalidEndpoint, Status: http.StatusBadRequest, Code: "invalid_webhook"},
+}
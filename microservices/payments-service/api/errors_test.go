@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"fintech-shared/payments"
+	"fintech-shared/webhooks"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Os códigos são contrato com os clientes: mudar um deles é uma quebra de API
+func TestAPIErrors_MapsDomainErrors(t *testing.T) {
+	_, invalidPayer := payments.NewPixPayment("merchant-1", "", 10)
+	_, invalidAmount := payments.NewPixPayment("merchant-1", "payer-1", 0)
+	_, invalidEndpoint := webhooks.NewWebhookEndpoint("merchant-1", "ftp://example.com", "secret", nil)
+	limitErr := payments.DefaultTransactionLimits().Check(1_000_001, 0, time.Now())
+	settled := &payments.PixPayment{Status: payments.StatusSettled}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"not found", fmt.Errorf("find: %w", payments.ErrNotFound), http.StatusNotFound, "payment_not_found"},
+		{"invalid payment", invalidPayer, http.StatusBadRequest, "invalid_payment"},
+		{"invalid amount", invalidAmount, http.StatusBadRequest, "invalid_amount"},
+		{"limit exceeded", limitErr, http.StatusUnprocessableEntity, "limit_exceeded"},
+		{"notes required", payments.ErrReviewNotesRequired, http.StatusBadRequest, "review_notes_required"},
+		{"not pending review", settled.Approve("reviewer", "", time.Now()), http.StatusConflict, "payment_not_pending_review"},
+		{"review expired", payments.ErrReviewExpired, http.StatusConflict, "review_expired"},
+		{"invalid transition", settled.Settle(), http.StatusConflict, "invalid_transition"},
+		{"webhook not found", webhooks.ErrEndpointNotFound, http.StatusNotFound, "webhook_not_found"},
+		{"invalid webhook", invalidEndpoint, http.StatusBadRequest, "invalid_webhook"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, ok := apiErrors.Lookup(tt.err)
+			if !ok {
+				t.Fatalf("%v is not mapped", tt.err)
+			}
+			if mapping.Status != tt.wantStatus || mapping.Code != tt.wantCode {
+				t.Errorf("mapping = %d %s, want %d %s", mapping.Status, mapping.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+
+	// Erros de infraestrutura não estão na tabela: viram 500 sem detalhes
+	if _, ok := apiErrors.Lookup(errors.New("dial tcp: connection refused")); ok {
+		t.Error("infrastructure errors must not be mapped")
+	}
+}
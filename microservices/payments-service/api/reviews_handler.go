@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fintech-shared/auth"
+	"fintech-shared/httpapi"
+	"fintech-shared/logging"
+	"fintech-shared/payments"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// ReviewsHandler expõe a fila de revisão manual do antifraude
+type ReviewsHandler struct {
+	reviewUC *payments.ReviewPaymentUseCase
+	authn    *auth.Authenticator
+}
+
+type reviewDecisionRequest struct {
+	Notes string `json:"notes"`
+}
+
+func NewReviewsHandler(reviewUC *payments.ReviewPaymentUseCase, authn *auth.Authenticator) *ReviewsHandler {
+	return &ReviewsHandler{reviewUC: reviewUC, authn: authn}
+}
+
+func (h *ReviewsHandler) RegisterRoutes(mux httpapi.Mux) {
+	mux.Handle("GET /reviews", h.authn.Middleware(http.HandlerFunc(h.listPending)))
+	mux.Handle("POST /reviews/{id}/approve", h.authn.Middleware(h.decision(h.approve)))
+	mux.Handle("POST /reviews/{id}/reject", h.authn.Middleware(h.decision(h.reject)))
+}
+
+// listPending godoc
+// @Summary      Fila de revisão manual
+// @Description  Lista os pagamentos em PENDING_REVIEW de todos os lojistas, com as regras antifraude que dispararam e o prazo da revisão (mais próximos de expirar primeiro). Exige o escopo payments:review.
+// @Tags         reviews
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Success      200  {array}   payments.PixPayment
+// @Failure      401  {object}  httpapi.Problem
+// @Failure      403  {object}  httpapi.Problem
+// @Failure      500  {object}  httpapi.Problem
+// @Router       /reviews [get]
+func (h *ReviewsHandler) listPending(w http.ResponseWriter, r *http.Request) {
+	if _, ok := auth.Authorize(w, r, auth.ScopePaymentsReview); !ok {
+		return
+	}
+
+	pending, err := h.reviewUC.ListPending(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to list pending reviews", "error", err)
+		apiErrors.Write(w, r, err)
+		return
+	}
+
+	if pending == nil {
+		pending = []*payments.PixPayment{}
+	}
+	writeJSON(w, http.StatusOK, pending)
+}
+
+// approve godoc
+// @Summary      Aprova um pagamento retido
+// @Description  Libera um pagamento em PENDING_REVIEW, que passa pela autorização do BACEN e segue para AUTHORIZED e depois é liquidado (recusado pelo BACEN, volta com status REJECTED). O revisor é a identidade da credencial. Exige o escopo payments:review.
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                    true   "ID do pagamento"
+// @Param        request  body      reviewDecisionRequest  false  "Observações do revisor"
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Success      200      {object}  payments.PixPayment
+// @Failure      400      {object}  httpapi.Problem
+// @Failure      401      {object}  httpapi.Problem
+// @Failure      403      {object}  httpapi.Problem
+// @Failure      404      {object}  httpapi.Problem
+// @Failure      409      {object}  httpapi.Problem  "Pagamento não está aguardando revisão ou o prazo expirou"
+// @Failure      500      {object}  httpapi.Problem
+// @Router       /reviews/{id}/approve [post]
+func (h *ReviewsHandler) approve(w http.ResponseWriter, r *http.Request, id int64, reviewer string, req reviewDecisionRequest) {
+	payment, err := h.reviewUC.Approve(r.Context(), id, reviewer, req.Notes)
+	if err != nil {
+		writeReviewError(w, r, id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, payment)
+}
+
+// reject godoc
+// @Summary      Recusa um pagamento retido
+// @Description  Recusa um pagamento em PENDING_REVIEW (status final REJECTED). A justificativa (notes) é obrigatória. Exige o escopo payments:review.
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                    true  "ID do pagamento"
+// @Param        request  body      reviewDecisionRequest  true  "Justificativa da recusa"
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Success      200      {object}  payments.PixPayment
+// @Failure      400      {object}  httpapi.Problem
+// @Failure      401      {object}  httpapi.Problem
+// @Failure      403      {object}  httpapi.Problem
+// @Failure      404      {object}  httpapi.Problem
+// @Failure      409      {object}  httpapi.Problem  "Pagamento não está aguardando revisão ou o prazo expirou"
+// @Failure      500      {object}  httpapi.Problem
+// @Router       /reviews/{id}/reject [post]
+func (h *ReviewsHandler) reject(w http.ResponseWriter, r *http.Request, id int64, reviewer string, req reviewDecisionRequest) {
+	payment, err := h.reviewUC.Reject(r.Context(), id, reviewer, req.Notes)
+	if err != nil {
+		writeReviewError(w, r, id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, payment)
+}
+
+// reviewDecision é a aprovação ou a recusa, já com o ID, o revisor e o corpo
+type reviewDecision func(w http.ResponseWriter, r *http.Request, id int64, reviewer string, req reviewDecisionRequest)
+
+// decision faz o que é comum às duas decisões (escopo, ID e corpo opcional)
+// antes de chamar decide
+func (h *ReviewsHandler) decision(decide reviewDecision) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.Authorize(w, r, auth.ScopePaymentsReview)
+		if !ok {
+			return
+		}
+
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			httpapi.WriteProblem(w, r, http.StatusBadRequest, httpapi.CodeInvalidID, "invalid payment ID")
+			return
+		}
+
+		// O corpo é opcional na aprovação
+		var req reviewDecisionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			httpapi.WriteProblem(w, r, http.StatusBadRequest, httpapi.CodeInvalidJSON, "invalid json")
+			return
+		}
+
+		decide(w, r, id, principal.Subject, req)
+	}
+}
+
+// writeReviewError registra o erro da revisão e o traduz pela tabela central
+func writeReviewError(w http.ResponseWriter, r *http.Request, id int64, err error) {
+	slog.WarnContext(logging.WithPaymentID(r.Context(), id), "failed to review payment", "error", err)
+	apiErrors.Write(w, r, err)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"fintech-payments-service/docs"
+	"fintech-shared/auth"
+	"fintech-shared/httpapi"
+	"fintech-shared/openapi"
+	"fintech-shared/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Páginas montadas pelos handlers que não fazem parte da API documentada
+var undocumentedPages = map[string]bool{"GET /monitor": true}
+
+// A documentação OpenAPI precisa bater com as rotas que os handlers montam.
+// As rotas de health ficam no main e não passam por RegisterRoutes.
+func TestRegisterRoutes_MatchesOpenAPI(t *testing.T) {
+	routes := openapi.NewRoutes()
+	NewPaymentsHandler(nil, nil, nil, nil, nil).RegisterRoutes(routes)
+	NewWebhooksHandler(nil, nil).RegisterRoutes(routes)
+	NewReviewsHandler(nil, nil).RegisterRoutes(routes)
+
+	all, err := openapi.Operations(docs.SwaggerInfo.ReadDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops []openapi.Operation
+	for _, op := range all {
+		if !op.HasTag("health") {
+			ops = append(ops, op)
+		}
+	}
+
+	unmounted, undocumented := routes.Diff(ops)
+	for _, op := range unmounted {
+		t.Errorf("%s %s is documented but not mounted", op.Method, op.Path)
+	}
+	for _, pattern := range undocumented {
+		if !undocumentedPages[pattern] {
+			t.Errorf("%s is mounted but not documented", pattern)
+		}
+	}
+}
+
+// Cada rota chega ao seu handler (401 sem credencial: o middleware de
+// autenticação é o primeiro da cadeia) e os métodos não montados recebem 405
+// com o Allow do caminho, inclusive nos caminhos que antes se sobrepunham.
+func TestRegisterRoutes_RouteTable(t *testing.T) {
+	store, _ := auth.ParseAPIKeys("")
+	authn := auth.NewAuthenticator(store, nil)
+	mux := httpapi.NewServeMux()
+	NewPaymentsHandler(nil, nil, authn, auth.NewCORSPolicy(""), ratelimit.NewTokenBucketLimiter(1000, 1000)).RegisterRoutes(mux)
+	NewWebhooksHandler(nil, authn).RegisterRoutes(mux)
+	NewReviewsHandler(nil, authn).RegisterRoutes(mux)
+
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+		wantCode   string
+		wantAllow  string
+	}{
+		{http.MethodGet, "/pix", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodPost, "/pix", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodGet, "/pix/42", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodGet, "/pix/monitor/42", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodGet, "/pix/ws", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodGet, "/monitor", http.StatusOK, "", ""},
+		{http.MethodGet, "/reviews", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodPost, "/reviews/42/approve", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodPost, "/reviews/42/reject", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodGet, "/webhooks", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodPost, "/webhooks", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+		{http.MethodGet, "/webhooks/7/deliveries", http.StatusUnauthorized, httpapi.CodeUnauthorized, ""},
+
+		{http.MethodDelete, "/pix", http.StatusMethodNotAllowed, httpapi.CodeMethodNotAllowed, "GET, HEAD, POST"},
+		{http.MethodPost, "/pix/42", http.StatusMethodNotAllowed, httpapi.CodeMethodNotAllowed, "GET, HEAD"},
+		{http.MethodPost, "/pix/monitor/42", http.StatusMethodNotAllowed, httpapi.CodeMethodNotAllowed, "GET, HEAD"},
+		{http.MethodPost, "/monitor", http.StatusMethodNotAllowed, httpapi.CodeMethodNotAllowed, "GET, HEAD"},
+		{http.MethodPost, "/reviews", http.StatusMethodNotAllowed, httpapi.CodeMethodNotAllowed, "GET, HEAD"},
+		{http.MethodGet, "/reviews/42/approve", http.StatusMethodNotAllowed, httpapi.CodeMethodNotAllowed, "POST"},
+		{http.MethodPut, "/webhooks", http.StatusMethodNotAllowed, httpapi.CodeMethodNotAllowed, "GET, HEAD, POST"},
+		{http.MethodDelete, "/webhooks/7/deliveries", http.StatusMethodNotAllowed, httpapi.CodeMethodNotAllowed, "GET, HEAD"},
+
+		{http.MethodGet, "/pix/monitor/", http.StatusNotFound, httpapi.CodeNotFound, ""},
+		{http.MethodGet, "/pix/42/refunds", http.StatusNotFound, httpapi.CodeNotFound, ""},
+		{http.MethodPost, "/reviews/42/cancel", http.StatusNotFound, httpapi.CodeNotFound, ""},
+		{http.MethodGet, "/webhooks/7", http.StatusNotFound, httpapi.CodeNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var problem httpapi.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", problem.Code, tt.wantCode)
+			}
+		})
+	}
+}
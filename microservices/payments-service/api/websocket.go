@@ -0,0 +1,27 @@
+package api
+
+import (
+	"fintech-shared/auth"
+	"net/http"
+)
+
+// monitorWebSocket godoc
+// @Summary      Monitora mudanças de status de pagamentos em tempo real (WebSocket)
+// @Description  Alternativa ao SSE para clientes que não suportam text/event-stream. Uma única conexão pode se inscrever e desinscrever de vários pagamentos enviando {"action":"subscribe|unsubscribe","payment_id":N}. Pagamentos iniciais podem ser informados via query string (payment_id=1&payment_id=2).
+// @Tags         payments
+// @Param        payment_id    query     []int   false  "IDs dos pagamentos para inscrição inicial"  collectionFormat(multi)
+// @Param        access_token  query     string  false  "API key ou JWT (WebSocket no browser não permite headers)"
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Success      101           {string}  string  "Switching Protocols"
+// @Failure      400           {object}  httpapi.Problem
+// @Failure      401           {object}  httpapi.Problem
+// @Failure      403           {object}  httpapi.Problem
+// @Router       /pix/ws [get]
+func (h *PaymentsHandler) monitorWebSocket(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.AuthorizeMerchant(w, r, auth.ScopePaymentsRead)
+	if !ok {
+		return
+	}
+	h.ws.Serve(w, r, principal.MerchantID)
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"fintech-shared/migrate"
+	"fintech-shared/migrate/migratetest"
+	"fintech-shared/payments"
+	"fintech-shared/payments/paymentstest"
+	"fintech-shared/pgtest"
+	"fintech-shared/postgres"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pgtest.Run(m))
+}
+
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	migrations, err := migrate.Load(files, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+	// Versões sequenciais a partir de 1, sem buracos
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d has version %d, want %d", i, m.Version, i+1)
+		}
+	}
+}
+
+// schemaHeader abre o db/init-payments.sql, gerado a partir das migrações
+const schemaHeader = `-- Banco de dados próprio do serviço de pagamentos
+-- Autonomia de dados: apenas este serviço acessa este banco
+--
+-- NOTE: Cada microsserviço tem seu próprio banco de dados
+-- Isso garante autonomia e evita acoplamento
+--
+-- Schema completo de referência, GERADO a partir das migrações em
+-- microservices/payments-service/infra/migrations/sql (a fonte da verdade, aplicadas com
+-- "migrate up"). Não edite: rode "go test ./infra/migrations -update-schema".
+`
+
+func TestSchemaFile(t *testing.T) {
+	migratetest.CheckSchemaFile(t, files, "sql", "init-payments.sql", schemaHeader)
+}
+
+// A suíte do repositório compartilhado com o schema montado pelas migrações
+// embutidas no binário (em fintech-shared ela roda contra o init gerado)
+func TestMigrations_PixPaymentRepository(t *testing.T) {
+	pgtest.DSN(t) // Sem servidor, pula a suíte inteira de uma vez
+	paymentstest.RunRepositoryTests(t, func(t *testing.T) payments.PixPaymentRepository {
+		return postgres.NewPgPixPaymentRepository(migratetest.NewPool(t, New))
+	})
+}
@@ -0,0 +1,26 @@
+-- Webhooks dos lojistas (callbacks de mudança de status dos pagamentos)
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+  id BIGSERIAL PRIMARY KEY,
+  merchant_id TEXT NOT NULL,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  event_types TEXT[] NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Log de entregas: uma linha por tentativa
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id BIGSERIAL PRIMARY KEY,
+  endpoint_id BIGINT NOT NULL REFERENCES webhook_endpoints(id),
+  payment_id BIGINT NOT NULL,
+  event_type TEXT NOT NULL,
+  attempt INT NOT NULL,
+  status_code INT NOT NULL,
+  success BOOLEAN NOT NULL,
+  error TEXT NOT NULL DEFAULT '',
+  next_retry_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_merchant ON webhook_endpoints (merchant_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint ON webhook_deliveries (endpoint_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pix_payments_merchant;
+ALTER TABLE pix_payments DROP COLUMN IF EXISTS merchant_id;
@@ -0,0 +1,4 @@
+-- Pagador, para o limite diário por pagador
+ALTER TABLE pix_payments ADD COLUMN IF NOT EXISTS payer_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_pix_payments_payer ON pix_payments (payer_id, created_at);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_pix_payments_pending_review;
+ALTER TABLE pix_payments
+  DROP COLUMN IF EXISTS reviewed_at,
+  DROP COLUMN IF EXISTS review_notes,
+  DROP COLUMN IF EXISTS reviewed_by,
+  DROP COLUMN IF EXISTS review_decision,
+  DROP COLUMN IF EXISTS review_deadline;
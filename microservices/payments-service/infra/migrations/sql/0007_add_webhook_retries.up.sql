@@ -0,0 +1,7 @@
+-- Retentativas de webhook persistidas: a linha com next_retry_at guarda o corpo
+-- do evento e o worker de retentativas a reenvia quando vence
+ALTER TABLE webhook_deliveries
+  ADD COLUMN IF NOT EXISTS payload BYTEA, -- Corpo enviado, só nas linhas com retentativa agendada
+  ADD COLUMN IF NOT EXISTS retried_at TIMESTAMPTZ; -- Quando a tentativa seguinte foi registrada
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_retry_at) WHERE retried_at IS NULL;
@@ -0,0 +1,129 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fintech-shared/payments"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen é retornado sem chamar o serviço enquanto o circuito está aberto
+var ErrCircuitOpen = errors.New("notifications circuit breaker is open")
+
+// Estados do circuit breaker, reportados no /readyz
+const (
+	CircuitClosed   = "closed"    // Chamadas passam normalmente
+	CircuitOpen     = "open"      // Chamadas falham na hora até o fim do cooldown
+	CircuitHalfOpen = "half_open" // Uma chamada de teste decide se o circuito fecha
+)
+
+// CircuitBreaker abre o circuito depois de threshold falhas seguidas e, passado
+// o cooldown, deixa uma única chamada de teste passar: sucesso fecha o circuito,
+// falha o abre de novo. Assim um serviço de notificações fora do ar não prende
+// cada pagamento no timeout do cliente HTTP.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	clock     payments.Clock
+
+	mu       sync.Mutex
+	state    string
+	failures int       // Falhas seguidas com o circuito fechado
+	openedAt time.Time // Quando o circuito abriu pela última vez
+	probing  bool      // Chamada de teste em andamento (half_open)
+}
+
+func NewCircuitBreaker(threshold int, cooldown time.Duration, clock payments.Clock) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, clock: clock, state: CircuitClosed}
+}
+
+// State retorna o estado atual; um circuito aberto com o cooldown vencido já
+// é reportado como half_open
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.cooldownElapsed() {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// Allow diz se a chamada pode seguir. Cada Allow que retorna nil deve ser
+// seguido de um Record com o resultado da chamada, ou de um Release se ela
+// terminou sem resultado.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		if !b.cooldownElapsed() {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return nil
+	case CircuitHalfOpen:
+		// Só uma chamada de teste por vez
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	}
+	return nil
+}
+
+// Record registra o resultado de uma chamada liberada por Allow
+func (b *CircuitBreaker) Record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitHalfOpen {
+		b.probing = false
+		if failed {
+			b.open()
+		} else {
+			b.state = CircuitClosed
+			b.failures = 0
+		}
+		return
+	}
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.open()
+	}
+}
+
+// Release devolve uma chamada liberada por Allow que terminou sem dizer nada
+// sobre o serviço (cancelada pelo chamador): o estado e as falhas seguidas
+// ficam como estavam, e no half_open outra chamada de teste pode passar
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitHalfOpen {
+		b.probing = false
+	}
+}
+
+// Check reporta o circuito aberto como dependência indisponível no /readyz
+func (b *CircuitBreaker) Check(ctx context.Context) error {
+	if state := b.State(); state == CircuitOpen {
+		return fmt.Errorf("circuit %s after %d consecutive failures", state, b.threshold)
+	}
+	return nil
+}
+
+func (b *CircuitBreaker) open() {
+	b.state = CircuitOpen
+	b.openedAt = b.clock.Now()
+	b.failures = 0
+}
+
+func (b *CircuitBreaker) cooldownElapsed() bool {
+	return b.clock.Now().Sub(b.openedAt) >= b.cooldown
+}
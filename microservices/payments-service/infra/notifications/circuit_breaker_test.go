@@ -0,0 +1,162 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fintech-shared/auth"
+	"fintech-shared/payments"
+	"fintech-shared/payments/paymentstest"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := paymentstest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	breaker := NewCircuitBreaker(3, 30*time.Second, clock)
+
+	// Um sucesso zera as falhas seguidas
+	for _, failed := range []bool{true, true, false, true, true} {
+		if err := breaker.Allow(); err != nil {
+			t.Fatalf("Allow() = %v with the circuit closed", err)
+		}
+		breaker.Record(failed)
+	}
+	if state := breaker.State(); state != CircuitClosed {
+		t.Fatalf("state = %s after 2 consecutive failures, want %s", state, CircuitClosed)
+	}
+
+	breaker.Record(true)
+	if state := breaker.State(); state != CircuitOpen {
+		t.Fatalf("state = %s after 3 consecutive failures, want %s", state, CircuitOpen)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow() = %v, want ErrCircuitOpen", err)
+	}
+	if err := breaker.Check(context.Background()); err == nil {
+		t.Error("Check() = nil with the circuit open")
+	}
+
+	// Passado o cooldown, só uma chamada de teste; a falha dela reabre o circuito
+	clock.Advance(30 * time.Second)
+	if state := breaker.State(); state != CircuitHalfOpen {
+		t.Fatalf("state = %s after the cooldown, want %s", state, CircuitHalfOpen)
+	}
+	if err := breaker.Check(context.Background()); err != nil {
+		t.Errorf("Check() = %v with the circuit half open", err)
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("probe Allow() = %v", err)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second Allow() during the probe = %v, want ErrCircuitOpen", err)
+	}
+	breaker.Record(true)
+	if state := breaker.State(); state != CircuitOpen {
+		t.Fatalf("state = %s after a failed probe, want %s", state, CircuitOpen)
+	}
+
+	// Chamada de teste com sucesso fecha o circuito
+	clock.Advance(30 * time.Second)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("probe Allow() = %v", err)
+	}
+	breaker.Record(false)
+	if state := breaker.State(); state != CircuitClosed {
+		t.Fatalf("state = %s after a successful probe, want %s", state, CircuitClosed)
+	}
+}
+
+// Chamada cancelada pelo chamador: não fecha o circuito nem zera as falhas
+func TestCircuitBreaker_Release(t *testing.T) {
+	clock := paymentstest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	breaker := NewCircuitBreaker(2, 30*time.Second, clock)
+
+	_ = breaker.Allow()
+	breaker.Record(true)
+	_ = breaker.Allow()
+	breaker.Release()
+	_ = breaker.Allow()
+	breaker.Record(true)
+	if state := breaker.State(); state != CircuitOpen {
+		t.Fatalf("state = %s, want %s: a released call must not reset the failures", state, CircuitOpen)
+	}
+
+	clock.Advance(30 * time.Second)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("probe Allow() = %v", err)
+	}
+	breaker.Release()
+	if state := breaker.State(); state != CircuitHalfOpen {
+		t.Fatalf("state = %s after a released probe, want %s", state, CircuitHalfOpen)
+	}
+	// A chamada de teste foi devolvida: a próxima pode testar
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after a released probe = %v", err)
+	}
+}
+
+func TestHTTPNotificationClient_CircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	tokens, err := auth.NewServiceTokenSigner([]byte("0123456789abcdef0123456789abcdef"), "payments-service", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := paymentstest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	metrics := &fakeMetrics{}
+	client := NewHTTPNotificationClient(server.URL, tokens, metrics)
+	breaker := NewCircuitBreaker(2, time.Minute, clock)
+	client.SetCircuitBreaker(breaker)
+
+	// 4xx é erro da requisição: não conta para abrir o circuito
+	status = http.StatusBadRequest
+	for i := 0; i < 3; i++ {
+		_ = client.Notify(context.Background(), &payments.PixPayment{ID: 1, Amount: 10}, payments.NotificationCreated, "")
+	}
+	if state := breaker.State(); state != CircuitClosed {
+		t.Fatalf("state = %s after 4xx responses, want %s", state, CircuitClosed)
+	}
+
+	// Duas respostas 5xx abrem o circuito e a terceira chamada nem sai
+	status = http.StatusServiceUnavailable
+	_ = client.Notify(context.Background(), &payments.PixPayment{ID: 1, Amount: 10}, payments.NotificationCreated, "")
+	_ = client.Notify(context.Background(), &payments.PixPayment{ID: 1, Amount: 10}, payments.NotificationCreated, "")
+	if err := client.Notify(context.Background(), &payments.PixPayment{ID: 1, Amount: 10}, payments.NotificationCreated, ""); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("send with the circuit open = %v, want ErrCircuitOpen", err)
+	}
+	if got := requests.Load(); got != 5 {
+		t.Errorf("requests = %d, want 5", got)
+	}
+	if last := metrics.calls[len(metrics.calls)-1]; last.outcome != OutcomeOpen {
+		t.Errorf("last outcome = %s, want %s", last.outcome, OutcomeOpen)
+	}
+
+	// Chamada de teste cancelada pelo chamador: o circuito continua half_open
+	clock.Advance(time.Minute)
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.Notify(canceled, &payments.PixPayment{ID: 1, Amount: 10}, payments.NotificationCreated, ""); err == nil {
+		t.Fatal("send with a canceled context succeeded")
+	}
+	if state := breaker.State(); state != CircuitHalfOpen {
+		t.Fatalf("state = %s after a canceled probe, want %s", state, CircuitHalfOpen)
+	}
+
+	// Com o serviço de volta, a chamada de teste fecha o circuito
+	status = http.StatusCreated
+	if err := client.Notify(context.Background(), &payments.PixPayment{ID: 1, Amount: 10}, payments.NotificationCreated, ""); err != nil {
+		t.Fatal(err)
+	}
+	if state := breaker.State(); state != CircuitClosed {
+		t.Errorf("state = %s after a successful probe, want %s", state, CircuitClosed)
+	}
+}
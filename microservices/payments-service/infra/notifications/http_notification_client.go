@@ -1,71 +1,142 @@
 package notifications
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
+	"context"
+	"fintech-shared/auth"
+	"fintech-shared/logging"
+	"fintech-shared/notificationsapi"
+	"fintech-shared/payments"
+	"fintech-shared/tracing"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("fintech-payments-service/infra/notifications")
+
+// Resultados das chamadas, registrados em ClientMetrics
+const (
+	OutcomeSuccess  = "success"
+	OutcomeRejected = "rejected" // O serviço respondeu com status de erro
+	OutcomeError    = "error"    // Falha antes da resposta (rede, timeout, token)
+	OutcomeOpen     = "open"     // Não enviada: circuit breaker aberto
 )
 
+// ClientMetrics registra o resultado e a duração de cada chamada
+type ClientMetrics interface {
+	NotificationCall(notificationType, outcome string, elapsed time.Duration)
+}
+
 // HTTPNotificationClient implementa comunicação síncrona via HTTP
 // com o serviço de notificações
 type HTTPNotificationClient struct {
-	baseURL    string
-	httpClient *http.Client
+	api     *notificationsapi.Client
+	tokens  *auth.ServiceTokenSigner
+	metrics ClientMetrics
+	breaker *CircuitBreaker
 }
 
-func NewHTTPNotificationClient(baseURL string) *HTTPNotificationClient {
+// NewHTTPNotificationClient cria o cliente; metrics pode ser nil
+func NewHTTPNotificationClient(baseURL string, tokens *auth.ServiceTokenSigner, metrics ClientMetrics) *HTTPNotificationClient {
 	return &HTTPNotificationClient{
-		baseURL: baseURL,
-		httpClient: &http.Client{
+		api: notificationsapi.NewClient(baseURL, &http.Client{
 			Timeout: 5 * time.Second,
-		},
+			// Injeta o traceparent: o trace continua no serviço de notificações
+			Transport: tracing.Transport(http.DefaultTransport),
+		}),
+		tokens:  tokens,
+		metrics: metrics,
 	}
 }
 
-type notificationRequest struct {
-	PaymentID int64   `json:"payment_id"`
-	Amount    float64 `json:"amount"`
-	Type      string  `json:"type"`
+// SetCircuitBreaker protege as chamadas com o circuit breaker; sem ele toda
+// chamada vai ao serviço. Deve ser chamado antes do primeiro envio.
+func (c *HTTPNotificationClient) SetCircuitBreaker(breaker *CircuitBreaker) {
+	c.breaker = breaker
 }
 
-func (c *HTTPNotificationClient) SendPaymentCreatedNotification(paymentID int64, amount float64) error {
-	return c.sendNotification(paymentID, amount, "PAYMENT_CREATED")
+// Notify implementa payments.Notifier: envia a etapa do pagamento ao
+// notifications-service. A mensagem é montada pelo serviço de notificações; o
+// tipo do fluxo tem os mesmos valores do contrato.
+func (c *HTTPNotificationClient) Notify(ctx context.Context, payment *payments.PixPayment, notificationType payments.NotificationType, message string) error {
+	return c.sendNotification(ctx, payment.ID, payment.Amount, notificationsapi.NotificationType(notificationType))
 }
 
-func (c *HTTPNotificationClient) SendPaymentAuthorizedNotification(paymentID int64, amount float64) error {
-	return c.sendNotification(paymentID, amount, "PAYMENT_AUTHORIZED")
-}
-
-func (c *HTTPNotificationClient) SendPaymentSettledNotification(paymentID int64, amount float64) error {
-	return c.sendNotification(paymentID, amount, "PAYMENT_SETTLED")
-}
+func (c *HTTPNotificationClient) sendNotification(ctx context.Context, paymentID int64, amount float64, notificationType notificationsapi.NotificationType) error {
+	ctx, span := tracer.Start(ctx, "notifications.Send", trace.WithAttributes(
+		attribute.String("notification.type", string(notificationType)),
+		attribute.Int64("payment.id", paymentID),
+	))
+	defer span.End()
 
-func (c *HTTPNotificationClient) sendNotification(paymentID int64, amount float64, notificationType string) error {
-	reqBody := notificationRequest{
-		PaymentID: paymentID,
-		Amount:    amount,
-		Type:      notificationType,
+	start := time.Now()
+	if c.breaker != nil {
+		if err := c.breaker.Allow(); err != nil {
+			tracing.Fail(span, err)
+			if c.metrics != nil {
+				c.metrics.NotificationCall(string(notificationType), OutcomeOpen, time.Since(start))
+			}
+			return err
+		}
 	}
-
-	jsonData, err := json.Marshal(reqBody)
+	statusCode, err := c.post(ctx, paymentID, amount, notificationType)
 	if err != nil {
-		return err
+		tracing.Fail(span, err)
+	}
+	if c.breaker != nil {
+		if ctx.Err() != nil {
+			// Cancelada pelo chamador: nem sucesso nem falha do serviço
+			c.breaker.Release()
+		} else {
+			c.breaker.Record(breakerFailure(statusCode, err))
+		}
 	}
+	if c.metrics != nil {
+		outcome := OutcomeSuccess
+		switch {
+		case err != nil && statusCode != 0:
+			outcome = OutcomeRejected
+		case err != nil:
+			outcome = OutcomeError
+		}
+		c.metrics.NotificationCall(string(notificationType), outcome, time.Since(start))
+	}
+	return err
+}
 
-	url := fmt.Sprintf("%s/notifications", c.baseURL)
-	resp, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		// Em produção, isso deveria ser logado e possivelmente retentado
-		// Por enquanto, apenas retornamos o erro (eventual consistency)
-		return err
+// breakerFailure diz se o erro indica o serviço indisponível. Um 4xx é erro da
+// requisição: o serviço respondeu, e isso não abre o circuito.
+func breakerFailure(statusCode int, err error) bool {
+	if err == nil {
+		return false
 	}
-	defer resp.Body.Close()
+	return statusCode == 0 || statusCode >= http.StatusInternalServerError
+}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("notification service returned status %d", resp.StatusCode)
+// post envia a notificação e retorna o status HTTP (0 se não houve resposta)
+func (c *HTTPNotificationClient) post(ctx context.Context, paymentID int64, amount float64, notificationType notificationsapi.NotificationType) (int, error) {
+	// Token novo a cada chamada: vida curta e válido só para o serviço de notificações
+	// Audience e escopo vêm do auth: os mesmos que o notifications-service exige
+	token, err := c.tokens.Sign(auth.NotificationsAudience, auth.ScopeNotificationsWrite)
+	if err != nil {
+		return 0, err
 	}
 
-	return nil
+	// Em caso de erro, por enquanto apenas o retornamos (eventual consistency);
+	// em produção, isso deveria ser logado e possivelmente retentado
+	return c.api.CreateNotification(ctx, notificationsapi.CreateNotificationRequest{
+		PaymentID: paymentID,
+		Amount:    amount,
+		Type:      notificationType,
+	}, func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		// Propaga o ID de correlação: os logs dos dois serviços ficam ligados
+		if requestID := logging.RequestID(ctx); requestID != "" {
+			req.Header.Set(logging.RequestIDHeader, requestID)
+		}
+		return nil
+	})
 }
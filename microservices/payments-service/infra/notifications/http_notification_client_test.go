@@ -0,0 +1,119 @@
+package notifications
+
+import (
+	"context"
+	"fintech-shared/auth"
+	"fintech-shared/logging"
+	"fintech-shared/notificationsapi/contracttest"
+	"fintech-shared/payments"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPNotificationClient_PropagatesRequestID(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(logging.RequestIDHeader)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	tokens, err := auth.NewServiceTokenSigner([]byte("0123456789abcdef0123456789abcdef"), "payments-service", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewHTTPNotificationClient(server.URL, tokens, nil)
+
+	ctx := logging.WithRequestID(context.Background(), "req-123")
+	if err := client.Notify(ctx, &payments.PixPayment{ID: 1, Amount: 10}, payments.NotificationCreated, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got != "req-123" {
+		t.Errorf("X-Request-ID = %q, want req-123", got)
+	}
+
+	// Sem request_id no contexto o header não é enviado
+	if err := client.Notify(context.Background(), &payments.PixPayment{ID: 1, Amount: 10}, payments.NotificationCreated, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got != "" {
+		t.Errorf("X-Request-ID = %q, want empty", got)
+	}
+}
+
+type recordedCall struct {
+	notificationType, outcome string
+}
+
+type fakeMetrics struct {
+	calls []recordedCall
+}
+
+func (m *fakeMetrics) NotificationCall(notificationType, outcome string, elapsed time.Duration) {
+	m.calls = append(m.calls, recordedCall{notificationType, outcome})
+}
+
+func TestHTTPNotificationClient_RecordsOutcomes(t *testing.T) {
+	status := http.StatusCreated
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	tokens, err := auth.NewServiceTokenSigner([]byte("0123456789abcdef0123456789abcdef"), "payments-service", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics := &fakeMetrics{}
+	client := NewHTTPNotificationClient(server.URL, tokens, metrics)
+
+	_ = client.Notify(context.Background(), &payments.PixPayment{ID: 1, Amount: 10}, payments.NotificationSettled, "")
+	status = http.StatusServiceUnavailable
+	_ = client.Notify(context.Background(), &payments.PixPayment{ID: 1, Amount: 10}, payments.NotificationSettled, "")
+	server.Close()
+	_ = client.Notify(context.Background(), &payments.PixPayment{ID: 1, Amount: 10}, payments.NotificationSettled, "")
+
+	want := []recordedCall{
+		{"PAYMENT_SETTLED", OutcomeSuccess},
+		{"PAYMENT_SETTLED", OutcomeRejected},
+		{"PAYMENT_SETTLED", OutcomeError},
+	}
+	if len(metrics.calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", metrics.calls, want)
+	}
+	for i := range want {
+		if metrics.calls[i] != want[i] {
+			t.Errorf("call %d = %v, want %v", i, metrics.calls[i], want[i])
+		}
+	}
+}
+
+// Teste de contrato do lado consumidor: o cliente precisa enviar exatamente o
+// que o pact registra e aceitar o status que o provedor promete. Se o pact
+// mudar, o notifications-service verifica a nova versão em api/.
+func TestHTTPNotificationClient_HonorsNotificationsPact(t *testing.T) {
+	provider := contracttest.NewMockProvider(t, contracttest.PaymentsService())
+
+	tokens, err := auth.NewServiceTokenSigner([]byte("0123456789abcdef0123456789abcdef"), "payments-service", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewHTTPNotificationClient(provider.URL, tokens, nil)
+
+	ctx := context.Background()
+	// Todas as etapas do fluxo: os tipos de payments precisam existir no contrato
+	payment := &payments.PixPayment{ID: 42, Amount: 150.5}
+	for _, notificationType := range []payments.NotificationType{
+		payments.NotificationCreated,
+		payments.NotificationPendingReview,
+		payments.NotificationRejected,
+		payments.NotificationAuthorized,
+		payments.NotificationSettled,
+	} {
+		if err := client.Notify(ctx, payment, notificationType, ""); err != nil {
+			t.Errorf("%s: %v", notificationType, err)
+		}
+	}
+	provider.Verify(t)
+}
@@ -2,20 +2,80 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fintech-payments-service/api"
-	app "fintech-payments-service/application"
+	"fintech-payments-service/docs"
 	"fintech-payments-service/infra/messaging/pix"
+	"fintech-payments-service/infra/migrations"
 	"fintech-payments-service/infra/notifications"
-	"fintech-payments-service/infra/persistence"
+	"fintech-shared/auth"
+	"fintech-shared/health"
+	"fintech-shared/httpapi"
+	"fintech-shared/logging"
+	"fintech-shared/metrics"
+	"fintech-shared/migrate"
+	"fintech-shared/openapi"
+	"fintech-shared/payments"
+	"fintech-shared/postgres"
+	"fintech-shared/ratelimit"
+	"fintech-shared/tracing"
+	"fintech-shared/webhooks"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+//go:generate swag init -g main.go -d .,./api --parseDependency -o docs
+
+// @title           Fintech Payments Service API
+// @version         1.0
+// @description     Microsserviço de pagamentos PIX, com banco próprio. As notificações são enviadas ao notifications-service por HTTP.
+
+// @contact.name   Fintech Dev
+// @contact.url    https://github.com/fintechdev
+
+// @host      localhost:8081
+// @BasePath  /
+
+// @schemes   http
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 API key do lojista
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 JWT (HS256 ou RS256) no formato "Bearer {token}", com as claims merchant_id e scope
+
 func main() {
+	// Logs estruturados em JSON (nível em LOG_LEVEL)
+	logging.Setup("payments-service")
+
+	// Subcomando "migrate up|down|status": gerencia o schema e encerra
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate.RunCommand("payments-service", os.Args[2:], migrations.New); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Tracing distribuído (OpenTelemetry), exportado conforme OTEL_TRACES_EXPORTER
+	shutdownTracing, err := tracing.Setup(context.Background(), "payments-service")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { _ = shutdownTracing(context.Background()) }()
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -31,6 +91,48 @@ func main() {
 		notificationServiceURL = "http://notifications-service:8080"
 	}
 
+	// Autenticação por API key e/ou JWT (ver fintech-shared/auth)
+	authenticator, err := auth.NewAuthenticatorFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	cors := auth.NewCORSPolicy(os.Getenv("CORS_ALLOWED_ORIGINS"))
+
+	// Rate limit por credencial/IP em /pix (token bucket)
+	limiter := ratelimit.NewTokenBucketLimiter(envFloat("RATE_LIMIT_RPS", 5), int(envFloat("RATE_LIMIT_BURST", 10)))
+
+	// Limites de negócio do PIX (valores em R$; 0 desabilita o limite)
+	limits := payments.DefaultTransactionLimits()
+	limits.MaxPerTransaction = envFloat("PIX_MAX_PER_TRANSACTION", limits.MaxPerTransaction)
+	limits.DailyPerPayer = envFloat("PIX_DAILY_LIMIT_PER_PAYER", limits.DailyPerPayer)
+	limits.NightlyPerTransaction = envFloat("PIX_NIGHTLY_LIMIT", limits.NightlyPerTransaction)
+
+	// Regras antifraude (YAML); sem FRAUD_RULES_FILE valem as regras padrão
+	fraudRules, err := payments.LoadFraudRules(os.Getenv("FRAUD_RULES_FILE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Tokens de serviço assinados para as chamadas internas ao serviço de notificações
+	serviceTokens, err := auth.NewServiceTokenSigner([]byte(os.Getenv("SERVICE_TOKEN_SECRET")), "payments-service", time.Minute)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Prazo da revisão manual: sem decisão até lá, o pagamento retido é recusado
+	reviewDeadline := envDuration("REVIEW_DEADLINE", 30*time.Minute)
+
+	// Perfil de simulação: esperas do fluxo e latência do BACEN. demo (padrão)
+	// deixa cada etapa visível no monitor; instant e load servem aos testes
+	simulationProfile, err := payments.LoadProfile(os.Getenv("SIMULATION_PROFILE"), os.Getenv("SIMULATION_FILE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Cancelado no SIGTERM/SIGINT: para os laços em background e o servidor
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -41,40 +143,177 @@ func main() {
 	}
 	defer pool.Close()
 
+	// Não sobe com o schema desatualizado: rode "payments-service migrate up" antes
+	migrator, err := migrations.New(pool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := migrator.Check(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	// Métricas Prometheus (/metrics): HTTP, fluxo de pagamento, eventos,
+	// chamadas ao serviço de notificações e pool do banco
+	appMetrics := metrics.New()
+	appMetrics.Register(metrics.NewPoolCollector(pool))
+	paymentMetrics := metrics.NewPayments(appMetrics)
+	api.GetBroadcaster().SetMetrics(paymentMetrics)
+
 	// Repositório usando banco próprio
-	paymentRepo := persistence.NewPgPixPaymentRepository(pool)
+	paymentRepo := postgres.NewPgPixPaymentRepository(pool)
 
 	// Cliente HTTP para comunicação com serviço de notificações
-	notificationClient := notifications.NewHTTPNotificationClient(notificationServiceURL)
+	notificationClient := notifications.NewHTTPNotificationClient(notificationServiceURL, serviceTokens, metrics.NewNotificationClient(appMetrics))
+	// Circuit breaker: com o serviço de notificações fora do ar, os pagamentos não
+	// esperam o timeout do cliente a cada notificação
+	notificationBreaker := notifications.NewCircuitBreaker(
+		int(envFloat("NOTIFICATIONS_BREAKER_THRESHOLD", 5)),
+		envDuration("NOTIFICATIONS_BREAKER_COOLDOWN", 30*time.Second),
+		payments.SystemClock{},
+	)
+	notificationClient.SetCircuitBreaker(notificationBreaker)
 
 	// Gateway do BACEN (simulação)
-	gateway := pix.NewBacenPixGateway()
+	gateway := pix.NewBacenPixGateway(simulationProfile.Gateway, payments.SystemClock{})
 
-	// Event broadcaster para observabilidade em tempo real
-	eventBroadcaster := api.GetBroadcaster()
+	// Webhooks dos lojistas: consomem os mesmos eventos do SSE. As retentativas
+	// ficam em webhook_deliveries e são reenviadas a cada WEBHOOK_RETRY_INTERVAL
+	webhookRepo := postgres.NewPgWebhookRepository(pool)
+	webhookDispatcher := webhooks.NewWebhookDispatcher(webhookRepo, 5, 2*time.Second, payments.SystemClock{})
+	webhookDispatcher.SetMetrics(paymentMetrics)
+	webhookDispatcher.Start(shutdownCtx, 4, envDuration("WEBHOOK_RETRY_INTERVAL", time.Second))
+
+	// Event broadcaster para observabilidade em tempo real (SSE/WebSocket) e webhooks
+	eventBroadcaster := payments.EventBroadcasters{api.GetBroadcaster(), webhookDispatcher}
 
 	// Use case que usa o cliente de notificações, gateway e event broadcaster
-	createUC := app.NewCreatePixPaymentUseCase(paymentRepo, notificationClient, gateway, eventBroadcaster)
+	fraudChecker := payments.NewRulesFraudChecker(fraudRules, paymentRepo, payments.SystemClock{})
+	createUC := payments.NewCreatePixPaymentUseCase(paymentRepo, notificationClient, gateway, eventBroadcaster, limits, fraudChecker, reviewDeadline, paymentMetrics, simulationProfile.Flow, payments.SystemClock{})
+	reviewUC := payments.NewReviewPaymentUseCase(paymentRepo, createUC)
+	reviewUC.StartExpiry(shutdownCtx, envDuration("REVIEW_EXPIRY_INTERVAL", 30*time.Second))
+
+	handler := api.NewPaymentsHandler(createUC, paymentRepo, authenticator, cors, limiter)
+	webhooksHandler := api.NewWebhooksHandler(webhookRepo, authenticator)
+	reviewsHandler := api.NewReviewsHandler(reviewUC, authenticator)
+
+	// Readiness: banco, migrações, serviço de notificações e o circuit breaker do cliente.
+	// Do serviço de notificações basta o /livez: o /readyz dele não deve derrubar a
+	// prontidão deste em cascata
+	readiness := health.NewChecker("payments-service", 2*time.Second)
+	readiness.Add("postgres", health.PingCheck(pool))
+	readiness.Add("schema", migrator.Check)
+	readiness.Add("notifications-service", health.HTTPCheck(&http.Client{Timeout: 2 * time.Second}, notificationServiceURL+"/livez"))
+	readiness.Add("notifications-circuit", notificationBreaker.Check)
+
+	mux := httpapi.NewServeMux()
+	mux.HandleFunc("/health", healthCheck)
+	// Probes do orquestrador (sem autenticação, como o health check)
+	mux.HandleFunc("/livez", livenessCheck)
+	mux.HandleFunc("/readyz", readinessCheck(readiness))
+	// Métricas para o Prometheus (sem autenticação, como o health check)
+	mux.Handle("/metrics", appMetrics.Handler())
 
-	handler := api.NewPaymentsHandler(createUC, paymentRepo)
+	// Documentação OpenAPI 3 (gerada das anotações com swag) e Swagger UI
+	spec, err := openapi.Handler(docs.SwaggerInfo.ReadDoc())
+	if err != nil {
+		log.Fatal(err)
+	}
+	mux.Handle(openapi.Path, spec)
+	mux.HandleFunc("/swagger/", httpSwagger.Handler(
+		httpSwagger.URL(openapi.Path),
+		httpSwagger.DeepLinking(true),
+		httpSwagger.DocExpansion("none"),
+		httpSwagger.DomID("swagger-ui"),
+	))
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"status":"ok","service":"payments","type":"microservice"}`))
-	})
 	handler.RegisterRoutes(mux)
+	webhooksHandler.RegisterRoutes(mux)
+	reviewsHandler.RegisterRoutes(mux)
 
 	srv := &http.Server{
 		Addr:         ":" + port,
-		Handler:      mux,
+		Handler:      logging.RequestIDMiddleware(tracing.Handler(cors.Middleware(appMetrics.InstrumentMux(mux)), mux)),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
 
-	log.Println("Payments Service listening on :" + port)
-	log.Println("Notification Service URL:", notificationServiceURL)
-	log.Println("NOTE: This is a microservice with its own database")
-	log.Fatal(srv.ListenAndServe())
+	slog.Info("payments service listening", "port", port, "notification_service_url", notificationServiceURL)
+	// Shutdown espera as requisições em andamento (até 10s; streams SSE não terminam sozinhos)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-shutdownCtx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			slog.Error("graceful shutdown failed", "error", err)
+		}
+	}()
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	<-stopped
+	slog.Info("payments service stopped")
+}
+
+// envFloat lê um número da variável de ambiente, com valor padrão
+func envFloat(name string, def float64) float64 {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", name, err)
+	}
+	return value
+}
+
+// envDuration lê uma duração (ex.: "30m") da variável de ambiente, com valor padrão
+func envDuration(name string, def time.Duration) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		log.Fatalf("invalid %s: %q", name, raw)
+	}
+	return value
+}
+
+// healthCheck godoc
+// @Summary      Health check
+// @Description  Verifica se a API está funcionando
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  map[string]string
+// @Router       /health [get]
+func healthCheck(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`{"status":"ok","service":"payments","type":"microservice"}`))
+}
+
+// livenessCheck godoc
+// @Summary      Liveness probe
+// @Description  Indica que o processo está de pé (não verifica dependências)
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  map[string]string
+// @Router       /livez [get]
+func livenessCheck(w http.ResponseWriter, r *http.Request) {
+	health.LiveHandler("payments-service")(w, r)
+}
+
+// readinessCheck godoc
+// @Summary      Readiness probe
+// @Description  Verifica banco, schema e o serviço de notificações, com status e latência de cada dependência
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  health.Report
+// @Failure      503  {object}  health.Report  "Alguma dependência indisponível"
+// @Router       /readyz [get]
+func readinessCheck(checker *health.Checker) http.HandlerFunc {
+	return checker.ReadyHandler()
 }
@@ -44,42 +44,678 @@ const docTemplate = `{
                 }
             }
         },
+        "/livez": {
+            "get": {
+                "description": "Indica que o processo está de pé (não verifica dependências)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/payments/pix": {
             "get": {
-                "description": "Retorna uma lista de todos os pagamentos PIX criados, ordenados por data de criação (mais recentes primeiro)",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna uma lista de todos os pagamentos PIX do lojista autenticado, ordenados por data de criação (mais recentes primeiro)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Lista todos os pagamentos PIX",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/payments.PixPayment"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cria um novo pagamento PIX com o valor especificado. Automaticamente cria uma notificação associada. Sujeito aos limites por transação, diário por pagador e noturno (20h–6h), e ao rate limit por credencial.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Cria um novo pagamento PIX",
+                "parameters": [
+                    {
+                        "description": "Dados do pagamento",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createPixRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payments.PixPayment"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON inválido ou campos inválidos (listados em errors)",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Corpo acima do limite (64 KiB)",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type diferente de application/json",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Limite de transação excedido",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit excedido (ver header Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/pix/monitor/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Endpoint SSE que envia eventos em tempo real quando o status do pagamento muda",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Monitora mudanças de status de um pagamento em tempo real (SSE)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do pagamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key ou JWT (EventSource não permite headers)",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/pix/ws": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Alternativa ao SSE para clientes que não suportam text/event-stream. Uma única conexão pode se inscrever e desinscrever de vários pagamentos enviando {\"action\":\"subscribe|unsubscribe\",\"payment_id\":N}. Pagamentos iniciais podem ser informados via query string (payment_id=1\u0026payment_id=2).",
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Monitora mudanças de status de pagamentos em tempo real (WebSocket)",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "IDs dos pagamentos para inscrição inicial",
+                        "name": "payment_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key ou JWT (WebSocket no browser não permite headers)",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/pix/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna os detalhes de um pagamento PIX específico pelo seu ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Busca pagamento PIX por ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do pagamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payments.PixPayment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Verifica banco e schema, com status e latência de cada dependência",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Alguma dependência indisponível",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista os pagamentos em PENDING_REVIEW de todos os lojistas, com as regras antifraude que dispararam e o prazo da revisão (mais próximos de expirar primeiro). Exige o escopo payments:review.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Fila de revisão manual",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/payments.PixPayment"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Libera um pagamento em PENDING_REVIEW, que passa pela autorização do BACEN e segue para AUTHORIZED e depois é liquidado (recusado pelo BACEN, volta com status REJECTED). O revisor é a identidade da credencial. Exige o escopo payments:review.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Aprova um pagamento retido",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do pagamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Observações do revisor",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/http.reviewDecisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payments.PixPayment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Pagamento não está aguardando revisão ou o prazo expirou",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recusa um pagamento em PENDING_REVIEW (status final REJECTED). A justificativa (notes) é obrigatória. Exige o escopo payments:review.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Recusa um pagamento retido",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do pagamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Justificativa da recusa",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.reviewDecisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payments.PixPayment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Pagamento não está aguardando revisão ou o prazo expirou",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Retorna os endpoints de webhook registrados pelo lojista autenticado (sem o secret)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "payments"
+                    "webhooks"
                 ],
-                "summary": "Lista todos os pagamentos PIX",
+                "summary": "Lista os endpoints de webhook",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/fintech-monolith_domains_payments.PixPayment"
+                                "$ref": "#/definitions/webhooks.WebhookEndpoint"
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/httpapi.Problem"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Cria um novo pagamento PIX com o valor especificado. Automaticamente cria uma notificação associada.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registra uma URL para receber callbacks (POST) quando o status de um pagamento muda. Cada entrega é assinada com HMAC-SHA256 no header X-Webhook-Signature (sha256=hex(HMAC(secret, timestamp + \".\" + body))) e traz o header X-Webhook-Timestamp.",
                 "consumes": [
                     "application/json"
                 ],
@@ -87,56 +723,88 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payments"
+                    "webhooks"
                 ],
-                "summary": "Cria um novo pagamento PIX",
+                "summary": "Registra um endpoint de webhook",
                 "parameters": [
                     {
-                        "description": "Dados do pagamento",
+                        "description": "Dados do endpoint",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apps_monolith-api_http.createPixRequest"
+                            "$ref": "#/definitions/http.createWebhookRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/fintech-monolith_domains_payments.PixPayment"
+                            "$ref": "#/definitions/webhooks.WebhookEndpoint"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "JSON inválido ou campos inválidos (listados em errors)",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Corpo acima do limite (64 KiB)",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type diferente de application/json",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
                         }
                     }
                 }
             }
         },
-        "/payments/pix/{id}": {
+        "/webhooks/{id}/deliveries": {
             "get": {
-                "description": "Retorna os detalhes de um pagamento PIX específico pelo seu ID",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Retorna todas as tentativas de entrega para o endpoint (mais recentes primeiro), incluindo status HTTP, erro e horário da próxima tentativa",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "payments"
+                    "webhooks"
                 ],
-                "summary": "Busca pagamento PIX por ID",
+                "summary": "Log de entregas de um webhook",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID do pagamento",
+                        "description": "ID do endpoint",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -146,25 +814,40 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/fintech-monolith_domains_payments.PixPayment"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/webhooks.WebhookDelivery"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/httpapi.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httpapi.Problem"
                         }
                     }
                 }
@@ -172,28 +855,217 @@ const docTemplate = `{
         }
     },
     "definitions": {
-        "apps_monolith-api_http.createPixRequest": {
+        "health.DependencyStatus": {
             "type": "object",
             "properties": {
-                "amount": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
                     "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.DependencyStatus"
+                    }
+                },
+                "service": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.createPixRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 123.45
+                },
+                "payer_id": {
+                    "type": "string",
+                    "example": "pagador-123"
+                }
+            }
+        },
+        "http.createWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "payment.authorized",
+                        "payment.settled"
+                    ]
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "s3cr3t"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://lojista.example.com/pix/callback"
+                }
+            }
+        },
+        "http.reviewDecisionRequest": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "type": "string",
+                    "example": "Cliente confirmou a transação por telefone"
+                }
+            }
+        },
+        "httpapi.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "amount"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be at least 0.01 with at most 2 decimal places"
+                }
+            }
+        },
+        "httpapi.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "payment_not_found"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "payment not found"
+                },
+                "errors": {
+                    "description": "Erros por campo, nas falhas de validação (code validation_failed)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpapi.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/pix/42"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "urn:fintech:problem:payment_not_found"
+                }
+            }
+        },
+        "payments.FraudAssessment": {
+            "type": "object",
+            "properties": {
+                "decision": {
+                    "$ref": "#/definitions/payments.FraudDecision"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "payments.FraudDecision": {
+            "type": "string",
+            "enum": [
+                "approve",
+                "review",
+                "deny"
+            ],
+            "x-enum-comments": {
+                "FraudApprove": "Segue para autorização",
+                "FraudDeny": "Recusado (REJECTED)",
+                "FraudReview": "Retido para aprovação manual"
+            },
+            "x-enum-descriptions": [
+                "Segue para autorização",
+                "Retido para aprovação manual",
+                "Recusado (REJECTED)"
+            ],
+            "x-enum-varnames": [
+                "FraudApprove",
+                "FraudReview",
+                "FraudDeny"
+            ]
+        },
+        "payments.PaymentReview": {
+            "type": "object",
+            "properties": {
+                "deadline": {
+                    "type": "string"
+                },
+                "decision": {
+                    "$ref": "#/definitions/payments.ReviewDecision"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
                 }
             }
         },
-        "fintech-monolith_domains_payments.PaymentStatus": {
+        "payments.PaymentStatus": {
             "type": "string",
             "enum": [
                 "CREATED",
+                "PENDING_REVIEW",
                 "AUTHORIZED",
-                "SETTLED"
+                "SETTLED",
+                "REJECTED"
+            ],
+            "x-enum-comments": {
+                "StatusPendingReview": "Retido pelo antifraude, aguarda aprovação manual",
+                "StatusRejected": "Recusado pelo antifraude"
+            },
+            "x-enum-descriptions": [
+                "",
+                "Retido pelo antifraude, aguarda aprovação manual",
+                "",
+                "",
+                "Recusado pelo antifraude"
             ],
             "x-enum-varnames": [
                 "StatusCreated",
+                "StatusPendingReview",
                 "StatusAuthorized",
-                "StatusSettled"
+                "StatusSettled",
+                "StatusRejected"
             ]
         },
-        "fintech-monolith_domains_payments.PixPayment": {
+        "payments.PixPayment": {
             "type": "object",
             "properties": {
                 "amount": {
@@ -205,11 +1077,129 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "merchant_id": {
+                    "description": "Lojista dono do pagamento",
+                    "type": "string"
+                },
+                "payer_id": {
+                    "description": "Pagador (base do limite diário)",
+                    "type": "string"
+                },
+                "review": {
+                    "description": "Revisão manual (quando retido)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/payments.PaymentReview"
+                        }
+                    ]
+                },
+                "risk": {
+                    "description": "Resultado da análise antifraude",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/payments.FraudAssessment"
+                        }
+                    ]
+                },
                 "status": {
-                    "$ref": "#/definitions/fintech-monolith_domains_payments.PaymentStatus"
+                    "$ref": "#/definitions/payments.PaymentStatus"
+                }
+            }
+        },
+        "payments.ReviewDecision": {
+            "type": "string",
+            "enum": [
+                "approved",
+                "rejected",
+                "expired"
+            ],
+            "x-enum-comments": {
+                "ReviewExpired": "Prazo esgotado sem decisão: o pagamento é recusado"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "Prazo esgotado sem decisão: o pagamento é recusado"
+            ],
+            "x-enum-varnames": [
+                "ReviewApproved",
+                "ReviewRejected",
+                "ReviewExpired"
+            ]
+        },
+        "webhooks.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "endpoint_id": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "next_retry_at": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "integer"
+                },
+                "status_code": {
+                    "description": "0 quando não houve resposta",
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "webhooks.WebhookEndpoint": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "merchant_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API key do lojista",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "JWT (HS256 ou RS256) no formato \"Bearer {token}\", com as claims merchant_id e scope",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
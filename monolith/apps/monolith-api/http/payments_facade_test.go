@@ -0,0 +1,182 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fintech-shared/auth"
+	"fintech-shared/httpapi"
+	"fintech-shared/payments"
+	"fintech-shared/payments/paymentstest"
+	"fintech-shared/ratelimit"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// paymentsServer monta as rotas de pagamentos sobre os fakes, com o caso de
+// uso real e o relógio manual (o fluxo em background termina na hora)
+type paymentsServer struct {
+	mux    *httpapi.ServeMux
+	repo   *paymentstest.Repository
+	events *paymentstest.Broadcaster
+}
+
+func newPaymentsServer(t *testing.T) *paymentsServer {
+	t.Helper()
+	store, err := auth.ParseAPIKeys("key-a|merchant-a|payments:read,payments:create;key-b|merchant-b|payments:read,payments:create")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 12h em Brasília: fora do período noturno dos limites
+	clock := paymentstest.NewClock(time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC))
+	s := &paymentsServer{
+		mux:    httpapi.NewServeMux(),
+		repo:   paymentstest.NewRepository(clock),
+		events: paymentstest.NewBroadcaster(),
+	}
+	createUC := payments.NewCreatePixPaymentUseCase(s.repo, &paymentstest.Notifier{}, &paymentstest.Gateway{}, s.events,
+		payments.DefaultTransactionLimits(), nil, time.Hour, nil, payments.FlowTimings{}, clock)
+	NewPaymentsFacade(createUC, s.repo, auth.NewAuthenticator(store, nil), auth.NewCORSPolicy(""),
+		ratelimit.NewTokenBucketLimiter(1000, 1000)).RegisterRoutes(s.mux)
+	return s
+}
+
+func (s *paymentsServer) save(t *testing.T, merchantID string, amount float64) *payments.PixPayment {
+	t.Helper()
+	payment, err := payments.NewPixPayment(merchantID, "pagador-1", amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := s.repo.Save(context.Background(), payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return saved
+}
+
+func (s *paymentsServer) do(method, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(auth.APIKeyHeader, key)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPaymentsFacade_CreateRunsTheFlow(t *testing.T) {
+	s := newPaymentsServer(t)
+
+	rec := s.do(http.MethodPost, "/payments/pix", "key-a", `{"amount": 150, "payer_id": "pagador-1"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var created payments.PixPayment
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == 0 || created.MerchantID != "merchant-a" || created.Status != payments.StatusCreated {
+		t.Errorf("created = %+v", created)
+	}
+
+	s.events.WaitFor(t, created.ID, payments.StatusSettled)
+	rec = s.do(http.MethodGet, fmt.Sprintf("/payments/pix/%d", created.ID), "key-a", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"SETTLED"`) {
+		t.Errorf("get = %d %s", rec.Code, rec.Body)
+	}
+
+	// Acima do limite por transação: 422 sem gravar nada
+	rec = s.do(http.MethodPost, "/payments/pix", "key-a", `{"amount": 1000001, "payer_id": "pagador-1"}`)
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `"code":"limit_exceeded"`) {
+		t.Errorf("over limit = %d %s", rec.Code, rec.Body)
+	}
+}
+
+// Cada lojista só enxerga os próprios pagamentos; os dos outros são 404
+func TestPaymentsFacade_ScopesPaymentsToMerchant(t *testing.T) {
+	s := newPaymentsServer(t)
+	own := s.save(t, "merchant-a", 10)
+	s.save(t, "merchant-a", 20)
+	other := s.save(t, "merchant-b", 30)
+
+	rec := s.do(http.MethodGet, "/payments/pix", "key-a", "")
+	var list []payments.PixPayment
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || len(list) != 2 {
+		t.Fatalf("list = %d %+v", rec.Code, list)
+	}
+	for _, p := range list {
+		if p.MerchantID != "merchant-a" {
+			t.Errorf("listed payment %d of %s", p.ID, p.MerchantID)
+		}
+	}
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantCode   string
+	}{
+		{fmt.Sprintf("/payments/pix/%d", own.ID), http.StatusOK, ""},
+		{fmt.Sprintf("/payments/pix/%d", other.ID), http.StatusNotFound, "payment_not_found"},
+		{"/payments/pix/999", http.StatusNotFound, "payment_not_found"},
+		{"/payments/pix/abc", http.StatusBadRequest, httpapi.CodeInvalidID},
+		{fmt.Sprintf("/payments/pix/monitor/%d", other.ID), http.StatusNotFound, "payment_not_found"},
+		{"/payments/pix/monitor/abc", http.StatusBadRequest, httpapi.CodeInvalidID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := s.do(http.MethodGet, tt.path, "key-a", "")
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), `"code":"`+tt.wantCode+`"`) {
+				t.Errorf("body = %s, want code %s", rec.Body, tt.wantCode)
+			}
+		})
+	}
+}
+
+// O monitor SSE aceita a credencial na query (EventSource não envia headers)
+// e começa pelo status atual do pagamento
+func TestPaymentsFacade_MonitorStreamsInitialStatus(t *testing.T) {
+	s := newPaymentsServer(t)
+	payment := s.save(t, "merchant-a", 10)
+	server := httptest.NewServer(s.mux)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s/payments/pix/monitor/%d?access_token=key-a", server.URL, payment.ID), nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d, Content-Type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	var event []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line == "\n" {
+			break
+		}
+		event = append(event, line)
+	}
+	if got := strings.Join(event, ""); !strings.HasPrefix(got, "event: initial\n") || !strings.Contains(got, `"status":"CREATED"`) {
+		t.Errorf("initial event = %q", got)
+	}
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fintech-shared/auth"
+	"fintech-shared/httpapi"
+	"fintech-shared/payments"
+	"fintech-shared/payments/paymentstest"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReviewsFacade_Decisions(t *testing.T) {
+	store, err := auth.ParseAPIKeys("key-analyst|backoffice|payments:review;key-a|merchant-a|payments:read")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := paymentstest.NewClock(time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC))
+	repo := paymentstest.NewRepository(clock)
+	events := paymentstest.NewBroadcaster()
+	createUC := payments.NewCreatePixPaymentUseCase(repo, &paymentstest.Notifier{}, &paymentstest.Gateway{}, events,
+		payments.DefaultTransactionLimits(), nil, time.Hour, nil, payments.FlowTimings{}, clock)
+	mux := httpapi.NewServeMux()
+	NewReviewsFacade(payments.NewReviewPaymentUseCase(repo, createUC), auth.NewAuthenticator(store, nil)).RegisterRoutes(mux)
+
+	hold := func() int64 {
+		payment, _ := payments.NewPixPayment("merchant-a", "pagador-1", 150)
+		saved, _ := repo.Save(context.Background(), payment)
+		_ = saved.HoldForReview(clock.Now().Add(time.Hour))
+		if ok, err := repo.SaveReview(context.Background(), saved, payments.StatusCreated); !ok || err != nil {
+			t.Fatalf("SaveReview = %v, %v", ok, err)
+		}
+		return saved.ID
+	}
+	approved, rejected := hold(), hold()
+
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(auth.APIKeyHeader, key)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodGet, "/reviews", "key-analyst", "")
+	var queue []payments.PixPayment
+	if err := json.Unmarshal(rec.Body.Bytes(), &queue); err != nil || len(queue) != 2 {
+		t.Fatalf("queue = %d %s", rec.Code, rec.Body)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		key        string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"merchant without scope", fmt.Sprintf("/reviews/%d/approve", approved), "key-a", "", http.StatusForbidden, httpapi.CodeForbidden},
+		{"invalid id", "/reviews/abc/approve", "key-analyst", "", http.StatusBadRequest, httpapi.CodeInvalidID},
+		{"invalid json", fmt.Sprintf("/reviews/%d/reject", rejected), "key-analyst", `{"notes":`, http.StatusBadRequest, httpapi.CodeInvalidJSON},
+		{"reject without notes", fmt.Sprintf("/reviews/%d/reject", rejected), "key-analyst", `{}`, http.StatusBadRequest, "review_notes_required"},
+		{"approve without body", fmt.Sprintf("/reviews/%d/approve", approved), "key-analyst", "", http.StatusOK, ""},
+		{"reject", fmt.Sprintf("/reviews/%d/reject", rejected), "key-analyst", `{"notes": "fraude confirmada"}`, http.StatusOK, ""},
+		{"second decision", fmt.Sprintf("/reviews/%d/reject", approved), "key-analyst", `{"notes": "tarde demais"}`, http.StatusConflict, "payment_not_pending_review"},
+		{"unknown payment", "/reviews/999/approve", "key-analyst", "", http.StatusNotFound, "payment_not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(http.MethodPost, tt.path, tt.key, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), `"code":"`+tt.wantCode+`"`) {
+				t.Errorf("body = %s, want code %s", rec.Body, tt.wantCode)
+			}
+		})
+	}
+
+	events.WaitFor(t, approved, payments.StatusSettled)
+	if got := events.Statuses(rejected); len(got) != 1 || got[0] != payments.StatusRejected {
+		t.Errorf("rejected events = %v", got)
+	}
+	if rec := do(http.MethodGet, "/reviews", "key-analyst", ""); strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("queue after decisions = %s", rec.Body)
+	}
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"fintech-shared/auth"
+	"fintech-shared/httpapi"
+	"fintech-shared/webhooks"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type WebhooksFacade struct {
+	repo  webhooks.WebhookRepository
+	authn *auth.Authenticator
+}
+
+type createWebhookRequest struct {
+	URL        string   `json:"url" example:"https://lojista.example.com/pix/callback"`
+	Secret     string   `json:"secret" example:"s3cr3t"`
+	EventTypes []string `json:"event_types" example:"payment.authorized,payment.settled"`
+}
+
+// validate confere a presença dos campos; formato da URL e tipos de evento
+// ficam com o domínio (NewWebhookEndpoint)
+func (req createWebhookRequest) validate() error {
+	var v httpapi.Validator
+	v.Required("url", req.URL)
+	v.Required("secret", req.Secret)
+	v.Check(len(req.EventTypes) > 0, "event_types", "must not be empty")
+	return v.Err()
+}
+
+func NewWebhooksFacade(repo webhooks.WebhookRepository, authn *auth.Authenticator) *WebhooksFacade {
+	return &WebhooksFacade{repo: repo, authn: authn}
+}
+
+func (f *WebhooksFacade) RegisterRoutes(mux httpapi.Mux) {
+	mux.Handle("GET /webhooks", f.authn.Middleware(http.HandlerFunc(f.listAll)))
+	mux.Handle("POST /webhooks", f.authn.Middleware(http.HandlerFunc(f.create)))
+	mux.Handle("GET /webhooks/{id}/deliveries", f.authn.Middleware(http.HandlerFunc(f.listDeliveries)))
+}
+
+// create godoc
+// @Summary      Registra um endpoint de webhook
+// @Description  Registra uma URL para receber callbacks (POST) quando o status de um pagamento muda. Cada entrega é assinada com HMAC-SHA256 no header X-Webhook-Signature (sha256=hex(HMAC(secret, timestamp + "." + body))) e traz o header X-Webhook-Timestamp.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        request  body      createWebhookRequest  true  "Dados do endpoint"
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Success      201      {object}  webhooks.WebhookEndpoint
+// @Failure      400      {object}  httpapi.Problem  "JSON inválido ou campos inválidos (listados em errors)"
+// @Failure      401      {object}  httpapi.Problem
+// @Failure      403      {object}  httpapi.Problem
+// @Failure      413      {object}  httpapi.Problem  "Corpo acima do limite (64 KiB)"
+// @Failure      415      {object}  httpapi.Problem  "Content-Type diferente de application/json"
+// @Failure      500      {object}  httpapi.Problem
+// @Router       /webhooks [post]
+func (f *WebhooksFacade) create(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.AuthorizeMerchant(w, r, auth.ScopeWebhooksManage)
+	if !ok {
+		return
+	}
+
+	var req createWebhookRequest
+	if err := httpapi.DecodeJSON(w, r, &req); err != nil {
+		slog.WarnContext(r.Context(), "failed to decode request", "error", err)
+		apiErrors.Write(w, r, err)
+		return
+	}
+	if err := req.validate(); err != nil {
+		slog.WarnContext(r.Context(), "invalid webhook request", "url", req.URL, "events", req.EventTypes)
+		apiErrors.Write(w, r, err)
+		return
+	}
+
+	endpoint, err := webhooks.NewWebhookEndpoint(principal.MerchantID, req.URL, req.Secret, req.EventTypes)
+	if err != nil {
+		apiErrors.Write(w, r, err)
+		return
+	}
+
+	saved, err := f.repo.SaveEndpoint(r.Context(), endpoint)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to save webhook endpoint", "error", err)
+		apiErrors.Write(w, r, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "webhook endpoint registered", "endpoint_id", saved.ID, "merchant_id", saved.MerchantID, "url", saved.URL, "events", saved.EventTypes)
+	writeJSON(w, http.StatusCreated, saved)
+}
+
+// listAll godoc
+// @Summary      Lista os endpoints de webhook
+// @Description  Retorna os endpoints de webhook registrados pelo lojista autenticado (sem o secret)
+// @Tags         webhooks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Success      200  {array}   webhooks.WebhookEndpoint
+// @Failure      401  {object}  httpapi.Problem
+// @Failure      403  {object}  httpapi.Problem
+// @Failure      500  {object}  httpapi.Problem
+// @Router       /webhooks [get]
+func (f *WebhooksFacade) listAll(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.AuthorizeMerchant(w, r, auth.ScopeWebhooksManage)
+	if !ok {
+		return
+	}
+
+	endpoints, err := f.repo.FindEndpointsByMerchant(r.Context(), principal.MerchantID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to list webhook endpoints", "error", err)
+		apiErrors.Write(w, r, err)
+		return
+	}
+
+	if endpoints == nil {
+		endpoints = []*webhooks.WebhookEndpoint{}
+	}
+	writeJSON(w, http.StatusOK, endpoints)
+}
+
+// listDeliveries godoc
+// @Summary      Log de entregas de um webhook
+// @Description  Retorna todas as tentativas de entrega para o endpoint (mais recentes primeiro), incluindo status HTTP, erro e horário da próxima tentativa
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      int  true  "ID do endpoint"
+// @Security     ApiKeyAuth
+// @Security     BearerAuth
+// @Success      200  {array}   webhooks.WebhookDelivery
+// @Failure      400  {object}  httpapi.Problem
+// @Failure      401  {object}  httpapi.Problem
+// @Failure      403  {object}  httpapi.Problem
+// @Failure      404  {object}  httpapi.Problem
+// @Failure      500  {object}  httpapi.Problem
+// @Router       /webhooks/{id}/deliveries [get]
+func (f *WebhooksFacade) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.AuthorizeMerchant(w, r, auth.ScopeWebhooksManage)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		httpapi.WriteProblem(w, r, http.StatusBadRequest, httpapi.CodeInvalidID, "invalid webhook ID")
+		return
+	}
+
+	endpoint, err := f.repo.FindEndpointByID(r.Context(), id)
+	if err != nil {
+		apiErrors.Write(w, r, err)
+		return
+	}
+	// Endpoints de outros lojistas são tratados como inexistentes
+	if endpoint.MerchantID != principal.MerchantID {
+		apiErrors.Write(w, r, webhooks.ErrEndpointNotFound)
+		return
+	}
+
+	deliveries, err := f.repo.FindDeliveriesByEndpoint(r.Context(), id)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to list webhook deliveries", "error", err)
+		apiErrors.Write(w, r, err)
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []*webhooks.WebhookDelivery{}
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
@@ -2,23 +2,42 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	httpSwagger "github.com/swaggo/http-swagger"
 
-	_ "fintech-monolith/apps/monolith-api/docs" // docs is generated by Swag CLI, you have to import it.
+	"fintech-monolith/apps/monolith-api/docs" // docs is generated by Swag CLI, you have to import it.
 
-	app "fintech-monolith/domains/payments/application"
+	paymentsdomain "fintech-shared/payments"
+	"fintech-shared/httpapi"
+	"fintech-shared/openapi"
+	notificationsdomain "fintech-monolith/domains/notifications"
+	"fintech-shared/auth"
+	"fintech-monolith/infra/database/migrations"
+	"fintech-shared/migrate"
 	"fintech-monolith/infra/database/notifications"
-	"fintech-monolith/infra/database/payments"
+	"fintech-shared/postgres"
+	"fintech-shared/health"
+	"fintech-shared/logging"
 	"fintech-monolith/infra/messaging/pix"
+	"fintech-shared/metrics"
+	"fintech-shared/webhooks"
+	"fintech-shared/ratelimit"
+	"fintech-shared/tracing"
 	httphandler "fintech-monolith/apps/monolith-api/http"
 )
 
+//go:generate swag init -g main.go -d .,./http --parseDependency -o docs
+
 // @title           Fintech Monolith API
 // @version         1.0
 // @description     API monolítica para gerenciamento de pagamentos PIX e notificações
@@ -31,7 +50,35 @@ import (
 // @BasePath  /
 
 // @schemes   http
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 API key do lojista
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 JWT (HS256 ou RS256) no formato "Bearer {token}", com as claims merchant_id e scope
 func main() {
+	// Logs estruturados em JSON (nível em LOG_LEVEL)
+	logging.Setup("monolith-api")
+
+	// Subcomando "migrate up|down|status": gerencia o schema e encerra
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate.RunCommand("monolith-api", os.Args[2:], migrations.New); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Tracing (OpenTelemetry), exportado conforme OTEL_TRACES_EXPORTER
+	shutdownTracing, err := tracing.Setup(context.Background(), "monolith-api")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { _ = shutdownTracing(context.Background()) }()
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -42,6 +89,42 @@ func main() {
 		log.Fatal("DATABASE_URL is required")
 	}
 
+	// Autenticação por API key e/ou JWT (ver fintech-shared/auth)
+	authenticator, err := auth.NewAuthenticatorFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	cors := auth.NewCORSPolicy(os.Getenv("CORS_ALLOWED_ORIGINS"))
+
+	// Rate limit por credencial/IP em /payments/pix (token bucket)
+	limiter := ratelimit.NewTokenBucketLimiter(envFloat("RATE_LIMIT_RPS", 5), int(envFloat("RATE_LIMIT_BURST", 10)))
+
+	// Limites de negócio do PIX (valores em R$; 0 desabilita o limite)
+	limits := paymentsdomain.DefaultTransactionLimits()
+	limits.MaxPerTransaction = envFloat("PIX_MAX_PER_TRANSACTION", limits.MaxPerTransaction)
+	limits.DailyPerPayer = envFloat("PIX_DAILY_LIMIT_PER_PAYER", limits.DailyPerPayer)
+	limits.NightlyPerTransaction = envFloat("PIX_NIGHTLY_LIMIT", limits.NightlyPerTransaction)
+
+	// Regras antifraude (YAML); sem FRAUD_RULES_FILE valem as regras padrão
+	fraudRules, err := paymentsdomain.LoadFraudRules(os.Getenv("FRAUD_RULES_FILE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Prazo da revisão manual: sem decisão até lá, o pagamento retido é recusado
+	reviewDeadline := envDuration("REVIEW_DEADLINE", 30*time.Minute)
+
+	// Perfil de simulação: esperas do fluxo e latência do BACEN. demo (padrão)
+	// deixa cada etapa visível no monitor; instant e load servem aos testes
+	simulationProfile, err := paymentsdomain.LoadProfile(os.Getenv("SIMULATION_PROFILE"), os.Getenv("SIMULATION_FILE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Cancelado no SIGTERM/SIGINT: para os laços em background e o servidor
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -52,27 +135,71 @@ func main() {
 	}
 	defer pool.Close()
 
+	// Não sobe com o schema desatualizado: rode "monolith-api migrate up" antes
+	migrator, err := migrations.New(pool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := migrator.Check(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	// Métricas Prometheus (/metrics): HTTP, fluxo de pagamento, eventos e pool do banco
+	appMetrics := metrics.New()
+	appMetrics.Register(metrics.NewPoolCollector(pool))
+	paymentMetrics := metrics.NewPayments(appMetrics)
+	httphandler.GetBroadcaster().SetMetrics(paymentMetrics)
+
 	// Repositórios compartilhando o mesmo banco
-	paymentRepo := payments.NewPgPixPaymentRepository(pool)
+	paymentRepo := postgres.NewPgPixPaymentRepository(pool)
 	notificationRepo := notifications.NewPgNotificationRepository(pool)
-	gateway := pix.NewBacenPixGateway()
+	webhookRepo := postgres.NewPgWebhookRepository(pool)
+	gateway := pix.NewBacenPixGateway(simulationProfile.Gateway, paymentsdomain.SystemClock{})
+
+	// Webhooks dos lojistas: consomem os mesmos eventos do SSE. As retentativas
+	// ficam em webhook_deliveries e são reenviadas a cada WEBHOOK_RETRY_INTERVAL
+	webhookDispatcher := webhooks.NewWebhookDispatcher(webhookRepo, 5, 2*time.Second, paymentsdomain.SystemClock{})
+	webhookDispatcher.SetMetrics(paymentMetrics)
+	webhookDispatcher.Start(shutdownCtx, 4, envDuration("WEBHOOK_RETRY_INTERVAL", time.Second))
 
-	// Event broadcaster para observabilidade em tempo real
-	eventBroadcaster := httphandler.GetBroadcaster()
+	// Event broadcaster para observabilidade em tempo real (SSE/WebSocket) e webhooks
+	eventBroadcaster := paymentsdomain.EventBroadcasters{httphandler.GetBroadcaster(), webhookDispatcher}
 
 	// Use case que usa ambos os repositórios (comunicação direta no monólito)
-	createUC := app.NewCreatePixPaymentUseCase(paymentRepo, notificationRepo, gateway, eventBroadcaster)
+	fraudChecker := paymentsdomain.NewRulesFraudChecker(fraudRules, paymentRepo, paymentsdomain.SystemClock{})
+	createUC := paymentsdomain.NewCreatePixPaymentUseCase(paymentRepo, notificationsdomain.NewPaymentNotifier(notificationRepo), gateway, eventBroadcaster, limits, fraudChecker, reviewDeadline, paymentMetrics, simulationProfile.Flow, paymentsdomain.SystemClock{})
+	reviewUC := paymentsdomain.NewReviewPaymentUseCase(paymentRepo, createUC)
+	reviewUC.StartExpiry(shutdownCtx, envDuration("REVIEW_EXPIRY_INTERVAL", 30*time.Second))
 
-	facade := httphandler.NewPaymentsFacade(createUC, paymentRepo)
+	facade := httphandler.NewPaymentsFacade(createUC, paymentRepo, authenticator, cors, limiter)
+	webhooksFacade := httphandler.NewWebhooksFacade(webhookRepo, authenticator)
+	reviewsFacade := httphandler.NewReviewsFacade(reviewUC, authenticator)
 
-	mux := http.NewServeMux()
+	// Readiness: banco compartilhado e migrações de todos os domínios
+	readiness := health.NewChecker("monolith-api", 2*time.Second)
+	readiness.Add("postgres", health.PingCheck(pool))
+	readiness.Add("schema", migrator.Check)
+
+	mux := httpapi.NewServeMux()
 	
 	// Health check endpoint
 	mux.HandleFunc("/health", healthCheck)
+
+	// Probes do orquestrador (sem autenticação, como o health check)
+	mux.HandleFunc("/livez", livenessCheck)
+	mux.HandleFunc("/readyz", readinessCheck(readiness))
+
+	// Métricas para o Prometheus (sem autenticação, como o health check)
+	mux.Handle("/metrics", appMetrics.Handler())
 	
-	// Swagger UI
+	// Documentação OpenAPI 3 (gerada das anotações com swag) e Swagger UI
+	spec, err := openapi.Handler(docs.SwaggerInfo.ReadDoc())
+	if err != nil {
+		log.Fatal(err)
+	}
+	mux.Handle(openapi.Path, spec)
 	mux.HandleFunc("/swagger/", httpSwagger.Handler(
-		httpSwagger.URL("http://localhost:8080/swagger/doc.json"),
+		httpSwagger.URL(openapi.Path),
 		httpSwagger.DeepLinking(true),
 		httpSwagger.DocExpansion("none"),
 		httpSwagger.DomID("swagger-ui"),
@@ -80,18 +207,34 @@ func main() {
 	
 	// API routes
 	facade.RegisterRoutes(mux)
+	webhooksFacade.RegisterRoutes(mux)
+	reviewsFacade.RegisterRoutes(mux)
 
 	srv := &http.Server{
 		Addr:         ":" + port,
-		Handler:      mux,
+		Handler:      logging.RequestIDMiddleware(tracing.Handler(cors.Middleware(appMetrics.InstrumentMux(mux)), mux)),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
 
-	log.Println("Monolith API listening on :" + port)
-	log.Println("NOTE: This is a monolithic service with shared database")
-	log.Println("Swagger UI available at: http://localhost:" + port + "/swagger/index.html")
-	log.Fatal(srv.ListenAndServe())
+	slog.Info("monolith api listening", "port", port,
+		"swagger_ui", "http://localhost:"+port+"/swagger/index.html")
+	// Shutdown espera as requisições em andamento (até 10s; streams SSE não terminam sozinhos)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-shutdownCtx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			slog.Error("graceful shutdown failed", "error", err)
+		}
+	}()
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	<-stopped
+	slog.Info("monolith api stopped")
 }
 
 // healthCheck godoc
@@ -107,3 +250,52 @@ func healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte(`{"status":"ok","type":"monolith"}`))
 }
+
+// livenessCheck godoc
+// @Summary      Liveness probe
+// @Description  Indica que o processo está de pé (não verifica dependências)
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  map[string]string
+// @Router       /livez [get]
+func livenessCheck(w http.ResponseWriter, r *http.Request) {
+	health.LiveHandler("monolith-api")(w, r)
+}
+
+// readinessCheck godoc
+// @Summary      Readiness probe
+// @Description  Verifica banco e schema, com status e latência de cada dependência
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  health.Report
+// @Failure      503  {object}  health.Report  "Alguma dependência indisponível"
+// @Router       /readyz [get]
+func readinessCheck(checker *health.Checker) http.HandlerFunc {
+	return checker.ReadyHandler()
+}
+
+// envFloat lê um número da variável de ambiente, com valor padrão
+func envFloat(name string, def float64) float64 {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", name, err)
+	}
+	return value
+}
+
+// envDuration lê uma duração (ex.: "30m") da variável de ambiente, com valor padrão
+func envDuration(name string, def time.Duration) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		log.Fatalf("invalid %s: %q", name, raw)
+	}
+	return value
+}
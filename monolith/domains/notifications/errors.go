@@ -0,0 +1,10 @@
+package notifications
+
+import "errors"
+
+var (
+	// ErrNotFound indica que a notificação não existe (o repositório o devolve no lugar do erro do driver)
+	ErrNotFound = errors.New("notification not found")
+	// ErrInvalidTransition indica uma mudança de status que o ciclo de vida não permite
+	ErrInvalidTransition = errors.New("invalid status transition")
+)
@@ -0,0 +1,39 @@
+package notifications
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNotification_Lifecycle(t *testing.T) {
+	transitions := map[string]func(*Notification) error{
+		"send": (*Notification).MarkAsSent,
+		"fail": (*Notification).MarkAsFailed,
+	}
+	allowed := map[NotificationStatus]map[string]NotificationStatus{
+		StatusPending: {"send": StatusSent, "fail": StatusFailed},
+		StatusSent:    {},
+		StatusFailed:  {},
+	}
+
+	for from, targets := range allowed {
+		for name, apply := range transitions {
+			t.Run(string(from)+"/"+name, func(t *testing.T) {
+				notification := NewNotification(1, "PAYMENT_CREATED", "pagador-1", "")
+				notification.Status = from
+
+				err := apply(notification)
+				want, ok := targets[name]
+				if !ok {
+					if !errors.Is(err, ErrInvalidTransition) || notification.Status != from {
+						t.Errorf("err = %v, status = %s; want ErrInvalidTransition and %s", err, notification.Status, from)
+					}
+					return
+				}
+				if err != nil || notification.Status != want {
+					t.Errorf("err = %v, status = %s; want %s", err, notification.Status, want)
+				}
+			})
+		}
+	}
+}
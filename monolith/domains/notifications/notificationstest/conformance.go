@@ -0,0 +1,47 @@
+package notificationstest
+
+import (
+	"context"
+	"errors"
+	"fintech-monolith/domains/notifications"
+	"testing"
+)
+
+// RunRepositoryTests verifica o contrato de notifications.NotificationRepository.
+// Roda contra o Repository em memória e contra o PostgreSQL, para que o fake
+// não se afaste do SQL. newRepository devolve um repositório vazio e o ID de
+// um pagamento existente (no banco do monólito há FK para pix_payments).
+func RunRepositoryTests(t *testing.T, newRepository func(t *testing.T) (notifications.NotificationRepository, int64)) {
+	ctx := context.Background()
+
+	t.Run("save inserts", func(t *testing.T) {
+		repo, paymentID := newRepository(t)
+		var saved []*notifications.Notification
+		for _, notificationType := range []string{"PAYMENT_CREATED", "PAYMENT_AUTHORIZED"} {
+			notification, err := repo.Save(ctx, notifications.NewNotification(paymentID, notificationType, "user@example.com", "Pagamento"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			saved = append(saved, notification)
+		}
+		if saved[0].ID == 0 || saved[1].ID <= saved[0].ID {
+			t.Errorf("ids = %d, %d, want increasing", saved[0].ID, saved[1].ID)
+		}
+
+		stored, err := repo.FindByID(ctx, saved[1].ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.PaymentID != paymentID || stored.Type != "PAYMENT_AUTHORIZED" || stored.Recipient != "user@example.com" ||
+			stored.Message != "Pagamento" || stored.Status != notifications.StatusPending || stored.CreatedAt.IsZero() {
+			t.Errorf("stored = %+v", stored)
+		}
+	})
+
+	t.Run("find unknown id", func(t *testing.T) {
+		repo, _ := newRepository(t)
+		if _, err := repo.FindByID(ctx, 999); !errors.Is(err, notifications.ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+}
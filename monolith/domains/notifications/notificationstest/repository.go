@@ -0,0 +1,63 @@
+// Package notificationstest tem a implementação em memória do repositório de
+// notificações do monólito para os testes
+package notificationstest
+
+import (
+	"context"
+	"fintech-monolith/domains/notifications"
+	"sort"
+	"sync"
+)
+
+// Repository implementa notifications.NotificationRepository em memória
+type Repository struct {
+	mu            sync.Mutex
+	nextID        int64
+	notifications map[int64]*notifications.Notification
+}
+
+func NewRepository() *Repository {
+	return &Repository{notifications: map[int64]*notifications.Notification{}}
+}
+
+func (r *Repository) Save(ctx context.Context, notification *notifications.Notification) (*notifications.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	notification.ID = r.nextID
+	saved := *notification
+	r.notifications[saved.ID] = &saved
+	return notification, nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id int64) (*notifications.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notification, ok := r.notifications[id]
+	if !ok {
+		return nil, notifications.ErrNotFound
+	}
+	found := *notification
+	return &found, nil
+}
+
+// Types devolve os tipos das notificações do pagamento, na ordem em que foram salvas
+func (r *Repository) Types(paymentID int64) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var saved []*notifications.Notification
+	for _, notification := range r.notifications {
+		if notification.PaymentID == paymentID {
+			saved = append(saved, notification)
+		}
+	}
+	sort.Slice(saved, func(i, j int) bool { return saved[i].ID < saved[j].ID })
+	var types []string
+	for _, notification := range saved {
+		types = append(types, notification.Type)
+	}
+	return types
+}
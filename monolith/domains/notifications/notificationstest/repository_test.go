@@ -0,0 +1,12 @@
+package notificationstest
+
+import (
+	"fintech-monolith/domains/notifications"
+	"testing"
+)
+
+func TestRepository_Conformance(t *testing.T) {
+	RunRepositoryTests(t, func(t *testing.T) (notifications.NotificationRepository, int64) {
+		return NewRepository(), 1
+	})
+}
@@ -0,0 +1,38 @@
+package notifications
+
+import (
+	"context"
+	"fintech-shared/payments"
+	"fintech-shared/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("fintech-monolith/domains/notifications")
+
+// PaymentNotifier implementa payments.Notifier gravando a notificação no
+// mesmo banco (no monólito, tudo está no mesmo processo - comunicação direta)
+type PaymentNotifier struct {
+	repo NotificationRepository
+}
+
+func NewPaymentNotifier(repo NotificationRepository) *PaymentNotifier {
+	return &PaymentNotifier{repo: repo}
+}
+
+func (n *PaymentNotifier) Notify(ctx context.Context, payment *payments.PixPayment, notificationType payments.NotificationType, message string) error {
+	ctx, span := tracer.Start(ctx, "notifications.Create", trace.WithAttributes(
+		attribute.String("notification.type", string(notificationType)),
+		attribute.Int64("payment.id", payment.ID),
+	))
+	defer span.End()
+
+	notification := NewNotification(payment.ID, string(notificationType), "user@example.com", message)
+	if _, err := n.repo.Save(ctx, notification); err != nil {
+		tracing.Fail(span, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package notifications_test
+
+import (
+	"context"
+	"errors"
+	"fintech-monolith/domains/notifications"
+	"fintech-monolith/domains/notifications/notificationstest"
+	"fintech-shared/payments"
+	"reflect"
+	"testing"
+)
+
+func TestPaymentNotifier_SavesNotification(t *testing.T) {
+	repo := notificationstest.NewRepository()
+	notifier := notifications.NewPaymentNotifier(repo)
+	payment := &payments.PixPayment{ID: 7, Amount: 150}
+
+	for _, notificationType := range []payments.NotificationType{payments.NotificationCreated, payments.NotificationAuthorized} {
+		if err := notifier.Notify(context.Background(), payment, notificationType, "Pagamento PIX"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := repo.Types(7); !reflect.DeepEqual(got, []string{"PAYMENT_CREATED", "PAYMENT_AUTHORIZED"}) {
+		t.Errorf("types = %v", got)
+	}
+	stored, err := repo.FindByID(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Message != "Pagamento PIX" || stored.Status != notifications.StatusPending {
+		t.Errorf("stored = %+v", stored)
+	}
+}
+
+// brokenRepository simula o banco fora do ar
+type brokenRepository struct {
+	notifications.NotificationRepository
+}
+
+func (brokenRepository) Save(context.Context, *notifications.Notification) (*notifications.Notification, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestPaymentNotifier_ReturnsSaveError(t *testing.T) {
+	notifier := notifications.NewPaymentNotifier(brokenRepository{})
+	if err := notifier.Notify(context.Background(), &payments.PixPayment{ID: 7}, payments.NotificationSettled, ""); err == nil {
+		t.Error("Notify() = nil, want the repository error")
+	}
+}
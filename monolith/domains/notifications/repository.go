@@ -1,6 +1,8 @@
 package notifications
 
+import "context"
+
 type NotificationRepository interface {
-	Save(notification *Notification) (*Notification, error)
-	FindByID(id int64) (*Notification, error)
+	Save(ctx context.Context, notification *Notification) (*Notification, error)
+	FindByID(ctx context.Context, id int64) (*Notification, error)
 }
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"embed"
+	"fintech-shared/migrate"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Migrações versionadas do banco compartilhado: sql/NNNN_nome.up.sql e sql/NNNN_nome.down.sql
+//
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockKey identifica o advisory lock das migrações deste banco
+const lockKey int64 = 0x6d_6f_6e_6d_69_67 // "monmig"
+
+// New cria o migrator com as migrações embutidas no binário
+func New(pool *pgxpool.Pool) (*migrate.Migrator, error) {
+	return migrate.New(pool, files, "sql", lockKey)
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"fintech-shared/migrate"
+	"fintech-shared/migrate/migratetest"
+	"fintech-shared/payments"
+	"fintech-shared/payments/paymentstest"
+	"fintech-shared/pgtest"
+	"fintech-shared/postgres"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pgtest.Run(m))
+}
+
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	migrations, err := migrate.Load(files, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+	// Versões sequenciais a partir de 1, sem buracos
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d has version %d, want %d", i, m.Version, i+1)
+		}
+	}
+}
+
+// schemaHeader abre o db/init-monolith.sql, gerado a partir das migrações
+const schemaHeader = `-- Banco de dados compartilhado no monólito
+-- Todos os domínios (payments, notifications) usam o mesmo banco
+--
+-- NOTE: No monólito, ambos os domínios compartilham o mesmo banco
+-- Isso quebra a autonomia de dados, mas é aceitável em um monólito inicial
+--
+-- Schema completo de referência, GERADO a partir das migrações em
+-- monolith/infra/database/migrations/sql (a fonte da verdade, aplicadas com
+-- "migrate up"). Não edite: rode "go test ./infra/database/migrations -update-schema".
+`
+
+func TestSchemaFile(t *testing.T) {
+	migratetest.CheckSchemaFile(t, files, "sql", "init-monolith.sql", schemaHeader)
+}
+
+// A suíte do repositório compartilhado com o schema montado pelas migrações
+// embutidas no binário (em fintech-shared ela roda contra o init gerado)
+func TestMigrations_PixPaymentRepository(t *testing.T) {
+	pgtest.DSN(t) // Sem servidor, pula a suíte inteira de uma vez
+	paymentstest.RunRepositoryTests(t, func(t *testing.T) payments.PixPaymentRepository {
+		return postgres.NewPgPixPaymentRepository(migratetest.NewPool(t, New))
+	})
+}
@@ -0,0 +1,8 @@
+-- Pagamentos PIX. IF NOT EXISTS: num banco que já tem a tabela a migração só
+-- passa a versioná-lo
+CREATE TABLE IF NOT EXISTS pix_payments (
+  id BIGSERIAL PRIMARY KEY,
+  amount NUMERIC(18,2) NOT NULL,
+  status TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,12 @@
+-- Notificações. No monólito o banco é compartilhado, então a FK para o
+-- pagamento é possível (nos microsserviços é apenas uma referência)
+CREATE TABLE IF NOT EXISTS notifications (
+  id BIGSERIAL PRIMARY KEY,
+  payment_id BIGINT NOT NULL,
+  type TEXT NOT NULL,
+  recipient TEXT NOT NULL,
+  message TEXT NOT NULL,
+  status TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT fk_payment FOREIGN KEY (payment_id) REFERENCES pix_payments(id)
+);
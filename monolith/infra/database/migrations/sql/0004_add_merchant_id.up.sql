@@ -0,0 +1,5 @@
+-- Lojista dono do pagamento: cada lojista só vê os próprios pagamentos.
+-- IF NOT EXISTS: num banco que já tem a coluna e o índice a migração não falha
+ALTER TABLE pix_payments ADD COLUMN IF NOT EXISTS merchant_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_pix_payments_merchant ON pix_payments (merchant_id, created_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE pix_payments
+  DROP COLUMN IF EXISTS risk_reasons,
+  DROP COLUMN IF EXISTS risk_decision;
@@ -0,0 +1,4 @@
+-- Avaliação do antifraude
+ALTER TABLE pix_payments
+  ADD COLUMN IF NOT EXISTS risk_decision TEXT, -- approve/review/deny
+  ADD COLUMN IF NOT EXISTS risk_reasons TEXT[]; -- Regras que dispararam
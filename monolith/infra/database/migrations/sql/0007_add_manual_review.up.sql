@@ -0,0 +1,9 @@
+-- Revisão manual dos pagamentos retidos pelo antifraude
+ALTER TABLE pix_payments
+  ADD COLUMN IF NOT EXISTS review_deadline TIMESTAMPTZ, -- Prazo da revisão (PENDING_REVIEW)
+  ADD COLUMN IF NOT EXISTS review_decision TEXT, -- approved/rejected/expired
+  ADD COLUMN IF NOT EXISTS reviewed_by TEXT, -- Identidade do revisor (ou "system" na expiração)
+  ADD COLUMN IF NOT EXISTS review_notes TEXT,
+  ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_pix_payments_pending_review ON pix_payments (review_deadline) WHERE status = 'PENDING_REVIEW';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+ALTER TABLE webhook_deliveries
+  DROP COLUMN IF EXISTS retried_at,
+  DROP COLUMN IF EXISTS payload;
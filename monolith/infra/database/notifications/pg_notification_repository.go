@@ -2,9 +2,12 @@ package notifications
 
 import (
 	"context"
+	"errors"
 	"fintech-monolith/domains/notifications"
+	"fintech-shared/tracing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -18,9 +21,10 @@ func NewPgNotificationRepository(pool *pgxpool.Pool) *PgNotificationRepository {
 	return &PgNotificationRepository{pool: pool}
 }
 
-func (r *PgNotificationRepository) Save(notification *notifications.Notification) (*notifications.Notification, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *PgNotificationRepository) Save(ctx context.Context, notification *notifications.Notification) (*notifications.Notification, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "NotificationRepository.Save")
 	defer cancel()
+	defer span.End()
 
 	var id int64
 	var createdAt time.Time
@@ -30,7 +34,7 @@ func (r *PgNotificationRepository) Save(notification *notifications.Notification
 	).Scan(&id, &createdAt)
 
 	if err != nil {
-		return nil, err
+		return nil, tracing.ObserveDB(span, err)
 	}
 
 	notification.ID = id
@@ -38,9 +42,10 @@ func (r *PgNotificationRepository) Save(notification *notifications.Notification
 	return notification, nil
 }
 
-func (r *PgNotificationRepository) FindByID(id int64) (*notifications.Notification, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *PgNotificationRepository) FindByID(ctx context.Context, id int64) (*notifications.Notification, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "NotificationRepository.FindByID")
 	defer cancel()
+	defer span.End()
 
 	var notification notifications.Notification
 	var status string
@@ -49,8 +54,11 @@ func (r *PgNotificationRepository) FindByID(id int64) (*notifications.Notificati
 		id,
 	).Scan(&notification.ID, &notification.PaymentID, &notification.Type, &notification.Recipient, &notification.Message, &status, &notification.CreatedAt)
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notifications.ErrNotFound
+	}
 	if err != nil {
-		return nil, err
+		return nil, tracing.ObserveDB(span, err)
 	}
 
 	notification.Status = notifications.NotificationStatus(status)
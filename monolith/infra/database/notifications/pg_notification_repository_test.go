@@ -0,0 +1,46 @@
+package notifications
+
+import (
+	"context"
+	"fintech-monolith/domains/notifications"
+	"fintech-monolith/domains/notifications/notificationstest"
+	"fintech-monolith/infra/database/migrations"
+	"fintech-shared/migrate/migratetest"
+	"fintech-shared/pgtest"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pgtest.Run(m))
+}
+
+func TestPgNotificationRepository_Conformance(t *testing.T) {
+	pgtest.DSN(t) // Sem servidor, pula a suíte inteira de uma vez
+	notificationstest.RunRepositoryTests(t, func(t *testing.T) (notifications.NotificationRepository, int64) {
+		return newRepository(t, pgtest.NewPool(t, "init-monolith.sql"))
+	})
+}
+
+// Mesma suíte com o schema montado pelas migrações embutidas no binário
+func TestPgNotificationRepository_Migrations(t *testing.T) {
+	pgtest.DSN(t)
+	notificationstest.RunRepositoryTests(t, func(t *testing.T) (notifications.NotificationRepository, int64) {
+		return newRepository(t, migratetest.NewPool(t, migrations.New))
+	})
+}
+
+// newRepository cria o repositório e o pagamento que a FK fk_payment exige
+func newRepository(t *testing.T, pool *pgxpool.Pool) (notifications.NotificationRepository, int64) {
+	t.Helper()
+	var paymentID int64
+	err := pool.QueryRow(context.Background(),
+		"INSERT INTO pix_payments (amount, status) VALUES (150, 'CREATED') RETURNING id",
+	).Scan(&paymentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewPgNotificationRepository(pool), paymentID
+}
@@ -1,43 +1,87 @@
 package pix
 
 import (
-	"fintech-monolith/domains/payments"
-	"log"
-	"time"
+	"context"
+	"fintech-shared/payments"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type BacenPixGateway struct{}
+var tracer = otel.Tracer("fintech-monolith/infra/messaging/pix")
+
+// BacenPixGateway simula o BACEN. A latência de cada chamada é sorteada do
+// perfil de simulação (fixa na demo, cauda longa no perfil de carga).
+type BacenPixGateway struct {
+	profile payments.GatewayProfile
+	clock   payments.Clock
 
-func NewBacenPixGateway() *BacenPixGateway {
-	return &BacenPixGateway{}
+	mu  sync.Mutex
+	rng *rand.Rand
 }
 
-func (g *BacenPixGateway) NotifyCreation(payment *payments.PixPayment) {
+func NewBacenPixGateway(profile payments.GatewayProfile, clock payments.Clock) *BacenPixGateway {
+	if clock == nil {
+		clock = payments.SystemClock{}
+	}
+	return &BacenPixGateway{
+		profile: profile,
+		clock:   clock,
+		rng:     rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+func (g *BacenPixGateway) NotifyCreation(ctx context.Context, payment *payments.PixPayment) {
+	ctx, span := startSpan(ctx, "bacen.NotifyCreation", payment)
+	defer span.End()
+
 	// Simula notificação para o BACEN
-	log.Printf("BACEN: Notificando criação de pagamento PIX - ID: %d, Valor: R$ %.2f", payment.ID, payment.Amount)
+	slog.InfoContext(ctx, "bacen: notifying pix payment creation", "amount", payment.Amount)
 	// Simula latência de rede
-	time.Sleep(100 * time.Millisecond)
-	log.Printf("BACEN: Pagamento PIX registrado no sistema - ID: %d", payment.ID)
+	g.wait(span, g.profile.NotifyCreation)
+	slog.InfoContext(ctx, "bacen: pix payment registered")
 }
 
-func (g *BacenPixGateway) Authorize(payment *payments.PixPayment) error {
-	// Simula autorização no BACEN
-	log.Printf("BACEN: Processando autorização de pagamento PIX - ID: %d", payment.ID)
-	time.Sleep(200 * time.Millisecond)
+func (g *BacenPixGateway) Authorize(ctx context.Context, payment *payments.PixPayment) error {
+	ctx, span := startSpan(ctx, "bacen.Authorize", payment)
+	defer span.End()
 
-	// Simula validações do BACEN
-	if payment.Amount > 100000 {
-		log.Printf("BACEN: Pagamento acima de R$ 100.000 requer análise adicional")
-	}
-
-	log.Printf("BACEN: Pagamento PIX autorizado - ID: %d", payment.ID)
+	// Simula autorização no BACEN
+	slog.InfoContext(ctx, "bacen: processing pix authorization")
+	g.wait(span, g.profile.Authorize)
+	slog.InfoContext(ctx, "bacen: pix payment authorized")
 	return nil
 }
 
-func (g *BacenPixGateway) Settle(payment *payments.PixPayment) error {
+func (g *BacenPixGateway) Settle(ctx context.Context, payment *payments.PixPayment) error {
+	ctx, span := startSpan(ctx, "bacen.Settle", payment)
+	defer span.End()
+
 	// Simula liquidação no BACEN
-	log.Printf("BACEN: Processando liquidação de pagamento PIX - ID: %d", payment.ID)
-	time.Sleep(300 * time.Millisecond)
-	log.Printf("BACEN: Pagamento PIX liquidado - ID: %d, Valor transferido: R$ %.2f", payment.ID, payment.Amount)
+	slog.InfoContext(ctx, "bacen: processing pix settlement")
+	g.wait(span, g.profile.Settle)
+	slog.InfoContext(ctx, "bacen: pix payment settled", "amount", payment.Amount)
 	return nil
 }
+
+// wait espera a latência sorteada e a registra no span
+func (g *BacenPixGateway) wait(span trace.Span, latency payments.Latency) {
+	g.mu.Lock()
+	d := latency.Sample(g.rng)
+	g.mu.Unlock()
+
+	span.SetAttributes(attribute.Int64("bacen.simulated_latency_ms", d.Milliseconds()))
+	g.clock.Sleep(d)
+}
+
+// startSpan abre o span da chamada ao BACEN (sistema externo)
+func startSpan(ctx context.Context, name string, payment *payments.PixPayment) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int64("payment.id", payment.ID), attribute.Float64("payment.amount", payment.Amount)),
+	)
+}
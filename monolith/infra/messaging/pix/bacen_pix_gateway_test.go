@@ -0,0 +1,82 @@
+package pix
+
+import (
+	"context"
+	"fintech-shared/payments"
+	"fintech-shared/payments/paymentstest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// A latência de cada chamada vem do perfil e passa pelo relógio (sem dormir)
+func TestBacenPixGateway_WaitsProfileLatency(t *testing.T) {
+	demo, _ := payments.SimulationProfileByName("demo")
+	load, _ := payments.SimulationProfileByName("load")
+	tests := []struct {
+		name    string
+		profile payments.GatewayProfile
+		check   func(t *testing.T, sleeps []time.Duration)
+	}{
+		{"demo", demo.Gateway, func(t *testing.T, sleeps []time.Duration) {
+			if want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}; !reflect.DeepEqual(sleeps, want) {
+				t.Errorf("sleeps = %v, want %v", sleeps, want)
+			}
+		}},
+		{"instant", payments.GatewayProfile{}, func(t *testing.T, sleeps []time.Duration) {
+			if want := []time.Duration{0, 0, 0}; !reflect.DeepEqual(sleeps, want) {
+				t.Errorf("sleeps = %v, want %v", sleeps, want)
+			}
+		}},
+		{"load", load.Gateway, func(t *testing.T, sleeps []time.Duration) {
+			caps := []time.Duration{load.Gateway.NotifyCreation.Max, load.Gateway.Authorize.Max, load.Gateway.Settle.Max}
+			for i, d := range sleeps {
+				if d <= 0 || d > caps[i] {
+					t.Errorf("sleep %d = %s, want in (0, %s]", i, d, caps[i])
+				}
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := paymentstest.NewClock(time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC))
+			gateway := NewBacenPixGateway(tt.profile, clock)
+			payment := &payments.PixPayment{ID: 1, Amount: 10}
+
+			gateway.NotifyCreation(context.Background(), payment)
+			if err := gateway.Authorize(context.Background(), payment); err != nil {
+				t.Fatal(err)
+			}
+			if err := gateway.Settle(context.Background(), payment); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, clock.Sleeps())
+		})
+	}
+}
+
+// No fluxo de pagamento, a autorização passa pelo gateway: a latência do
+// perfil entra depois da espera do fluxo, antes da liquidação
+func TestBacenPixGateway_LatencyInPaymentFlow(t *testing.T) {
+	demo, _ := payments.SimulationProfileByName("demo")
+	clock := paymentstest.NewClock(time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC))
+	events := paymentstest.NewBroadcaster()
+	createUC := payments.NewCreatePixPaymentUseCase(paymentstest.NewRepository(clock), &paymentstest.Notifier{},
+		NewBacenPixGateway(demo.Gateway, clock), events, payments.DefaultTransactionLimits(), nil, time.Hour, nil, demo.Flow, clock)
+
+	payment, err := createUC.Execute(context.Background(), "loja-a", "pagador-1", 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events.WaitFor(t, payment.ID, payments.StatusSettled)
+
+	// A notificação da liquidação ao BACEN vem depois do evento SETTLED
+	want := []time.Duration{
+		demo.Flow.Startup, 100 * time.Millisecond, // NotifyCreation
+		demo.Flow.Authorization, 200 * time.Millisecond, // Authorize
+		demo.Flow.Settlement,
+	}
+	if got := clock.Sleeps(); len(got) < len(want) || !reflect.DeepEqual(got[:len(want)], want) {
+		t.Errorf("sleeps = %v, want %v first", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// APIKeyStore resolve API keys para principals. As chaves são guardadas
+// apenas como hash SHA-256 e comparadas em tempo constante.
+type APIKeyStore struct {
+	keys map[[sha256.Size]byte]*Principal
+}
+
+// ParseAPIKeys lê a configuração no formato
+//
+//	chave|merchant|escopo,escopo;chave2|merchant2|escopo
+//
+// ex.: "dev-key-a|merchant-a|payments:read,payments:create" ou, num serviço
+// interno, "dev-key-payments|payments-service|notifications:write"
+func ParseAPIKeys(config string) (*APIKeyStore, error) {
+	store := &APIKeyStore{keys: make(map[[sha256.Size]byte]*Principal)}
+
+	for _, entry := range strings.Split(config, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, "|")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid API key entry %q: expected key|merchant|scopes", redact(parts[0]))
+		}
+
+		var scopes []string
+		for _, scope := range strings.Split(parts[2], ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+
+		hash := sha256.Sum256([]byte(parts[0]))
+		if _, dup := store.keys[hash]; dup {
+			return nil, errors.New("duplicated API key for merchant " + parts[1])
+		}
+		store.keys[hash] = &Principal{
+			// Identificador estável e único por chave (prefixo do hash), seguro para logs
+			Subject:    "apikey:" + hex.EncodeToString(hash[:6]),
+			MerchantID: parts[1],
+			Scopes:     scopes,
+		}
+	}
+
+	return store, nil
+}
+
+// Lookup retorna o principal da chave, se existir
+func (s *APIKeyStore) Lookup(key string) (*Principal, bool) {
+	if s == nil || key == "" {
+		return nil, false
+	}
+	hash := sha256.Sum256([]byte(key))
+	for stored, p := range s.keys {
+		if subtle.ConstantTimeCompare(stored[:], hash[:]) == 1 {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func (s *APIKeyStore) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.keys)
+}
+
+// redact mantém só o prefixo da chave para logs e identificação
+func redact(key string) string {
+	if len(key) <= 4 {
+		return "****"
+	}
+	return key[:4] + "****"
+}
@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func b64(v any) string {
+	data, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, secret string, claims map[string]any) string {
+	t.Helper()
+	unsigned := b64(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + b64(claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	unsigned := b64(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid}) + "." + b64(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func writeJWKS(t *testing.T, kid string, key *rsa.PublicKey) string {
+	t.Helper()
+	jwks := map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	data, _ := json.Marshal(jwks)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":         "user-1",
+		"iss":         "https://auth.fintech.local",
+		"aud":         []string{"fintech-api"},
+		"exp":         time.Now().Add(time.Hour).Unix(),
+		"merchant_id": "merchant-a",
+		"scope":       "payments:read payments:create",
+	}
+}
+
+func TestJWTVerifier_HS256(t *testing.T) {
+	v, err := NewJWTVerifier([]byte("secret"), "", "https://auth.fintech.local", "fintech-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := v.Verify(signHS256(t, "secret", validClaims()))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	p := claims.Principal()
+	if p.MerchantID != "merchant-a" || !p.HasScope(ScopePaymentsCreate) || p.HasScope(ScopePaymentsRefund) {
+		t.Errorf("unexpected principal: %+v", p)
+	}
+
+	if _, err := v.Verify(signHS256(t, "other-secret", validClaims())); err != ErrInvalidToken {
+		t.Errorf("wrong secret: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestJWTVerifier_RS256WithLocalJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewJWTVerifier(nil, writeJWKS(t, "key-1", &key.PublicKey), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := v.Verify(signRS256(t, key, "key-1", validClaims())); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if _, err := v.Verify(signRS256(t, key, "unknown-kid", validClaims())); err != ErrInvalidToken {
+		t.Errorf("unknown kid: err = %v, want ErrInvalidToken", err)
+	}
+
+	// HS256 assinado com a chave pública não pode ser aceito (confusão de algoritmo)
+	if _, err := v.Verify(signHS256(t, string(key.PublicKey.N.Bytes()), validClaims())); err != ErrInvalidToken {
+		t.Errorf("alg confusion: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestJWTVerifier_RejectsInvalidClaims(t *testing.T) {
+	v, _ := NewJWTVerifier([]byte("secret"), "", "https://auth.fintech.local", "fintech-api")
+
+	tests := map[string]func(map[string]any){
+		"expired":      func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"missing exp":  func(c map[string]any) { delete(c, "exp") },
+		"not yet":      func(c map[string]any) { c["nbf"] = time.Now().Add(time.Hour).Unix() },
+		"wrong issuer": func(c map[string]any) { c["iss"] = "https://evil.example.com" },
+		"wrong aud":    func(c map[string]any) { c["aud"] = "other-api" },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			claims := validClaims()
+			mutate(claims)
+			if _, err := v.Verify(signHS256(t, "secret", claims)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	none := b64(map[string]string{"alg": "none"}) + "." + b64(validClaims()) + "."
+	if _, err := v.Verify(none); err != ErrInvalidToken {
+		t.Errorf("alg none: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	store, err := ParseAPIKeys("key-a|merchant-a|payments:read,payments:create; key-b|merchant-b|payments:read")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", store.Len())
+	}
+
+	p, ok := store.Lookup("key-b")
+	if !ok || p.MerchantID != "merchant-b" || p.HasScope(ScopePaymentsCreate) {
+		t.Errorf("unexpected principal: %+v", p)
+	}
+	if _, ok := store.Lookup("key-c"); ok {
+		t.Error("unknown key accepted")
+	}
+
+	if _, err := ParseAPIKeys("key-without-merchant"); err == nil {
+		t.Error("expected error for malformed entry")
+	}
+}
+
+func TestMiddlewareAndAuthorize(t *testing.T) {
+	store, _ := ParseAPIKeys("key-a|merchant-a|payments:read")
+	verifier, _ := NewJWTVerifier([]byte("secret"), "", "", "")
+	authn := NewAuthenticator(store, verifier)
+
+	handler := authn.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope := r.URL.Query().Get("scope")
+		if p, ok := AuthorizeMerchant(w, r, scope); ok {
+			_, _ = w.Write([]byte(p.MerchantID))
+		}
+	}))
+
+	token := signHS256(t, "secret", validClaims())
+	tests := []struct {
+		name   string
+		url    string
+		header map[string]string
+		want   int
+	}{
+		{"no credentials", "/?scope=payments:read", nil, http.StatusUnauthorized},
+		{"invalid api key", "/?scope=payments:read", map[string]string{APIKeyHeader: "nope"}, http.StatusUnauthorized},
+		{"api key", "/?scope=payments:read", map[string]string{APIKeyHeader: "key-a"}, http.StatusOK},
+		{"api key missing scope", "/?scope=payments:create", map[string]string{APIKeyHeader: "key-a"}, http.StatusForbidden},
+		{"bearer jwt", "/?scope=payments:create", map[string]string{"Authorization": "Bearer " + token}, http.StatusOK},
+		{"basic auth", "/?scope=payments:read", map[string]string{"Authorization": "Basic a2V5LWE6"}, http.StatusUnauthorized},
+		{"access_token api key", "/?scope=payments:read&access_token=key-a", nil, http.StatusOK},
+		{"access_token jwt", "/?scope=payments:read&access_token=" + token, nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.want, rec.Body.String())
+			}
+			if rec.Code == http.StatusOK && rec.Body.String() != "merchant-a" {
+				t.Errorf("merchant = %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCORSPolicy(t *testing.T) {
+	policy := NewCORSPolicy("https://painel.example.com")
+	handler := policy.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/payments/pix", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("disallowed origin got Access-Control-Allow-Origin = %q", got)
+	}
+
+	req.Header.Set("Origin", "https://painel.example.com")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://painel.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+
+	ws := httptest.NewRequest(http.MethodGet, "http://api.local/payments/pix/ws", nil)
+	ws.Header.Set("Origin", "http://api.local")
+	if !policy.CheckOrigin(ws) {
+		t.Error("same origin WebSocket rejected")
+	}
+	ws.Header.Set("Origin", "https://evil.example.com")
+	if policy.CheckOrigin(ws) {
+		t.Error("cross origin WebSocket accepted")
+	}
+}
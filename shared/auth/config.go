@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"errors"
+	"os"
+)
+
+// NewAuthenticatorFromEnv monta o Authenticator das APIs de lojistas a partir das variáveis:
+//
+//	API_KEYS          chave|merchant|escopo,escopo;...
+//	JWT_HS256_SECRET  segredo compartilhado para tokens HS256
+//	JWT_JWKS_FILE     arquivo JWKS local com as chaves públicas RS256
+//	JWT_ISSUER        valor exigido na claim "iss" (opcional)
+//	JWT_AUDIENCE      valor exigido na claim "aud" (opcional)
+//
+// Pelo menos um método de autenticação precisa estar configurado.
+func NewAuthenticatorFromEnv() (*Authenticator, error) {
+	apiKeys, err := ParseAPIKeys(os.Getenv("API_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	var verifier *JWTVerifier
+	secret := os.Getenv("JWT_HS256_SECRET")
+	jwksFile := os.Getenv("JWT_JWKS_FILE")
+	if secret != "" || jwksFile != "" {
+		verifier, err = NewJWTVerifier([]byte(secret), jwksFile, os.Getenv("JWT_ISSUER"), os.Getenv("JWT_AUDIENCE"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if apiKeys.Len() == 0 && verifier == nil {
+		return nil, errors.New("auth: configure API_KEYS and/or JWT_HS256_SECRET/JWT_JWKS_FILE")
+	}
+	return NewAuthenticator(apiKeys, verifier), nil
+}
+
+// NewServiceAuthenticatorFromEnv monta o Authenticator de um serviço interno,
+// chamado por outros serviços e pelo backoffice, a partir das variáveis:
+//
+//	API_KEYS              chave|cliente|escopo,escopo;... (ex.: backoffice com notifications:read)
+//	SERVICE_TOKEN_SECRET  segredo compartilhado dos tokens de serviço (JWT HS256)
+//	SERVICE_TOKEN_ISSUER  serviço emissor aceito (padrão: payments-service)
+//
+// Tokens de serviço só são aceitos com aud = audience e exp válido.
+// Pelo menos um método de autenticação precisa estar configurado.
+func NewServiceAuthenticatorFromEnv(audience string) (*Authenticator, error) {
+	apiKeys, err := ParseAPIKeys(os.Getenv("API_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	var verifier *JWTVerifier
+	if secret := os.Getenv("SERVICE_TOKEN_SECRET"); secret != "" {
+		issuer := os.Getenv("SERVICE_TOKEN_ISSUER")
+		if issuer == "" {
+			issuer = "payments-service"
+		}
+		verifier, err = NewJWTVerifier([]byte(secret), "", issuer, audience)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if apiKeys.Len() == 0 && verifier == nil {
+		return nil, errors.New("auth: configure API_KEYS and/or SERVICE_TOKEN_SECRET")
+	}
+	return NewAuthenticator(apiKeys, verifier), nil
+}
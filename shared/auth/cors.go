@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CORSPolicy libera chamadas cross-origin apenas para origens configuradas
+// (substitui o antigo Access-Control-Allow-Origin: *)
+type CORSPolicy struct {
+	origins map[string]bool
+}
+
+// NewCORSPolicy recebe as origens permitidas separadas por vírgula,
+// ex.: "http://localhost:3000,https://painel.example.com"
+func NewCORSPolicy(allowedOrigins string) *CORSPolicy {
+	p := &CORSPolicy{origins: make(map[string]bool)}
+	for _, origin := range strings.Split(allowedOrigins, ",") {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			p.origins[origin] = true
+		}
+	}
+	return p
+}
+
+func (p *CORSPolicy) allowed(origin string) bool {
+	return p.origins[origin]
+}
+
+// Middleware adiciona os headers CORS para origens permitidas e responde preflights
+func (p *CORSPolicy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && p.allowed(origin) {
+			w.Header().Add("Vary", "Origin")
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			// O painel lê o X-Request-ID para correlacionar erros com os logs
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Request-ID, "+APIKeyHeader)
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CheckOrigin é usado no upgrade de WebSocket: aceita clientes sem Origin
+// (não-browser), a própria origem do servidor e as origens configuradas
+func (p *CORSPolicy) CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host) || p.allowed(origin)
+}
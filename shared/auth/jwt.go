@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// Tolerância para diferenças de relógio entre emissor e API
+const clockSkew = 30 * time.Second
+
+// Claims são as claims suportadas nos JWTs de acesso
+type Claims struct {
+	Subject    string   `json:"sub"`
+	Issuer     string   `json:"iss,omitempty"`
+	Audience   audience `json:"aud,omitempty"`
+	ExpiresAt  int64    `json:"exp"`
+	NotBefore  int64    `json:"nbf,omitempty"`
+	IssuedAt   int64    `json:"iat,omitempty"`
+	MerchantID string   `json:"merchant_id,omitempty"`
+	Scope      string   `json:"scope,omitempty"` // Escopos separados por espaço (RFC 8693)
+}
+
+// audience aceita tanto "aud": "x" quanto "aud": ["x", "y"]
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a audience) contains(value string) bool {
+	for _, v := range a {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// JWTVerifier valida JWTs assinados com HS256 (segredo compartilhado)
+// ou RS256 (chaves públicas de um JWKS local)
+type JWTVerifier struct {
+	hmacSecret []byte
+	rsaKeys    map[string]*rsa.PublicKey // kid -> chave
+	issuer     string
+	audience   string
+	now        func() time.Time
+}
+
+// NewJWTVerifier cria o verificador. issuer e audience vazios desabilitam a respectiva checagem.
+func NewJWTVerifier(hmacSecret []byte, jwksPath, issuer, audience string) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		hmacSecret: hmacSecret,
+		issuer:     issuer,
+		audience:   audience,
+		now:        time.Now,
+	}
+
+	if jwksPath != "" {
+		keys, err := loadJWKS(jwksPath)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKeys = keys
+	}
+
+	if len(v.hmacSecret) == 0 && len(v.rsaKeys) == 0 {
+		return nil, errors.New("jwt: an HS256 secret or a JWKS file is required")
+	}
+	return v, nil
+}
+
+// Verify valida assinatura, expiração, nbf, issuer e audience e retorna as claims
+func (v *JWTVerifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+
+	// O algoritmo é decidido pela configuração, nunca apenas pelo header
+	// (evita "alg: none" e confusão HS256/RS256)
+	switch header.Alg {
+	case "HS256":
+		if len(v.hmacSecret) == 0 {
+			return nil, ErrInvalidToken
+		}
+		mac := hmac.New(sha256.New, v.hmacSecret)
+		mac.Write(signed)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, ErrInvalidToken
+		}
+	case "RS256":
+		key := v.rsaKey(header.Kid)
+		if key == nil {
+			return nil, ErrInvalidToken
+		}
+		digest := sha256.Sum256(signed)
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, ErrInvalidToken
+		}
+	default:
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	now := v.now()
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return nil, ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Add(clockSkew).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, ErrInvalidToken
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return nil, ErrInvalidToken
+	}
+	if v.audience != "" && !claims.Audience.contains(v.audience) {
+		return nil, ErrInvalidToken
+	}
+
+	return &claims, nil
+}
+
+// Principal converte as claims no principal da requisição
+func (c *Claims) Principal() *Principal {
+	return &Principal{
+		Subject:    c.Subject,
+		MerchantID: c.MerchantID,
+		Scopes:     strings.Fields(c.Scope),
+	}
+}
+
+func (v *JWTVerifier) rsaKey(kid string) *rsa.PublicKey {
+	if kid != "" {
+		return v.rsaKeys[kid]
+	}
+	// Sem kid só é aceitável quando o JWKS tem uma única chave
+	if len(v.rsaKeys) == 1 {
+		for _, key := range v.rsaKeys {
+			return key
+		}
+	}
+	return nil
+}
+
+func decodeSegment(segment string, out any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// loadJWKS carrega as chaves RSA de um arquivo JWKS (RFC 7517)
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: reading JWKS: %w", err)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			Alg string `json:"alg"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("jwt: parsing JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") || (k.Alg != "" && k.Alg != "RS256") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: invalid modulus for key %q", k.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("jwt: invalid exponent for key %q", k.Kid)
+		}
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("jwt: JWKS has no RS256 signing keys")
+	}
+	return keys, nil
+}
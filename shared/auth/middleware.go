@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"errors"
+	"fintech-shared/httpapi"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+var errMissingCredentials = errors.New("missing credentials")
+
+// Authenticator autentica requisições por API key ou JWT (Bearer)
+type Authenticator struct {
+	apiKeys *APIKeyStore
+	jwt     *JWTVerifier
+}
+
+func NewAuthenticator(apiKeys *APIKeyStore, jwt *JWTVerifier) *Authenticator {
+	return &Authenticator{apiKeys: apiKeys, jwt: jwt}
+}
+
+// Middleware exige uma credencial válida e coloca o Principal no contexto
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := a.authenticate(r)
+		if err != nil {
+			slog.WarnContext(r.Context(), "unauthenticated request", "method", r.Method, "path", r.URL.Path, "error", err)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="fintech"`)
+			httpapi.WriteProblem(w, r, http.StatusUnauthorized, httpapi.CodeUnauthorized, "unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
+
+func (a *Authenticator) authenticate(r *http.Request) (*Principal, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return a.lookupAPIKey(key)
+	}
+
+	if header := r.Header.Get("Authorization"); header != "" {
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			return nil, errors.New("unsupported authorization scheme")
+		}
+		return a.verifyJWT(token)
+	}
+
+	// EventSource e WebSocket no browser não permitem headers customizados,
+	// então aceitamos a credencial via query string apenas em GETs
+	if r.Method == http.MethodGet {
+		if token := r.URL.Query().Get("access_token"); token != "" {
+			if strings.Count(token, ".") == 2 {
+				return a.verifyJWT(token)
+			}
+			return a.lookupAPIKey(token)
+		}
+	}
+
+	return nil, errMissingCredentials
+}
+
+func (a *Authenticator) lookupAPIKey(key string) (*Principal, error) {
+	principal, ok := a.apiKeys.Lookup(key)
+	if !ok {
+		return nil, errors.New("invalid API key")
+	}
+	return principal, nil
+}
+
+func (a *Authenticator) verifyJWT(token string) (*Principal, error) {
+	if a.jwt == nil {
+		return nil, errors.New("jwt authentication is not configured")
+	}
+	claims, err := a.jwt.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	return claims.Principal(), nil
+}
+
+// Authorize verifica se o principal autenticado tem o escopo exigido.
+// Responde 401/403 e retorna false quando não tiver.
+func Authorize(w http.ResponseWriter, r *http.Request, scope string) (*Principal, bool) {
+	principal, ok := PrincipalFrom(r.Context())
+	if !ok {
+		httpapi.WriteProblem(w, r, http.StatusUnauthorized, httpapi.CodeUnauthorized, "unauthorized")
+		return nil, false
+	}
+	if !principal.HasScope(scope) {
+		httpapi.WriteProblem(w, r, http.StatusForbidden, httpapi.CodeForbidden, "insufficient scope: "+scope+" required")
+		return nil, false
+	}
+	return principal, true
+}
+
+// AuthorizeMerchant é como Authorize, mas também exige que a credencial
+// esteja vinculada a um lojista (necessário para operações em pagamentos)
+func AuthorizeMerchant(w http.ResponseWriter, r *http.Request, scope string) (*Principal, bool) {
+	principal, ok := Authorize(w, r, scope)
+	if !ok {
+		return nil, false
+	}
+	if principal.MerchantID == "" {
+		httpapi.WriteProblem(w, r, http.StatusForbidden, httpapi.CodeForbidden, "credential is not bound to a merchant")
+		return nil, false
+	}
+	return principal, true
+}
@@ -0,0 +1,47 @@
+package auth
+
+import "context"
+
+// Escopos concedidos às credenciais (API keys e JWT)
+const (
+	ScopePaymentsRead   = "payments:read"
+	ScopePaymentsCreate = "payments:create"
+	ScopePaymentsRefund = "payments:refund" // Reservado para o endpoint de estorno
+	ScopePaymentsReview = "payments:review" // Revisão manual do antifraude (não vinculada a lojista)
+	ScopeWebhooksManage = "webhooks:manage"
+
+	ScopeNotificationsRead  = "notifications:read"
+	ScopeNotificationsWrite = "notifications:write"
+)
+
+// NotificationsAudience é a audience dos tokens de serviço aceitos pelo notifications-service
+const NotificationsAudience = "notifications-service"
+
+// Principal é a identidade autenticada de uma requisição.
+// Todo pagamento pertence a um lojista (MerchantID) e só é visível para ele.
+type Principal struct {
+	Subject    string
+	MerchantID string
+	Scopes     []string
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom retorna o principal autenticado pelo middleware
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ServiceTokenSigner emite tokens de serviço para chamadas internas
+// (ex.: payments-service -> notifications-service). São JWTs HS256 de vida
+// curta, com issuer, audience e escopos, validados pelo JWTVerifier do destino.
+type ServiceTokenSigner struct {
+	secret []byte
+	issuer string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewServiceTokenSigner(secret []byte, issuer string, ttl time.Duration) (*ServiceTokenSigner, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("service token: secret is required")
+	}
+	if issuer == "" {
+		return nil, errors.New("service token: issuer is required")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("service token: ttl must be > 0")
+	}
+	return &ServiceTokenSigner{secret: secret, issuer: issuer, ttl: ttl, now: time.Now}, nil
+}
+
+// Sign emite um token válido apenas para o serviço de destino (audience)
+func (s *ServiceTokenSigner) Sign(audience string, scopes ...string) (string, error) {
+	now := s.now()
+	return SignHS256(s.secret, Claims{
+		Subject:   s.issuer,
+		Issuer:    s.issuer,
+		Audience:  []string{audience},
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: now.Add(s.ttl).Unix(),
+		Scope:     strings.Join(scopes, " "),
+	})
+}
+
+// SignHS256 serializa e assina as claims como um JWT HS256
+func SignHS256(secret []byte, claims Claims) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
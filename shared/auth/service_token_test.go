@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServiceTokenSigner(t *testing.T) {
+	signer, err := NewServiceTokenSigner([]byte("service-secret"), "payments-service", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := signer.Sign("notifications-service", "notifications:write")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, _ := NewJWTVerifier([]byte("service-secret"), "", "payments-service", "notifications-service")
+	claims, err := v.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if p := claims.Principal(); p.Subject != "payments-service" || !p.HasScope("notifications:write") {
+		t.Errorf("unexpected principal: %+v", p)
+	}
+
+	other, _ := NewJWTVerifier([]byte("service-secret"), "", "", "other-service")
+	if _, err := other.Verify(token); err != ErrInvalidToken {
+		t.Errorf("wrong audience: err = %v, want ErrInvalidToken", err)
+	}
+
+	forged, _ := NewJWTVerifier([]byte("another-secret"), "", "", "notifications-service")
+	if _, err := forged.Verify(token); err != ErrInvalidToken {
+		t.Errorf("wrong secret: err = %v, want ErrInvalidToken", err)
+	}
+
+	// Token emitido há mais tempo que ttl + tolerância de relógio
+	signer.now = func() time.Time { return time.Now().Add(-2 * time.Minute) }
+	expired, _ := signer.Sign("notifications-service", "notifications:write")
+	if _, err := v.Verify(expired); err != ErrTokenExpired {
+		t.Errorf("expired: err = %v, want ErrTokenExpired", err)
+	}
+}
@@ -0,0 +1,97 @@
+// Package egress protege as chamadas a URLs cadastradas por terceiros (os webhooks
+// dos lojistas): só https e só endereços públicos, para que um cadastro não
+// transforme o serviço numa porta para a rede interna (SSRF).
+package egress
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress indica um destino fora da internet pública
+var ErrForbiddenAddress = errors.New("destination address is not allowed")
+
+// Faixas que não são de loopback/privadas/link-local pelo net/netip, mas também
+// não saem para a internet
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "Esta rede"
+	netip.MustParsePrefix("100.64.0.0/10"),  // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // Atribuições do IETF
+	netip.MustParsePrefix("198.18.0.0/15"),  // Testes de desempenho
+	netip.MustParsePrefix("240.0.0.0/4"),    // Reservado (inclui o broadcast)
+	netip.MustParsePrefix("64:ff9b:1::/48"), // Tradução IPv4/IPv6 local
+}
+
+// Allowed diz se o endereço é público: rejeita loopback, privados, link-local,
+// multicast, não especificado e as faixas reservadas
+func Allowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range reserved {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL valida uma URL no cadastro: https, com host, e sem apontar
+// diretamente para um endereço proibido. Um nome DNS só é resolvido na hora da
+// conexão (ver Transport): o que ele resolve hoje pode mudar amanhã.
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return errors.New("url must be an absolute https URL")
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !Allowed(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// Transport só conecta a endereços públicos: o endereço é conferido depois da
+// resolução DNS, no momento do dial, o que também cobre os redirecionamentos e
+// um DNS que troca de resposta entre o cadastro e a entrega. Não usa proxy,
+// que esconderia o destino real do dial.
+func Transport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// control roda com o endereço já resolvido, antes de cada conexão
+func control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !Allowed(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+	}
+	return nil
+}
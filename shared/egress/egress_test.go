@@ -0,0 +1,69 @@
+package egress
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestAllowed(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"8.8.8.8":          true,
+		"2001:4860::8888":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.0.10":     false,
+		"169.254.169.254":  false, // Metadados da nuvem
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"0.0.0.0":          false,
+		"100.64.0.1":       false,
+		"224.0.0.1":        false,
+		"::ffff:127.0.0.1": false, // IPv4 mapeado em IPv6
+	} {
+		if got := Allowed(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("Allowed(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	for rawURL, wantErr := range map[string]bool{
+		"https://lojista.example.com/pix/callback": false,
+		"https://8.8.8.8/hook":                     false,
+		"http://lojista.example.com/hook":          true,
+		"ftp://lojista.example.com/hook":           true,
+		"https:///hook":                            true,
+		"https://localhost/hook":                   true,
+		"https://api.localhost./hook":              true,
+		"https://127.0.0.1:8443/hook":              true,
+		"https://[::1]/hook":                       true,
+		"https://169.254.169.254/latest/meta-data": true,
+		"https://10.0.0.5/hook":                    true,
+	} {
+		if err := CheckURL(rawURL); (err != nil) != wantErr {
+			t.Errorf("CheckURL(%s) = %v, want error: %v", rawURL, err, wantErr)
+		}
+	}
+}
+
+func TestTransport_RefusesPrivateAddressesAtDial(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport()}
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("Get(%s) = %v, want ErrForbiddenAddress", server.URL, err)
+	}
+	if called {
+		t.Error("server reached through the egress transport")
+	}
+}
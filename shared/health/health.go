@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Estados reportados em /readyz
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+)
+
+// Check verifica uma dependência; um erro a marca como indisponível
+type Check func(ctx context.Context) error
+
+// DependencyStatus é o resultado de uma verificação
+type DependencyStatus struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report é a resposta de /readyz
+type Report struct {
+	Status       string                      `json:"status"`
+	Service      string                      `json:"service"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker executa as verificações de prontidão (readiness) do serviço
+type Checker struct {
+	service string
+	timeout time.Duration // Limite de cada verificação
+	checks  []namedCheck
+}
+
+func NewChecker(service string, timeout time.Duration) *Checker {
+	return &Checker{service: service, timeout: timeout}
+}
+
+// Add registra uma dependência. Deve ser chamado antes de servir /readyz.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Run verifica todas as dependências em paralelo, cada uma com o seu timeout
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Service: c.service, Dependencies: make(map[string]DependencyStatus, len(c.checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := nc.check(checkCtx)
+			status := DependencyStatus{Status: StatusOK, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+			if err != nil {
+				status.Status = StatusDown
+				status.Error = err.Error()
+			}
+
+			mu.Lock()
+			report.Dependencies[nc.name] = status
+			if err != nil {
+				report.Status = StatusDegraded
+			}
+			mu.Unlock()
+		}(nc)
+	}
+	wg.Wait()
+	return report
+}
+
+// Failing lista, em ordem, as dependências indisponíveis do relatório
+func (r Report) Failing() []string {
+	var names []string
+	for name, dep := range r.Dependencies {
+		if dep.Status != StatusOK {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReadyHandler responde /readyz: 200 com tudo disponível, 503 se alguma dependência falhou
+func (c *Checker) ReadyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+			slog.WarnContext(r.Context(), "readiness check failed", "dependencies", report.Failing())
+		}
+		writeJSON(w, status, report)
+	}
+}
+
+// LiveHandler responde /livez: o processo está de pé. Não verifica
+// dependências, para que uma falha no banco não reinicie o serviço.
+func LiveHandler(service string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK, "service": service})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReadyHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		dbErr      error
+		wantCode   int
+		wantStatus string
+	}{
+		{"all dependencies up", nil, http.StatusOK, StatusOK},
+		{"one dependency down", errors.New("connection refused"), http.StatusServiceUnavailable, StatusDegraded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker("payments-service", time.Second)
+			checker.Add("postgres", func(ctx context.Context) error { return tt.dbErr })
+			checker.Add("schema", func(ctx context.Context) error { return nil })
+
+			rec := httptest.NewRecorder()
+			checker.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			var report Report
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatal(err)
+			}
+			if report.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", report.Status, tt.wantStatus)
+			}
+			if len(report.Dependencies) != 2 || report.Dependencies["schema"].Status != StatusOK {
+				t.Errorf("dependencies = %+v", report.Dependencies)
+			}
+			if tt.dbErr != nil && report.Dependencies["postgres"].Error != tt.dbErr.Error() {
+				t.Errorf("postgres error = %q, want %q", report.Dependencies["postgres"].Error, tt.dbErr)
+			}
+		})
+	}
+}
+
+func TestChecker_TimesOutSlowDependency(t *testing.T) {
+	checker := NewChecker("payments-service", 20*time.Millisecond)
+	checker.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Run(context.Background())
+	if report.Status != StatusDegraded || report.Dependencies["slow"].Status != StatusDown {
+		t.Errorf("report = %+v, want slow dependency down", report)
+	}
+}
+
+func TestHTTPCheck(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	check := HTTPCheck(server.Client(), server.URL+"/livez")
+	if err := check(context.Background()); err != nil {
+		t.Errorf("healthy dependency: %v", err)
+	}
+	status = http.StatusInternalServerError
+	if err := check(context.Background()); err == nil {
+		t.Error("expected error for status 500")
+	}
+}
@@ -0,0 +1,47 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorMapping associa um erro de domínio ao status e ao código da resposta
+type ErrorMapping struct {
+	Err    error // Comparado com errors.Is
+	Status int
+	Code   string
+}
+
+// ErrorMap é a tabela central de erros de um deployable. A ordem importa: o
+// primeiro mapeamento que casa vence, então erros específicos vêm antes dos
+// genéricos que eles embrulham.
+type ErrorMap []ErrorMapping
+
+// Lookup encontra o mapeamento do erro
+func (m ErrorMap) Lookup(err error) (ErrorMapping, bool) {
+	for _, mapping := range m {
+		if errors.Is(err, mapping.Err) {
+			return mapping, true
+		}
+	}
+	return ErrorMapping{}, false
+}
+
+// Write responde com o problema correspondente ao erro. Os erros de requisição
+// (RequestError) já trazem status e código; os demais passam pela tabela. Erros fora dela
+// (banco fora do ar, timeouts...) viram 500 com uma mensagem genérica: o
+// detalhe fica no log de quem chamou, nunca vai para o cliente.
+func (m ErrorMap) Write(w http.ResponseWriter, r *http.Request, err error) {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		problem := NewProblem(requestErr.Status, requestErr.Code, requestErr.Detail)
+		problem.Errors = requestErr.Fields
+		problem.Write(w, r)
+		return
+	}
+	if mapping, ok := m.Lookup(err); ok {
+		WriteProblem(w, r, mapping.Status, mapping.Code, err.Error())
+		return
+	}
+	WriteProblem(w, r, http.StatusInternalServerError, CodeInternal, "internal server error")
+}
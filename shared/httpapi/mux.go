@@ -0,0 +1,57 @@
+package httpapi
+
+import "net/http"
+
+// Códigos estáveis das requisições que não casam com nenhuma rota
+const (
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+)
+
+// Mux é onde os handlers montam as rotas: *ServeMux no main e, nos testes,
+// um mux que também registra os padrões montados. Os padrões seguem o
+// http.ServeMux do Go 1.22 (ex.: "GET /pix/{id}").
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// ServeMux é o http.ServeMux com as respostas 404 e 405 no formato de
+// problema; o 405 mantém o header Allow calculado pelo mux
+type ServeMux struct {
+	*http.ServeMux
+}
+
+func NewServeMux() *ServeMux {
+	return &ServeMux{ServeMux: http.NewServeMux()}
+}
+
+func (mux *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler, pattern := mux.Handler(r)
+	if pattern != "" {
+		// O próprio mux despacha: é ele que preenche r.PathValue
+		mux.ServeMux.ServeHTTP(w, r)
+		return
+	}
+
+	// Sem rota: o handler do mux responderia em texto. Ele é executado só para
+	// descobrir o status (404 ou 405) e os métodos aceitos no caminho.
+	rec := &allowRecorder{header: http.Header{}, status: http.StatusOK}
+	handler.ServeHTTP(rec, r)
+	if rec.status == http.StatusMethodNotAllowed {
+		w.Header().Set("Allow", rec.header.Get("Allow"))
+		WriteProblem(w, r, http.StatusMethodNotAllowed, CodeMethodNotAllowed,
+			"method "+r.Method+" not allowed; allowed: "+rec.header.Get("Allow"))
+		return
+	}
+	WriteProblem(w, r, http.StatusNotFound, CodeNotFound, "no route for "+r.URL.Path)
+}
+
+// allowRecorder descarta o corpo e guarda o status e os headers da resposta
+type allowRecorder struct {
+	header http.Header
+	status int
+}
+
+func (r *allowRecorder) Header() http.Header         { return r.header }
+func (r *allowRecorder) Write(b []byte) (int, error) { return len(b), nil }
+func (r *allowRecorder) WriteHeader(status int)      { r.status = status }
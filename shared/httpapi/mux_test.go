@@ -0,0 +1,58 @@
+package httpapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServeMux(t *testing.T) {
+	mux := NewServeMux()
+	mux.Handle("GET /pix/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.PathValue("id"))
+	}))
+	mux.Handle("POST /pix", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	mux.Handle("GET /pix", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantCode   string
+		wantAllow  string
+		wantBody   string
+	}{
+		{"path value", http.MethodGet, "/pix/42", http.StatusOK, "", "", "42"},
+		{"head follows get", http.MethodHead, "/pix", http.StatusOK, "", "", ""},
+		{"post", http.MethodPost, "/pix", http.StatusCreated, "", "", ""},
+		{"wrong method", http.MethodDelete, "/pix", http.StatusMethodNotAllowed, CodeMethodNotAllowed, "GET, HEAD, POST", ""},
+		{"wrong method with id", http.MethodPost, "/pix/42", http.StatusMethodNotAllowed, CodeMethodNotAllowed, "GET, HEAD", ""},
+		{"unknown path", http.MethodGet, "/pix/42/refunds", http.StatusNotFound, CodeNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+			if tt.wantCode == "" {
+				if rec.Body.String() != tt.wantBody {
+					t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+				}
+				return
+			}
+			if problem := decodeProblem(t, rec); problem.Code != tt.wantCode || problem.Instance != tt.path {
+				t.Errorf("problem = %+v", problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Package httpapi reúne o que as APIs HTTP dos três deployables têm em comum:
+// as respostas de erro no formato RFC 7807 (application/problem+json), a
+// tradução dos erros de domínio para status e códigos estáveis, e o mux em que
+// as rotas são montadas, que responde 404 e 405 no mesmo formato.
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemContentType é o media type das respostas de erro (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix forma o "type" do problema a partir do código estável
+const ProblemTypePrefix = "urn:fintech:problem:"
+
+// Códigos estáveis dos erros que não vêm do domínio. Os clientes tratam os
+// erros pelo código; o "detail" é só para leitura humana e pode mudar.
+const (
+	CodeInvalidJSON  = "invalid_json"
+	CodeInvalidID    = "invalid_id"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeRateLimited  = "rate_limited"
+	CodeInternal     = "internal_error"
+)
+
+// Problem é o corpo das respostas de erro (RFC 7807), com o código estável
+// como membro de extensão
+type Problem struct {
+	Type     string `json:"type" example:"urn:fintech:problem:payment_not_found"`
+	Title    string `json:"title" example:"Not Found"`
+	Status   int    `json:"status" example:"404"`
+	Detail   string `json:"detail,omitempty" example:"payment not found"`
+	Instance string `json:"instance,omitempty" example:"/pix/42"`
+	Code     string `json:"code" example:"payment_not_found"`
+	// Erros por campo, nas falhas de validação (code validation_failed)
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem monta o problema; o título é a descrição padrão do status
+func NewProblem(status int, code, detail string) Problem {
+	return Problem{
+		Type:   ProblemTypePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// WriteProblem responde com o problema; a instância é o caminho da requisição
+func WriteProblem(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	NewProblem(status, code, detail).Write(w, r)
+}
+
+// Write responde com o problema; a instância é o caminho da requisição
+func (problem Problem) Write(w http.ResponseWriter, r *http.Request) {
+	if r != nil {
+		problem.Instance = r.URL.Path
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
@@ -0,0 +1,86 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) Problem {
+	t.Helper()
+	if got := rec.Header().Get("Content-Type"); got != ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ProblemContentType)
+	}
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	return problem
+}
+
+func TestWriteProblem(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pix/42", nil)
+	WriteProblem(rec, req, http.StatusNotFound, "payment_not_found", "payment not found")
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	want := Problem{
+		Type:     "urn:fintech:problem:payment_not_found",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   "payment not found",
+		Instance: "/pix/42",
+		Code:     "payment_not_found",
+	}
+	if got := decodeProblem(t, rec); !reflect.DeepEqual(got, want) {
+		t.Errorf("problem = %+v, want %+v", got, want)
+	}
+}
+
+var (
+	errNotFound = errors.New("not found")
+	errConflict = errors.New("conflict")
+	errSpecific = fmt.Errorf("%w: specific", errConflict)
+)
+
+func TestErrorMap_Write(t *testing.T) {
+	errorMap := ErrorMap{
+		{Err: errNotFound, Status: http.StatusNotFound, Code: "thing_not_found"},
+		{Err: errSpecific, Status: http.StatusGone, Code: "specific"},
+		{Err: errConflict, Status: http.StatusConflict, Code: "conflict"},
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{"sentinel", errNotFound, http.StatusNotFound, "thing_not_found", "not found"},
+		{"wrapped", fmt.Errorf("find: %w", errNotFound), http.StatusNotFound, "thing_not_found", "find: not found"},
+		{"first match wins", errSpecific, http.StatusGone, "specific", "conflict: specific"},
+		{"generic", errConflict, http.StatusConflict, "conflict", "conflict"},
+		{"unknown is hidden", errors.New("dial tcp: connection refused"), http.StatusInternalServerError, CodeInternal, "internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			errorMap.Write(rec, httptest.NewRequest(http.MethodGet, "/things/1", nil), tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			problem := decodeProblem(t, rec)
+			if problem.Code != tt.wantCode || problem.Detail != tt.wantDetail || problem.Status != tt.wantStatus {
+				t.Errorf("problem = %+v", problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// MaxBodyBytes limita o corpo das requisições JSON: os payloads da API têm
+// poucas centenas de bytes
+const MaxBodyBytes = 64 << 10
+
+// Códigos estáveis dos erros de requisição
+const (
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeRequestTooLarge      = "request_too_large"
+	CodeValidationFailed     = "validation_failed"
+)
+
+// FieldError descreve o problema de um campo do corpo da requisição
+type FieldError struct {
+	Field   string `json:"field" example:"amount"`
+	Message string `json:"message" example:"must be at least 0.01 with at most 2 decimal places"`
+}
+
+// RequestError é um erro da requisição (corpo, media type, validação) que já
+// sabe o status e o código da resposta; ErrorMap.Write o reconhece
+type RequestError struct {
+	Status int
+	Code   string
+	Detail string
+	Fields []FieldError
+}
+
+func (e *RequestError) Error() string {
+	return e.Detail
+}
+
+// DecodeJSON lê o corpo JSON em dst com as regras comuns às APIs: exige
+// Content-Type application/json (415), limita o tamanho a MaxBodyBytes (413)
+// e rejeita campos desconhecidos, tipos errados e conteúdo após o objeto (400).
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return &RequestError{Status: http.StatusUnsupportedMediaType, Code: CodeUnsupportedMediaType, Detail: "content type must be application/json"}
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return invalidJSON("request body must contain a single JSON object")
+	}
+	return nil
+}
+
+// decodeError traduz os erros do encoding/json em mensagens para o cliente
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return &RequestError{Status: http.StatusRequestEntityTooLarge, Code: CodeRequestTooLarge, Detail: fmt.Sprintf("request body must not exceed %d bytes", maxBytesErr.Limit)}
+	case errors.Is(err, io.EOF):
+		return invalidJSON("request body is required")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return invalidJSON("request body is truncated")
+	case errors.As(err, &syntaxErr):
+		return invalidJSON(fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset))
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return &RequestError{
+			Status: http.StatusBadRequest, Code: CodeValidationFailed, Detail: "request validation failed",
+			Fields: []FieldError{{Field: typeErr.Field, Message: "must be of type " + typeErr.Type.String()}},
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return &RequestError{
+			Status: http.StatusBadRequest, Code: CodeValidationFailed, Detail: "request validation failed",
+			Fields: []FieldError{{Field: field, Message: "unknown field"}},
+		}
+	}
+	return invalidJSON("invalid json")
+}
+
+func invalidJSON(detail string) *RequestError {
+	return &RequestError{Status: http.StatusBadRequest, Code: CodeInvalidJSON, Detail: detail}
+}
+
+// Validator acumula os erros por campo de uma requisição já decodificada
+type Validator struct {
+	fields []FieldError
+}
+
+// Check registra o erro do campo quando a condição não vale
+func (v *Validator) Check(ok bool, field, message string) {
+	if !ok {
+		v.fields = append(v.fields, FieldError{Field: field, Message: message})
+	}
+}
+
+// Amount valida um valor monetário: finito (sem NaN/Inf), de pelo menos um
+// centavo e sem frações de centavo
+func (v *Validator) Amount(field string, amount float64) {
+	v.Check(amount >= 0.01 && !math.IsInf(amount, 0) && cents(amount), field, "must be at least 0.01 with at most 2 decimal places")
+}
+
+// cents diz se o valor tem no máximo duas casas decimais. Compara a menor
+// representação decimal do float, a mesma que veio no JSON: multiplicar por 100
+// recusaria valores como 0.29 (28.999999999999996)
+func cents(amount float64) bool {
+	s := strconv.FormatFloat(amount, 'f', -1, 64)
+	dot := strings.IndexByte(s, '.')
+	return dot < 0 || len(s)-dot-1 <= 2
+}
+
+// Required valida um texto obrigatório
+func (v *Validator) Required(field, value string) {
+	v.Check(strings.TrimSpace(value) != "", field, "is required")
+}
+
+// Err devolve o RequestError com todos os campos inválidos, ou nil
+func (v *Validator) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &RequestError{Status: http.StatusBadRequest, Code: CodeValidationFailed, Detail: "request validation failed", Fields: v.fields}
+}
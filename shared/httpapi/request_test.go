@@ -0,0 +1,136 @@
+package httpapi
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type createRequest struct {
+	Amount  float64 `json:"amount"`
+	PayerID string  `json:"payer_id"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantCode    string
+		wantFields  []FieldError
+	}{
+		{"valid", "application/json", `{"amount": 10, "payer_id": "p1"}`, 0, "", nil},
+		{"charset parameter", "application/json; charset=utf-8", `{"amount": 10}`, 0, "", nil},
+		{"missing content type", "", `{"amount": 10}`, http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, nil},
+		{"form content type", "application/x-www-form-urlencoded", `amount=10`, http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, nil},
+		{"empty body", "application/json", ``, http.StatusBadRequest, CodeInvalidJSON, nil},
+		{"malformed", "application/json", `{"amount": }`, http.StatusBadRequest, CodeInvalidJSON, nil},
+		{"truncated", "application/json", `{"amount": 10`, http.StatusBadRequest, CodeInvalidJSON, nil},
+		{"trailing data", "application/json", `{"amount": 10} {}`, http.StatusBadRequest, CodeInvalidJSON, nil},
+		{"NaN literal", "application/json", `{"amount": NaN}`, http.StatusBadRequest, CodeInvalidJSON, nil},
+		{"unknown field", "application/json", `{"amount": 10, "amout": 10}`, http.StatusBadRequest, CodeValidationFailed, []FieldError{{Field: "amout", Message: "unknown field"}}},
+		{"wrong type", "application/json", `{"amount": "10"}`, http.StatusBadRequest, CodeValidationFailed, []FieldError{{Field: "amount", Message: "must be of type float64"}}},
+		{"out of range", "application/json", `{"amount": 1e400}`, http.StatusBadRequest, CodeValidationFailed, []FieldError{{Field: "amount", Message: "must be of type float64"}}},
+		{"too large", "application/json", `{"payer_id": "` + strings.Repeat("x", MaxBodyBytes) + `"}`, http.StatusRequestEntityTooLarge, CodeRequestTooLarge, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/pix", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			var dst createRequest
+			err := DecodeJSON(httptest.NewRecorder(), req, &dst)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				return
+			}
+
+			requestErr, ok := err.(*RequestError)
+			if !ok {
+				t.Fatalf("err = %v, want *RequestError", err)
+			}
+			if requestErr.Status != tt.wantStatus || requestErr.Code != tt.wantCode || !reflect.DeepEqual(requestErr.Fields, tt.wantFields) {
+				t.Errorf("err = %+v", requestErr)
+			}
+		})
+	}
+}
+
+func TestValidator(t *testing.T) {
+	var v Validator
+	v.Amount("amount", math.NaN())
+	v.Amount("fee", math.Inf(1))
+	v.Amount("ok", 10)
+	v.Required("payer_id", "  ")
+	v.Required("merchant", "m1")
+
+	err := v.Err()
+	requestErr, ok := err.(*RequestError)
+	if !ok {
+		t.Fatalf("err = %v, want *RequestError", err)
+	}
+	var fields []string
+	for _, f := range requestErr.Fields {
+		fields = append(fields, f.Field)
+	}
+	if !reflect.DeepEqual(fields, []string{"amount", "fee", "payer_id"}) {
+		t.Errorf("fields = %v", fields)
+	}
+
+	var valid Validator
+	valid.Amount("amount", 0.01)
+	if err := valid.Err(); err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+}
+
+func TestValidator_Amount(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+		valid  bool
+	}{
+		{"one cent", 0.01, true},
+		{"cents that are not exact in binary", 0.29, true},
+		{"one decimal place", 1.1, true},
+		{"whole amount", 150, true},
+		{"large amount with cents", 1_000_000.07, true},
+		{"zero", 0, false},
+		{"negative", -10, false},
+		{"below one cent", 0.001, false},
+		{"fraction of a cent", 10.005, false},
+		{"NaN", math.NaN(), false},
+		{"infinite", math.Inf(1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Validator
+			v.Amount("amount", tt.amount)
+			if err := v.Err(); (err == nil) != tt.valid {
+				t.Errorf("Amount(%v) err = %v, want valid = %v", tt.amount, err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestErrorMap_WritesRequestErrors(t *testing.T) {
+	var v Validator
+	v.Required("payer_id", "")
+	rec := httptest.NewRecorder()
+	ErrorMap{}.Write(rec, httptest.NewRequest(http.MethodPost, "/pix", nil), v.Err())
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+	problem := decodeProblem(t, rec)
+	if problem.Code != CodeValidationFailed || !reflect.DeepEqual(problem.Errors, []FieldError{{Field: "payer_id", Message: "is required"}}) {
+		t.Errorf("problem = %+v", problem)
+	}
+}
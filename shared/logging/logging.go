@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Chaves padronizadas nos logs (consultáveis no agregador)
+const (
+	KeyRequestID = "request_id"
+	KeyPaymentID = "payment_id"
+	KeyTraceID   = "trace_id"
+)
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	paymentIDKey
+)
+
+// WithRequestID associa o ID da requisição ao contexto
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID retorna o ID da requisição do contexto ("" se não houver)
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithPaymentID associa o pagamento ao contexto: todo log feito com ele traz o payment_id
+func WithPaymentID(ctx context.Context, paymentID int64) context.Context {
+	return context.WithValue(ctx, paymentIDKey, paymentID)
+}
+
+// contextHandler adiciona request_id, payment_id e trace_id do contexto a cada registro
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String(KeyRequestID, id))
+	}
+	if id, ok := ctx.Value(paymentIDKey).(int64); ok {
+		r.AddAttrs(slog.Int64(KeyPaymentID, id))
+	}
+	// Liga o log ao trace (OpenTelemetry) da requisição ou do fluxo
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String(KeyTraceID, sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// New cria um logger JSON que inclui o serviço e os IDs do contexto
+func New(w io.Writer, service string, level slog.Level) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	return slog.New(contextHandler{handler}).With("service", service)
+}
+
+// Setup configura o logger padrão (slog e o pacote log) a partir de LOG_LEVEL
+func Setup(service string) *slog.Logger {
+	logger := New(os.Stdout, service, ParseLevel(os.Getenv("LOG_LEVEL")))
+	slog.SetDefault(logger)
+	return logger
+}
+
+// ParseLevel converte debug/info/warn/error no nível do slog (padrão: info)
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
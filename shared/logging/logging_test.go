@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "test-service", slog.LevelInfo)
+
+	ctx := WithPaymentID(WithRequestID(context.Background(), "req-123"), 42)
+	logger.InfoContext(ctx, "payment created", "amount", 10.5)
+	logger.DebugContext(ctx, "below level")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1: %s", len(lines), buf.String())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["service"] != "test-service" || entry[KeyRequestID] != "req-123" || entry[KeyPaymentID] != float64(42) || entry["msg"] != "payment created" {
+		t.Errorf("entry = %v", entry)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	// ID recebido é aceito e devolvido
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if seen != "abc-123" || rec.Header().Get(RequestIDHeader) != "abc-123" {
+		t.Errorf("seen = %q, header = %q", seen, rec.Header().Get(RequestIDHeader))
+	}
+
+	// Sem ID (ou com ID inválido) um novo é gerado
+	for _, incoming := range []string{"", "bad id\nwith newline", strings.Repeat("a", 200)} {
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, incoming)
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if len(seen) != 32 || seen == incoming || rec.Header().Get(RequestIDHeader) != seen {
+			t.Errorf("incoming %q: seen = %q", incoming, seen)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fintech-shared/httpapi"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute agrupa as requisições que não casam com nenhuma rota,
+// evitando um label por URL desconhecida
+const unmatchedRoute = "unmatched"
+
+// InstrumentMux conta e mede as requisições por rota. A rota é o padrão
+// registrado no mux (ex.: "GET /payments/pix/{id}"), não a URL: IDs no caminho não
+// viram labels.
+func (r *Registry) InstrumentMux(mux *httpapi.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, route := mux.Handler(req)
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		mux.ServeHTTP(rec, req)
+
+		r.httpRequests.WithLabelValues(route, req.Method, strconv.Itoa(rec.status)).Inc()
+		r.httpDuration.WithLabelValues(route, req.Method).Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusRecorder guarda o status da resposta. Repassa Flush (SSE) e Hijack (WebSocket).
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response does not implement http.Hijacker")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
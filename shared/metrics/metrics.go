@@ -0,0 +1,54 @@
+// Package metrics reúne as métricas Prometheus dos deployables. Registry tem
+// as métricas HTTP e do runtime; cada deployable acrescenta os grupos do seu
+// domínio (Payments, NotificationClient, Notifications), que implementam as
+// interfaces de métricas do domínio sem que ele importe o Prometheus.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry é o registry próprio do deployable, com as métricas HTTP
+type Registry struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+}
+
+// New cria o registry com as métricas HTTP, do runtime Go e do processo
+func New() *Registry {
+	r := &Registry{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by route, method and status code.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route and method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+	}
+
+	r.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		r.httpRequests, r.httpDuration,
+	)
+	return r
+}
+
+// Register adiciona coletores extras (ex.: estatísticas do pool do banco)
+func (r *Registry) Register(collectors ...prometheus.Collector) {
+	r.registry.MustRegister(collectors...)
+}
+
+// Handler expõe as métricas no formato do Prometheus (rota /metrics)
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{Registry: r.registry})
+}
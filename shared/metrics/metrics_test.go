@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"fintech-shared/httpapi"
+	"fintech-shared/payments"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// scrape devolve o corpo do /metrics
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func expectSeries(t *testing.T, body string, want ...string) {
+	t.Helper()
+	for _, series := range want {
+		if !strings.Contains(body, series) {
+			t.Errorf("/metrics is missing %q", series)
+		}
+	}
+}
+
+func TestInstrumentMux(t *testing.T) {
+	r := New()
+	mux := httpapi.NewServeMux()
+	mux.HandleFunc("GET /payments/pix/{id}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "payment not found", http.StatusNotFound)
+	})
+	handler := r.InstrumentMux(mux)
+
+	// IDs diferentes caem na mesma rota
+	for _, path := range []string{"/payments/pix/1", "/payments/pix/2", "/nao-existe"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := testutil.ToFloat64(r.httpRequests.WithLabelValues("GET /payments/pix/{id}", "GET", "404")); got != 2 {
+		t.Errorf("route requests = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(r.httpRequests.WithLabelValues(unmatchedRoute, "GET", "404")); got != 1 {
+		t.Errorf("unmatched requests = %v, want 1", got)
+	}
+	expectSeries(t, scrape(t, r), `go_goroutines`)
+}
+
+func TestPaymentMetrics(t *testing.T) {
+	r := New()
+	m := NewPayments(r)
+
+	m.PaymentStatus(payments.StatusCreated)
+	m.PaymentStatus(payments.StatusSettled)
+	m.PaymentFailed("authorize")
+	m.PaymentSettled(6 * time.Second)
+	m.SubscribersChanged(1)
+	m.SubscribersChanged(1)
+	m.SubscribersChanged(-1)
+	m.EventDropped("sse")
+
+	if got := testutil.ToFloat64(m.subscribers); got != 1 {
+		t.Errorf("subscribers = %v, want 1", got)
+	}
+	expectSeries(t, scrape(t, r),
+		`pix_payments_total{status="CREATED"} 1`,
+		`pix_payments_total{status="SETTLED"} 1`,
+		`pix_payment_failures_total{stage="authorize"} 1`,
+		`pix_payment_settlement_seconds_count 1`,
+		`payment_events_dropped_total{sink="sse"} 1`,
+	)
+}
+
+func TestNotificationMetrics(t *testing.T) {
+	r := New()
+	NewNotificationClient(r).NotificationCall("PAYMENT_SETTLED", "ok", 20*time.Millisecond)
+	NewNotifications(r).NotificationSent("PAYMENT_SETTLED")
+
+	expectSeries(t, scrape(t, r),
+		`notification_client_requests_total{outcome="ok",type="PAYMENT_SETTLED"} 1`,
+		`notification_client_request_duration_seconds_count{type="PAYMENT_SETTLED"} 1`,
+		`notifications_sent_total{type="PAYMENT_SETTLED"} 1`,
+	)
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NotificationClient são as métricas das chamadas ao serviço de notificações,
+// feitas pelo payments-service
+type NotificationClient struct {
+	calls    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewNotificationClient cria as métricas do cliente de notificações no registry
+func NewNotificationClient(r *Registry) *NotificationClient {
+	m := &NotificationClient{
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "notification_client_requests_total",
+			Help: "Calls to the notifications service by notification type and outcome.",
+		}, []string{"type", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "notification_client_request_duration_seconds",
+			Help:    "Latency of calls to the notifications service.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"type"}),
+	}
+	r.Register(m.calls, m.duration)
+	return m
+}
+
+func (m *NotificationClient) NotificationCall(notificationType, outcome string, elapsed time.Duration) {
+	m.calls.WithLabelValues(notificationType, outcome).Inc()
+	m.duration.WithLabelValues(notificationType).Observe(elapsed.Seconds())
+}
+
+// Notifications são as métricas do notifications-service
+type Notifications struct {
+	sent *prometheus.CounterVec
+}
+
+// NewNotifications cria as métricas de notificações enviadas no registry
+func NewNotifications(r *Registry) *Notifications {
+	m := &Notifications{
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "notifications_sent_total",
+			Help: "Notifications sent by type.",
+		}, []string{"type"}),
+	}
+	r.Register(m.sent)
+	return m
+}
+
+func (m *Notifications) NotificationSent(notificationType string) {
+	m.sent.WithLabelValues(notificationType).Inc()
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"fintech-shared/payments"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Payments são as métricas do fluxo PIX e do broadcaster de eventos.
+// Implementa payments.PaymentMetrics e payments.BroadcasterMetrics.
+type Payments struct {
+	payments   *prometheus.CounterVec
+	failures   *prometheus.CounterVec
+	settlement prometheus.Histogram
+
+	subscribers   prometheus.Gauge
+	droppedEvents *prometheus.CounterVec
+}
+
+var (
+	_ payments.PaymentMetrics     = (*Payments)(nil)
+	_ payments.BroadcasterMetrics = (*Payments)(nil)
+)
+
+// NewPayments cria as métricas de pagamentos no registry
+func NewPayments(r *Registry) *Payments {
+	m := &Payments{
+		payments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pix_payments_total",
+			Help: "PIX payments that reached each status.",
+		}, []string{"status"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pix_payment_failures_total",
+			Help: "PIX payment flow failures by stage.",
+		}, []string{"stage"}),
+		settlement: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name: "pix_payment_settlement_seconds",
+			Help: "End-to-end time from payment creation to settlement.",
+			// O fluxo simulado leva alguns segundos; com revisão manual, até o prazo (30m)
+			Buckets: []float64{1, 2.5, 5, 7.5, 10, 15, 30, 60, 300, 900, 1800, 3600},
+		}),
+		subscribers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "payment_event_subscribers",
+			Help: "Clients subscribed to payment events (SSE and WebSocket).",
+		}),
+		droppedEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "payment_events_dropped_total",
+			Help: "Payment events dropped because a consumer was full, by sink.",
+		}, []string{"sink"}),
+	}
+	r.Register(m.payments, m.failures, m.settlement, m.subscribers, m.droppedEvents)
+	return m
+}
+
+func (m *Payments) PaymentStatus(status payments.PaymentStatus) {
+	m.payments.WithLabelValues(string(status)).Inc()
+}
+
+func (m *Payments) PaymentFailed(stage string) {
+	m.failures.WithLabelValues(stage).Inc()
+}
+
+func (m *Payments) PaymentSettled(elapsed time.Duration) {
+	m.settlement.Observe(elapsed.Seconds())
+}
+
+func (m *Payments) SubscribersChanged(delta int) {
+	m.subscribers.Add(float64(delta))
+}
+
+func (m *Payments) EventDropped(sink string) {
+	m.droppedEvents.WithLabelValues(sink).Inc()
+}
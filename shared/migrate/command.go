@@ -0,0 +1,79 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RunCommand executa o subcomando "migrate" de um deployable contra o banco
+// em DATABASE_URL:
+//   - up: aplica as migrações pendentes
+//   - down: reverte a última migração aplicada
+//   - status: lista as migrações e quando cada uma foi aplicada
+//
+// program aparece na mensagem de uso; newMigrator cria o migrator com as
+// migrações embutidas no binário.
+func RunCommand(program string, args []string, newMigrator func(*pgxpool.Pool) (*Migrator, error)) error {
+	usage := errors.New("usage: " + program + " migrate up|down|status")
+	if len(args) != 1 {
+		return usage
+	}
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		return errors.New("DATABASE_URL is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	migrator, err := newMigrator(pool)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			slog.Info("migration applied", "version", m.Version, "name", m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			slog.Info("schema already up to date")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx)
+		if reverted != nil {
+			slog.Info("migration reverted", "version", reverted.Version, "name", reverted.Name)
+		} else if err == nil {
+			slog.Info("no migration to revert")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d  %-30s  %s\n", s.Version, s.Name, applied)
+		}
+		return nil
+	}
+	return usage
+}
@@ -0,0 +1,82 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "sorted by version",
+			files: fstest.MapFS{
+				"sql/0002_b.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+				"sql/0002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+				"sql/0001_a.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+				"sql/0001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "missing down",
+			files: fstest.MapFS{
+				"sql/0001_a.up.sql": {Data: []byte("CREATE TABLE a ();")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid file name",
+			files: fstest.MapFS{
+				"sql/create_a.sql": {Data: []byte("CREATE TABLE a ();")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "same version with two names",
+			files: fstest.MapFS{
+				"sql/0001_a.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+				"sql/0001_b.down.sql": {Data: []byte("DROP TABLE a;")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := Load(tt.files, "sql")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, m := range migrations {
+				names = append(names, m.Name)
+			}
+			if len(names) != len(tt.want) || names[0] != tt.want[0] || names[1] != tt.want[1] {
+				t.Errorf("names = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "a", Up: "CREATE TABLE a ();\n"},
+		{Version: 2, Name: "b", Up: "ALTER TABLE a ADD COLUMN b TEXT;"},
+	}
+	want := "-- Schema\n" +
+		"\n-- 0001_a\nCREATE TABLE a ();\n" +
+		"\n-- 0002_b\nALTER TABLE a ADD COLUMN b TEXT;\n"
+	if got := Schema("-- Schema\n", migrations); got != want {
+		t.Errorf("Schema() = %q, want %q", got, want)
+	}
+}
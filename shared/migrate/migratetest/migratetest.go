@@ -0,0 +1,77 @@
+// Package migratetest testa as migrações de cada deployable: mantém os schemas
+// de referência (db/init-*.sql) gerados a partir delas e cria bancos migrados
+// para as suítes de conformidade dos repositórios.
+package migratetest
+
+import (
+	"context"
+	"fintech-shared/migrate"
+	"fintech-shared/pgtest"
+	"flag"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var update = flag.Bool("update-schema", false, "rewrite the db/init-*.sql reference schemas from the migrations")
+
+// CheckSchemaFile compara db/<name> com o schema gerado pelas migrações de
+// dir em fsys. Com -update-schema, reescreve o arquivo.
+func CheckSchemaFile(t *testing.T, fsys fs.FS, dir, name, header string) {
+	t.Helper()
+	migrations, err := migrate.Load(fsys, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := migrate.Schema(header, migrations)
+
+	path := pgtest.SchemaFile(t, name)
+	if *update {
+		if err := os.WriteFile(path, []byte(want), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("db/%s is out of date with the migrations; run go test with -update-schema", name)
+	}
+}
+
+// NewPool cria um banco vazio no servidor do pgtest e aplica as migrações do
+// deployable. Antes de devolver o pool, reverte todas e aplica de novo: um
+// down que não desfaz o up (ou falha) quebra o teste.
+func NewPool(t *testing.T, newMigrator func(*pgxpool.Pool) (*migrate.Migrator, error)) *pgxpool.Pool {
+	t.Helper()
+	ctx := context.Background()
+	pool := pgtest.NewDatabase(t)
+	migrator, err := newMigrator(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		reverted, err := migrator.Down(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reverted == nil {
+			break
+		}
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
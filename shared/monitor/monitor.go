@@ -0,0 +1,37 @@
+// Package monitor é a página HTML de acompanhamento dos pagamentos em tempo
+// real (SSE), a mesma no monólito e no payments-service
+package monitor
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"net/http"
+)
+
+//go:embed monitor.html
+var pageHTML string
+
+var pageTemplate = template.Must(template.New("monitor").Parse(pageHTML))
+
+// Page descreve o deployable que serve a página
+type Page struct {
+	// Prefixo da API de pagamentos: "/payments/pix" (monólito) ou "/pix" (microsserviço)
+	APIBase string
+	// Nome exibido no título (ex.: "Microsserviços"); vazio não exibe nada
+	Deployable string
+}
+
+// Handler serve a página. Ela é montada uma vez: a configuração não muda.
+func Handler(page Page) http.Handler {
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, page); err != nil {
+		panic("monitor: " + err.Error())
+	}
+	html := buf.Bytes()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(html)
+	})
+}
@@ -0,0 +1,30 @@
+package monitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		page  Page
+		title string
+		api   string
+	}{
+		{Page{APIBase: "/payments/pix"}, "<title>Monitor PIX - Tempo Real</title>", `const apiBase = '\/payments\/pix';`},
+		{Page{APIBase: "/pix", Deployable: "Microsserviços"}, "<title>Monitor PIX - Tempo Real (Microsserviços)</title>", `const apiBase = '\/pix';`},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		Handler(tt.page).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/monitor", nil))
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+			t.Errorf("%s: status %d, content type %q", tt.page.APIBase, rec.Code, rec.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(body, tt.title) || !strings.Contains(body, tt.api) {
+			t.Errorf("%s: page without title %q or api base %q", tt.page.APIBase, tt.title, tt.api)
+		}
+	}
+}
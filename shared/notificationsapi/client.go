@@ -0,0 +1,59 @@
+package notificationsapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// RequestEditor ajusta a requisição antes do envio (autenticação, correlação)
+type RequestEditor func(req *http.Request) error
+
+// Client é o stub do consumidor: monta as requisições a partir do contrato
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient cria o cliente; httpClient nil usa http.DefaultClient
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: baseURL, httpClient: httpClient}
+}
+
+// CreateNotification envia POST /notifications e retorna o status HTTP (0 se
+// não houve resposta). O provedor responde CreatedStatus; 200 também é aceito.
+func (c *Client) CreateNotification(ctx context.Context, body CreateNotificationRequest, editors ...RequestEditor) (int, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+NotificationsPath, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, edit := range editors {
+		if err := edit(req); err != nil {
+			return 0, err
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != CreatedStatus {
+		return resp.StatusCode, fmt.Errorf("notification service returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,63 @@
+// Package notificationsapi é o contrato da API do notifications-service: os
+// tipos trafegados, os caminhos e os status esperados. O payments-service
+// (consumidor) e o notifications-service (provedor) usam só estas definições.
+package notificationsapi
+
+import "time"
+
+// Caminhos da API
+const (
+	NotificationsPath = "/notifications"
+	NotificationPath  = "/notifications/{id}" // padrão do ServeMux; o ID vem em r.PathValue("id")
+)
+
+// CreatedStatus é o status de POST /notifications com sucesso
+const CreatedStatus = 201
+
+// NotificationType identifica o evento do pagamento que gerou a notificação
+type NotificationType string
+
+const (
+	TypePaymentCreated       NotificationType = "PAYMENT_CREATED"
+	TypePaymentPendingReview NotificationType = "PAYMENT_PENDING_REVIEW"
+	TypePaymentRejected      NotificationType = "PAYMENT_REJECTED"
+	TypePaymentAuthorized    NotificationType = "PAYMENT_AUTHORIZED"
+	TypePaymentSettled       NotificationType = "PAYMENT_SETTLED"
+)
+
+// NotificationTypes lista os tipos aceitos pelo provedor
+var NotificationTypes = []NotificationType{
+	TypePaymentCreated,
+	TypePaymentPendingReview,
+	TypePaymentRejected,
+	TypePaymentAuthorized,
+	TypePaymentSettled,
+}
+
+// Valid indica se o tipo é um dos aceitos pelo provedor
+func (t NotificationType) Valid() bool {
+	for _, known := range NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateNotificationRequest é o corpo de POST /notifications
+type CreateNotificationRequest struct {
+	PaymentID int64            `json:"payment_id" example:"42"`
+	Amount    float64          `json:"amount" example:"150.5"`
+	Type      NotificationType `json:"type" enums:"PAYMENT_CREATED,PAYMENT_PENDING_REVIEW,PAYMENT_REJECTED,PAYMENT_AUTHORIZED,PAYMENT_SETTLED"`
+}
+
+// Notification é a notificação retornada pelo POST e pelos GETs
+type Notification struct {
+	ID        int64            `json:"id"`
+	PaymentID int64            `json:"payment_id"`
+	Type      NotificationType `json:"type" enums:"PAYMENT_CREATED,PAYMENT_PENDING_REVIEW,PAYMENT_REJECTED,PAYMENT_AUTHORIZED,PAYMENT_SETTLED"`
+	Recipient string           `json:"recipient"`
+	Message   string           `json:"message"`
+	Status    string           `json:"status"`
+	CreatedAt time.Time        `json:"created_at"`
+}
@@ -0,0 +1,91 @@
+package contracttest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// MockProvider responde às requisições que casam com alguma interação do pact.
+// Requisição sem interação correspondente recebe 500 e é registrada como falha.
+type MockProvider struct {
+	URL string
+
+	pact     Pact
+	mu       sync.Mutex
+	used     map[int]bool
+	failures []string
+}
+
+// NewMockProvider sobe o provedor simulado; ele é encerrado no fim do teste
+func NewMockProvider(t testing.TB, pact Pact) *MockProvider {
+	t.Helper()
+	m := &MockProvider{pact: pact, used: map[int]bool{}}
+	server := httptest.NewServer(http.HandlerFunc(m.serve))
+	t.Cleanup(server.Close)
+	m.URL = server.URL
+	return m
+}
+
+func (m *MockProvider) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	var mismatches []string
+	for i, interaction := range m.pact.Interactions {
+		diffs := matchRequest(interaction.Request, r, body)
+		if len(diffs) == 0 {
+			m.mu.Lock()
+			m.used[i] = true
+			m.mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(interaction.Response.Status)
+			_, _ = w.Write(interaction.Response.Body)
+			return
+		}
+		mismatches = append(mismatches, fmt.Sprintf("  %q: %s", interaction.Description, strings.Join(diffs, "; ")))
+	}
+
+	m.mu.Lock()
+	m.failures = append(m.failures, fmt.Sprintf("%s %s %s matches no interaction:\n%s", r.Method, r.URL.Path, body, strings.Join(mismatches, "\n")))
+	m.mu.Unlock()
+	http.Error(w, "no matching interaction", http.StatusInternalServerError)
+}
+
+// Verify falha o teste se houve requisição fora do pact ou interação não exercitada
+func (m *MockProvider) Verify(t testing.TB) {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, failure := range m.failures {
+		t.Error(failure)
+	}
+	for i, interaction := range m.pact.Interactions {
+		if !m.used[i] {
+			t.Errorf("interaction %q was never exercised by %s", interaction.Description, m.pact.Consumer)
+		}
+	}
+}
+
+func matchRequest(expected Request, r *http.Request, body []byte) []string {
+	var diffs []string
+	if r.Method != expected.Method {
+		diffs = append(diffs, fmt.Sprintf("method %s, want %s", r.Method, expected.Method))
+	}
+	if r.URL.Path != expected.Path {
+		diffs = append(diffs, fmt.Sprintf("path %s, want %s", r.URL.Path, expected.Path))
+	}
+	for name, value := range expected.Headers {
+		if got := r.Header.Get(name); got != value {
+			diffs = append(diffs, fmt.Sprintf("header %s = %q, want %q", name, got, value))
+		}
+	}
+	bodyDiffs, err := compareBody(expected.Body, body, true)
+	if err != nil {
+		return append(diffs, err.Error())
+	}
+	return append(diffs, bodyDiffs...)
+}
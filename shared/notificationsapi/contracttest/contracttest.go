@@ -0,0 +1,150 @@
+// Package contracttest verifica o contrato orientado ao consumidor (pact) da
+// API de notificações. O pact registra as interações de que o consumidor
+// depende; o consumidor testa o próprio cliente contra um provedor simulado
+// (MockProvider) e o provedor reproduz as mesmas interações no handler real
+// (VerifyProvider). Mudar o formato do corpo ou o status em qualquer um dos
+// lados quebra o teste desse lado.
+package contracttest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Pact é o conjunto de interações entre um consumidor e um provedor
+type Pact struct {
+	Consumer     string        `json:"consumer"`
+	Provider     string        `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Description string   `json:"description"`
+	Request     Request  `json:"request"`
+	Response    Response `json:"response"`
+}
+
+type Request struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body"`
+}
+
+type Response struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+//go:embed pacts/payments-service.json
+var paymentsServicePact []byte
+
+// PaymentsService é o pact do payments-service com o notifications-service
+func PaymentsService() Pact {
+	var pact Pact
+	if err := json.Unmarshal(paymentsServicePact, &pact); err != nil {
+		panic(fmt.Sprintf("invalid pact payments-service.json: %v", err))
+	}
+	return pact
+}
+
+// Regras de comparação dos corpos: números casam pelo tipo (IDs e valores
+// variam), strings e booleanos pelo valor (tipos de notificação e status são
+// enumerados). No modo strict, usado nas requisições, campos a mais também são
+// diferença; nas respostas o provedor pode devolver campos que o consumidor ignora.
+func compareBody(expected, actual json.RawMessage, strict bool) ([]string, error) {
+	if len(expected) == 0 {
+		return nil, nil
+	}
+	var want, got any
+	if err := json.Unmarshal(expected, &want); err != nil {
+		return nil, fmt.Errorf("invalid expected body: %w", err)
+	}
+	if err := json.Unmarshal(actual, &got); err != nil {
+		return []string{fmt.Sprintf("body is not valid JSON: %v", err)}, nil
+	}
+	return compareValue("$", want, got, strict), nil
+}
+
+func compareValue(path string, want, got any, strict bool) []string {
+	switch want := want.(type) {
+	case map[string]any:
+		obj, ok := got.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected object, got %s", path, jsonType(got))}
+		}
+		var diffs []string
+		for _, key := range sortedKeys(want) {
+			value, ok := obj[key]
+			if !ok {
+				diffs = append(diffs, fmt.Sprintf("%s.%s: missing", path, key))
+				continue
+			}
+			diffs = append(diffs, compareValue(path+"."+key, want[key], value, strict)...)
+		}
+		if strict {
+			for _, key := range sortedKeys(obj) {
+				if _, ok := want[key]; !ok {
+					diffs = append(diffs, fmt.Sprintf("%s.%s: unexpected field", path, key))
+				}
+			}
+		}
+		return diffs
+	case []any:
+		arr, ok := got.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected array, got %s", path, jsonType(got))}
+		}
+		// Cada item deve ter a forma do primeiro item esperado
+		var diffs []string
+		if len(want) > 0 {
+			for i, item := range arr {
+				diffs = append(diffs, compareValue(fmt.Sprintf("%s[%d]", path, i), want[0], item, strict)...)
+			}
+		}
+		return diffs
+	case float64:
+		if _, ok := got.(float64); !ok {
+			return []string{fmt.Sprintf("%s: expected number, got %s", path, jsonType(got))}
+		}
+		return nil
+	default:
+		if jsonType(want) != jsonType(got) {
+			return []string{fmt.Sprintf("%s: expected %s, got %s", path, jsonType(want), jsonType(got))}
+		}
+		if want != got {
+			return []string{fmt.Sprintf("%s: expected %v, got %v", path, want, got)}
+		}
+		return nil
+	}
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
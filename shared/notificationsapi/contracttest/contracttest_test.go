@@ -0,0 +1,69 @@
+package contracttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fintech-shared/notificationsapi"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// O pact só pode usar o que o contrato define: caminhos, campos, tipos e status
+func TestPaymentsServicePact_MatchesContract(t *testing.T) {
+	pact := PaymentsService()
+	if len(pact.Interactions) == 0 {
+		t.Fatal("pact has no interactions")
+	}
+	for _, interaction := range pact.Interactions {
+		if interaction.Request.Method != http.MethodPost || interaction.Request.Path != notificationsapi.NotificationsPath {
+			t.Errorf("%q: unexpected request %s %s", interaction.Description, interaction.Request.Method, interaction.Request.Path)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(interaction.Request.Body))
+		decoder.DisallowUnknownFields()
+		var req notificationsapi.CreateNotificationRequest
+		if err := decoder.Decode(&req); err != nil {
+			t.Errorf("%q: request body does not fit the contract: %v", interaction.Description, err)
+		}
+		if !slices.Contains(notificationsapi.NotificationTypes, req.Type) {
+			t.Errorf("%q: unknown notification type %q", interaction.Description, req.Type)
+		}
+		if interaction.Response.Status != notificationsapi.CreatedStatus {
+			t.Errorf("%q: response status = %d, want %d", interaction.Description, interaction.Response.Status, notificationsapi.CreatedStatus)
+		}
+		decoder = json.NewDecoder(bytes.NewReader(interaction.Response.Body))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&notificationsapi.Notification{}); err != nil {
+			t.Errorf("%q: response body does not fit the contract: %v", interaction.Description, err)
+		}
+	}
+}
+
+func TestCompareBody(t *testing.T) {
+	expected := json.RawMessage(`{"payment_id":42,"amount":150.5,"type":"PAYMENT_CREATED"}`)
+	tests := []struct {
+		name   string
+		actual string
+		strict bool
+		want   []string
+	}{
+		{"same shape other numbers", `{"payment_id":7,"amount":1,"type":"PAYMENT_CREATED"}`, true, nil},
+		{"number as string", `{"payment_id":"42","amount":150.5,"type":"PAYMENT_CREATED"}`, true, []string{"$.payment_id: expected number, got string"}},
+		{"other type value", `{"payment_id":42,"amount":150.5,"type":"PAYMENT_SETTLED"}`, true, []string{"$.type: expected PAYMENT_CREATED, got PAYMENT_SETTLED"}},
+		{"missing field", `{"payment_id":42,"type":"PAYMENT_CREATED"}`, true, []string{"$.amount: missing"}},
+		{"extra field strict", `{"payment_id":42,"amount":150.5,"type":"PAYMENT_CREATED","currency":"BRL"}`, true, []string{"$.currency: unexpected field"}},
+		{"extra field lenient", `{"payment_id":42,"amount":150.5,"type":"PAYMENT_CREATED","currency":"BRL"}`, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compareBody(expected, json.RawMessage(tt.actual), tt.strict)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("compareBody = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
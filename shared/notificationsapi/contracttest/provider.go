@@ -0,0 +1,41 @@
+package contracttest
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// VerifyProvider reproduz cada interação do pact no handler do provedor e
+// confere o status e a forma da resposta. prepare ajusta a requisição antes do
+// envio, por exemplo com as credenciais que o pact não registra.
+func VerifyProvider(t *testing.T, pact Pact, handler http.Handler, prepare func(*http.Request)) {
+	t.Helper()
+	for _, interaction := range pact.Interactions {
+		interaction := interaction
+		t.Run(interaction.Description, func(t *testing.T) {
+			req := httptest.NewRequest(interaction.Request.Method, interaction.Request.Path, bytes.NewReader(interaction.Request.Body))
+			for name, value := range interaction.Request.Headers {
+				req.Header.Set(name, value)
+			}
+			if prepare != nil {
+				prepare(req)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != interaction.Response.Status {
+				t.Fatalf("%s: status = %d, want %d (body: %s)", pact.Provider, rec.Code, interaction.Response.Status, rec.Body.String())
+			}
+			diffs, err := compareBody(interaction.Response.Body, rec.Body.Bytes(), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, diff := range diffs {
+				t.Errorf("%s response: %s", pact.Provider, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package notificationsapi
+
+import (
+	"encoding/json"
+	"fintech-shared/httpapi"
+	"net/http"
+)
+
+// DecodeCreateNotification lê e valida o corpo de POST /notifications. Os
+// erros são *httpapi.RequestError, com os campos inválidos.
+func DecodeCreateNotification(w http.ResponseWriter, r *http.Request) (CreateNotificationRequest, error) {
+	var req CreateNotificationRequest
+	if err := httpapi.DecodeJSON(w, r, &req); err != nil {
+		return req, err
+	}
+	return req, req.Validate()
+}
+
+// Validate confere os campos do pedido de notificação
+func (req CreateNotificationRequest) Validate() error {
+	var v httpapi.Validator
+	v.Check(req.PaymentID > 0, "payment_id", "must be greater than 0")
+	v.Amount("amount", req.Amount)
+	v.Check(req.Type.Valid(), "type", "must be one of PAYMENT_CREATED, PAYMENT_PENDING_REVIEW, PAYMENT_REJECTED, PAYMENT_AUTHORIZED, PAYMENT_SETTLED")
+	return v.Err()
+}
+
+// WriteCreated responde ao POST /notifications com a notificação criada
+func WriteCreated(w http.ResponseWriter, notification Notification) {
+	WriteJSON(w, CreatedStatus, notification)
+}
+
+// WriteJSON responde com o payload em JSON
+func WriteJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
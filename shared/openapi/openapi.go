@@ -0,0 +1,261 @@
+// Package openapi publica a documentação das APIs em OpenAPI 3. As anotações
+// dos handlers continuam sendo lidas pelo swag, que gera Swagger 2.0; Convert
+// traduz esse documento para OpenAPI 3.0 e Handler o serve em /openapi.json.
+package openapi
+
+import (
+	"encoding/json"
+	"fintech-shared/httpapi"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Version é a versão do OpenAPI gerada por Convert
+const Version = "3.0.3"
+
+// Path é onde cada deployable publica a especificação
+const Path = "/openapi.json"
+
+// Campos do parâmetro Swagger 2.0 que passam para o schema no OpenAPI 3
+var schemaFields = []string{"type", "format", "items", "enum", "default", "minimum", "maximum", "minLength", "maxLength", "pattern"}
+
+// Convert traduz um documento Swagger 2.0 para OpenAPI 3.0. O servidor é
+// relativo ao basePath: a documentação vale para o host em que é servida.
+func Convert(swagger2 []byte) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(swagger2, &doc); err != nil {
+		return nil, fmt.Errorf("invalid swagger document: %w", err)
+	}
+	if doc["swagger"] != "2.0" {
+		return nil, fmt.Errorf("unsupported swagger version: %v", doc["swagger"])
+	}
+
+	basePath, _ := doc["basePath"].(string)
+	if basePath == "" {
+		basePath = "/"
+	}
+	out := map[string]any{
+		"openapi": Version,
+		"info":    doc["info"],
+		"servers": []any{map[string]any{"url": basePath}},
+		"paths":   map[string]any{},
+	}
+	if tags, ok := doc["tags"]; ok {
+		out["tags"] = tags
+	}
+	if security, ok := doc["security"]; ok {
+		out["security"] = security
+	}
+
+	consumes := stringList(doc["consumes"])
+	produces := stringList(doc["produces"])
+	paths, _ := doc["paths"].(map[string]any)
+	for path, item := range paths {
+		operations, _ := item.(map[string]any)
+		converted := map[string]any{}
+		for method, op := range operations {
+			operation, ok := op.(map[string]any)
+			if !ok {
+				continue
+			}
+			converted[method] = convertOperation(operation, consumes, produces)
+		}
+		out["paths"].(map[string]any)[path] = converted
+	}
+
+	components := map[string]any{}
+	if definitions, ok := doc["definitions"]; ok {
+		components["schemas"] = definitions
+	}
+	if schemes, ok := doc["securityDefinitions"].(map[string]any); ok {
+		components["securitySchemes"] = convertSecuritySchemes(schemes)
+	}
+	if len(components) > 0 {
+		out["components"] = components
+	}
+
+	return json.Marshal(rewriteRefs(out))
+}
+
+func convertOperation(op map[string]any, consumes, produces []string) map[string]any {
+	if list := stringList(op["consumes"]); len(list) > 0 {
+		consumes = list
+	}
+	if list := stringList(op["produces"]); len(list) > 0 {
+		produces = list
+	}
+	if len(consumes) == 0 {
+		consumes = []string{"application/json"}
+	}
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+
+	out := map[string]any{}
+	for _, key := range []string{"summary", "description", "operationId", "tags", "security", "deprecated"} {
+		if value, ok := op[key]; ok {
+			out[key] = value
+		}
+	}
+
+	var parameters []any
+	for _, p := range asList(op["parameters"]) {
+		param, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		if param["in"] == "body" {
+			body := map[string]any{"content": mediaTypes(consumes, param["schema"])}
+			if description, ok := param["description"]; ok {
+				body["description"] = description
+			}
+			if required, ok := param["required"]; ok {
+				body["required"] = required
+			}
+			out["requestBody"] = body
+			continue
+		}
+		parameters = append(parameters, convertParameter(param))
+	}
+	if len(parameters) > 0 {
+		out["parameters"] = parameters
+	}
+
+	responses := map[string]any{}
+	if source, ok := op["responses"].(map[string]any); ok {
+		for status, r := range source {
+			resp, _ := r.(map[string]any)
+			converted := map[string]any{"description": resp["description"]}
+			if converted["description"] == nil {
+				converted["description"] = ""
+			}
+			if schema, ok := resp["schema"]; ok {
+				// Respostas de erro saem como application/problem+json (RFC 7807)
+				types := produces
+				if strings.HasPrefix(status, "4") || strings.HasPrefix(status, "5") {
+					types = []string{httpapi.ProblemContentType}
+				}
+				converted["content"] = mediaTypes(types, schema)
+			}
+			if headers, ok := resp["headers"].(map[string]any); ok {
+				convertedHeaders := map[string]any{}
+				for name, h := range headers {
+					header, _ := h.(map[string]any)
+					convertedHeaders[name] = moveToSchema(header, "description")
+				}
+				converted["headers"] = convertedHeaders
+			}
+			responses[status] = converted
+		}
+	}
+	out["responses"] = responses
+	return out
+}
+
+// convertParameter move o tipo do parâmetro para schema e traduz collectionFormat
+func convertParameter(param map[string]any) map[string]any {
+	out := moveToSchema(param, "name", "in", "description", "required")
+	switch param["collectionFormat"] {
+	case "multi":
+		out["style"] = "form"
+		out["explode"] = true
+	case "csv":
+		out["style"] = "form"
+		out["explode"] = false
+	}
+	return out
+}
+
+// moveToSchema copia os campos em keep e agrupa os de tipo em "schema"
+func moveToSchema(source map[string]any, keep ...string) map[string]any {
+	out := map[string]any{}
+	for _, key := range keep {
+		if value, ok := source[key]; ok {
+			out[key] = value
+		}
+	}
+	schema := map[string]any{}
+	for _, key := range schemaFields {
+		if value, ok := source[key]; ok {
+			schema[key] = value
+		}
+	}
+	if len(schema) > 0 {
+		out["schema"] = schema
+	}
+	return out
+}
+
+func mediaTypes(types []string, schema any) map[string]any {
+	content := map[string]any{}
+	for _, mediaType := range types {
+		content[mediaType] = map[string]any{"schema": schema}
+	}
+	return content
+}
+
+func convertSecuritySchemes(schemes map[string]any) map[string]any {
+	out := map[string]any{}
+	for name, s := range schemes {
+		scheme, _ := s.(map[string]any)
+		switch scheme["type"] {
+		case "basic":
+			out[name] = map[string]any{"type": "http", "scheme": "basic", "description": scheme["description"]}
+		default:
+			out[name] = scheme
+		}
+	}
+	return out
+}
+
+// rewriteRefs aponta as referências de #/definitions para #/components/schemas
+func rewriteRefs(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if ref, ok := item.(string); key == "$ref" && ok {
+				v[key] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			v[key] = rewriteRefs(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = rewriteRefs(item)
+		}
+	}
+	return value
+}
+
+func asList(value any) []any {
+	list, _ := value.([]any)
+	return list
+}
+
+func stringList(value any) []string {
+	var out []string
+	for _, item := range asList(value) {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Handler serve o documento Swagger 2.0 (docs.SwaggerInfo.ReadDoc()) convertido
+// para OpenAPI 3. A conversão acontece uma vez, na criação do handler.
+func Handler(swagger2 string) (http.Handler, error) {
+	spec, err := Convert([]byte(swagger2))
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(spec)
+	}), nil
+}
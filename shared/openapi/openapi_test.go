@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const swagger2 = `{
+	"swagger": "2.0",
+	"info": {"title": "Test API", "version": "1.0"},
+	"basePath": "/",
+	"paths": {
+		"/pix": {
+			"post": {
+				"consumes": ["application/json"],
+				"produces": ["application/json"],
+				"tags": ["payments"],
+				"parameters": [{"in": "body", "name": "request", "required": true, "schema": {"$ref": "#/definitions/api.createPixRequest"}}],
+				"security": [{"ApiKeyAuth": []}],
+				"responses": {
+					"200": {"description": "OK", "schema": {"$ref": "#/definitions/payments.PixPayment"}},
+					"429": {"description": "Rate limit", "schema": {"$ref": "#/definitions/httpapi.Problem"}, "headers": {"Retry-After": {"type": "integer", "description": "segundos"}}}
+				}
+			}
+		},
+		"/pix/ws": {
+			"get": {
+				"parameters": [{"in": "query", "name": "payment_id", "type": "array", "items": {"type": "integer"}, "collectionFormat": "multi"}],
+				"responses": {"101": {"description": "Switching Protocols"}}
+			}
+		}
+	},
+	"definitions": {
+		"api.createPixRequest": {"type": "object", "properties": {"amount": {"type": "number"}}},
+		"httpapi.Problem": {"type": "object", "properties": {"code": {"type": "string"}}},
+		"payments.PixPayment": {"type": "object", "properties": {"review": {"allOf": [{"$ref": "#/definitions/payments.PaymentReview"}]}}}
+	},
+	"securityDefinitions": {"ApiKeyAuth": {"type": "apiKey", "in": "header", "name": "X-API-Key"}}
+}`
+
+func TestConvert(t *testing.T) {
+	data, err := Convert([]byte(swagger2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(path ...any) any {
+		var v any = doc
+		for _, key := range path {
+			switch k := key.(type) {
+			case string:
+				v = v.(map[string]any)[k]
+			case int:
+				v = v.([]any)[k]
+			}
+		}
+		return v
+	}
+
+	checks := []struct {
+		path []any
+		want any
+	}{
+		{[]any{"openapi"}, Version},
+		{[]any{"servers", 0, "url"}, "/"},
+		{[]any{"paths", "/pix", "post", "requestBody", "required"}, true},
+		{[]any{"paths", "/pix", "post", "requestBody", "content", "application/json", "schema", "$ref"}, "#/components/schemas/api.createPixRequest"},
+		{[]any{"paths", "/pix", "post", "responses", "200", "content", "application/json", "schema", "$ref"}, "#/components/schemas/payments.PixPayment"},
+		{[]any{"paths", "/pix", "post", "responses", "429", "headers", "Retry-After", "schema", "type"}, "integer"},
+		{[]any{"paths", "/pix", "post", "responses", "429", "content", "application/problem+json", "schema", "$ref"}, "#/components/schemas/httpapi.Problem"},
+		{[]any{"paths", "/pix", "post", "security", 0, "ApiKeyAuth"}, []any{}},
+		{[]any{"paths", "/pix/ws", "get", "parameters", 0, "schema", "type"}, "array"},
+		{[]any{"paths", "/pix/ws", "get", "parameters", 0, "explode"}, true},
+		{[]any{"paths", "/pix/ws", "get", "responses", "101", "description"}, "Switching Protocols"},
+		{[]any{"components", "schemas", "payments.PixPayment", "properties", "review", "allOf", 0, "$ref"}, "#/components/schemas/payments.PaymentReview"},
+		{[]any{"components", "securitySchemes", "ApiKeyAuth", "name"}, "X-API-Key"},
+	}
+	for _, c := range checks {
+		if got := get(c.path...); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%v = %v, want %v", c.path, got, c.want)
+		}
+	}
+	if get("paths", "/pix", "post", "parameters") != nil {
+		t.Error("body parameter should move to requestBody")
+	}
+}
+
+func TestConvert_RejectsOtherVersions(t *testing.T) {
+	if _, err := Convert([]byte(`{"openapi":"3.0.0"}`)); err == nil {
+		t.Error("expected error for a non swagger 2.0 document")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	handler, err := Handler(swagger2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("status = %d, content type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	ops, err := Operations(rec.Body.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Operation{
+		{Method: "POST", Path: "/pix", Tags: []string{"payments"}},
+		{Method: "GET", Path: "/pix/ws"},
+	}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("Operations = %+v, want %+v", ops, want)
+	}
+	if got := (Operation{Path: "/reviews/{id}/approve"}).Example(); got != "/reviews/1/approve" {
+		t.Errorf("Example = %q", got)
+	}
+}
+
+func TestRoutes_Diff(t *testing.T) {
+	routes := NewRoutes()
+	noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	routes.Handle("/pix", noop)
+	routes.Handle("/pix/", noop)
+	routes.Handle("/webhooks", noop)
+
+	unmounted, undocumented := routes.Diff([]Operation{
+		{Method: "GET", Path: "/pix"},
+		{Method: "GET", Path: "/pix/{id}"},
+		{Method: "GET", Path: "/reviews"},
+	})
+	if !reflect.DeepEqual(unmounted, []Operation{{Method: "GET", Path: "/reviews"}}) {
+		t.Errorf("unmounted = %+v", unmounted)
+	}
+	if !reflect.DeepEqual(undocumented, []string{"/webhooks"}) {
+		t.Errorf("undocumented = %v", undocumented)
+	}
+}
@@ -0,0 +1,59 @@
+package openapi
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Operation é uma rota documentada (método e caminho com {parâmetros})
+type Operation struct {
+	Method string
+	Path   string
+	Tags   []string
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+// Example devolve o caminho com cada parâmetro trocado por 1, para montar uma
+// requisição de teste contra o mux
+func (o Operation) Example() string {
+	return pathParam.ReplaceAllString(o.Path, "1")
+}
+
+// HasTag informa se a operação pertence à tag
+func (o Operation) HasTag(tag string) bool {
+	for _, t := range o.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Operations lista as operações de um documento Swagger 2.0 ou OpenAPI 3,
+// ordenadas por caminho e método
+func Operations(doc string) ([]Operation, error) {
+	var parsed struct {
+		Paths map[string]map[string]struct {
+			Tags []string `json:"tags"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(doc), &parsed); err != nil {
+		return nil, err
+	}
+	var ops []Operation
+	for path, methods := range parsed.Paths {
+		for method, op := range methods {
+			ops = append(ops, Operation{Method: strings.ToUpper(method), Path: path, Tags: op.Tags})
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return ops[i].Method < ops[j].Method
+	})
+	return ops, nil
+}
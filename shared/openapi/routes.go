@@ -0,0 +1,48 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Routes implementa httpapi.Mux guardando os padrões montados, para comparar
+// o que os handlers registram com o que está documentado
+type Routes struct {
+	mux      *http.ServeMux
+	patterns []string
+}
+
+func NewRoutes() *Routes {
+	return &Routes{mux: http.NewServeMux()}
+}
+
+func (r *Routes) Handle(pattern string, handler http.Handler) {
+	r.mux.Handle(pattern, handler)
+	r.patterns = append(r.patterns, pattern)
+}
+
+// Diff devolve as operações documentadas que nenhum padrão atende e os
+// padrões montados que nenhuma operação documentada alcança
+func (r *Routes) Diff(ops []Operation) (unmounted []Operation, undocumented []string) {
+	reached := map[string]bool{}
+	for _, op := range ops {
+		req, err := http.NewRequest(op.Method, op.Example(), nil)
+		if err != nil {
+			unmounted = append(unmounted, op)
+			continue
+		}
+		_, pattern := r.mux.Handler(req)
+		if pattern == "" {
+			unmounted = append(unmounted, op)
+			continue
+		}
+		reached[pattern] = true
+	}
+	for _, pattern := range r.patterns {
+		if !reached[pattern] {
+			undocumented = append(undocumented, pattern)
+		}
+	}
+	sort.Strings(undocumented)
+	return unmounted, undocumented
+}
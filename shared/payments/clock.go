@@ -0,0 +1,20 @@
+package payments
+
+import "time"
+
+// Clock é a fonte de tempo do fluxo de pagamento. As esperas da simulação
+// passam por ele para que os testes rodem sem dormir (paymentstest.Clock).
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	// After entrega o horário no canal depois de d, como time.After; os laços
+	// em background esperam com ele para também parar pelo ctx
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock é o relógio real
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time                         { return time.Now() }
+func (SystemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (SystemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
@@ -0,0 +1,339 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fintech-shared/logging"
+	"fintech-shared/tracing"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("fintech-shared/payments")
+
+// CreatePixPaymentUseCase cria um pagamento PIX e simula o fluxo completo:
+// 1. Cria o pagamento (CREATED)
+// 2. Análise antifraude (deny → REJECTED, review → PENDING_REVIEW)
+// 3. Autoriza no BACEN (AUTHORIZED; recusado pelo BACEN → REJECTED)
+// 4. Liquida o pagamento (SETTLED)
+// As notificações passam pelo Notifier de cada deployable; o resto do fluxo é
+// o mesmo no monólito e no payments-service.
+type CreatePixPaymentUseCase struct {
+	repo             PixPaymentRepository
+	notifier         Notifier
+	gateway          PixGateway
+	eventBroadcaster EventBroadcaster
+	limits           TransactionLimits
+	fraudChecker     FraudChecker
+	reviewDeadline   time.Duration // Prazo da revisão manual antes de expirar
+	metrics          PaymentMetrics
+	timings          FlowTimings // Esperas entre as etapas (perfil de simulação)
+	clock            Clock       // Relógio das esperas (manual nos testes)
+}
+
+func NewCreatePixPaymentUseCase(
+	repo PixPaymentRepository,
+	notifier Notifier,
+	gateway PixGateway,
+	eventBroadcaster EventBroadcaster,
+	limits TransactionLimits,
+	fraudChecker FraudChecker,
+	reviewDeadline time.Duration,
+	metrics PaymentMetrics,
+	timings FlowTimings,
+	clock Clock,
+) *CreatePixPaymentUseCase {
+	if metrics == nil {
+		metrics = NopMetrics{}
+	}
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	return &CreatePixPaymentUseCase{
+		repo:             repo,
+		notifier:         notifier,
+		gateway:          gateway,
+		eventBroadcaster: eventBroadcaster,
+		limits:           limits,
+		fraudChecker:     fraudChecker,
+		reviewDeadline:   reviewDeadline,
+		metrics:          metrics,
+		timings:          timings,
+		clock:            clock,
+	}
+}
+
+func (uc *CreatePixPaymentUseCase) Execute(ctx context.Context, merchantID, payerID string, amount float64) (*PixPayment, error) {
+	ctx, span := tracer.Start(ctx, "CreatePixPayment", trace.WithAttributes(
+		attribute.String("merchant.id", merchantID),
+		attribute.Float64("payment.amount", amount),
+	))
+	defer span.End()
+
+	// 1. Criar pagamento com status CREATED
+	payment, err := NewPixPayment(merchantID, payerID, amount)
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "creating pix payment", "merchant_id", merchantID, "payer_id", payerID, "amount", amount)
+
+	// 2. Salvar com status CREATED, se couber nos limites de
+	// negócio (por transação, diário por pagador e noturno). A soma do dia e o
+	// INSERT são atômicos por pagador: pedidos simultâneos não estouram o limite.
+	now := uc.clock.Now()
+	saved, err := uc.repo.SaveWithinLimit(ctx, payment, StartOfDay(now), func(dailyTotal float64) error {
+		return uc.limits.Check(amount, dailyTotal, now)
+	})
+	if errors.Is(err, ErrLimitExceeded) {
+		slog.WarnContext(ctx, "pix payment refused by transaction limit", "payer_id", payerID, "amount", amount, "error", err)
+		uc.metrics.PaymentFailed("limits")
+	}
+	if err != nil {
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	// A partir daqui todo log do fluxo traz o payment_id
+	ctx = logging.WithPaymentID(ctx, saved.ID)
+	span.SetAttributes(attribute.Int64("payment.id", saved.ID))
+	slog.InfoContext(ctx, "pix payment created", "status", saved.Status)
+	uc.metrics.PaymentStatus(saved.Status)
+
+	// Emitir evento de criação
+	if uc.eventBroadcaster != nil {
+		uc.emitStatusEvent(saved, "Pagamento PIX criado")
+	}
+
+	// IMPORTANTE: Processar o resto do fluxo em background (goroutine)
+	// Isso permite que o POST retorne imediatamente e o SSE tenha tempo de conectar
+	// O contexto da requisição é cancelado quando ela termina: o fluxo só herda os
+	// valores, inclusive o span atual, então o fluxo fica no mesmo trace da requisição
+	// O fluxo avança numa cópia: o pagamento devolvido é serializado na resposta
+	// enquanto o fluxo já muda o status
+	flowPayment := *saved
+	go uc.processPaymentFlow(context.WithoutCancel(ctx), &flowPayment)
+
+	// Retornar imediatamente com o pagamento criado
+	return saved, nil
+}
+
+// processPaymentFlow processa o fluxo completo do pagamento em background
+func (uc *CreatePixPaymentUseCase) processPaymentFlow(ctx context.Context, saved *PixPayment) {
+	// Filho do span da criação: termina bem depois da resposta, mas no mesmo trace
+	ctx, span := tracer.Start(ctx, "ProcessPixPayment", trace.WithAttributes(attribute.Int64("payment.id", saved.ID)))
+	defer span.End()
+
+	// Delay inicial para dar tempo do SSE conectar
+	uc.clock.Sleep(uc.timings.Startup)
+
+	// 3. Notificar criação ao BACEN (simulação)
+	uc.gateway.NotifyCreation(ctx, saved)
+
+	// 4. Criar notificação de criação
+	uc.notify(ctx, saved, NotificationCreated, "Pagamento PIX criado com sucesso")
+
+	// 5. Análise antifraude antes da autorização
+	if !uc.screen(ctx, saved) {
+		return
+	}
+
+	// 6. Simular autorização no BACEN (após a espera do perfil, 2s na demo)
+	if !uc.authorize(ctx, saved) {
+		return
+	}
+
+	// 7. Criar notificação de autorização
+	uc.notify(ctx, saved, NotificationAuthorized, "Pagamento PIX autorizado pelo BACEN")
+
+	// 8. Liquidação
+	uc.settle(ctx, saved)
+}
+
+// authorize autoriza o pagamento no BACEN. Retorna false se a autorização
+// falhar; recusado pelo BACEN, o pagamento termina em REJECTED.
+func (uc *CreatePixPaymentUseCase) authorize(ctx context.Context, saved *PixPayment) bool {
+	ctx, span := tracer.Start(ctx, "AuthorizePixPayment")
+	defer span.End()
+
+	uc.clock.Sleep(uc.timings.Authorization)
+	if err := uc.gateway.Authorize(ctx, saved); err != nil {
+		slog.WarnContext(ctx, "pix payment refused by bacen", "error", err)
+		uc.metrics.PaymentFailed("bacen_authorize")
+		tracing.Fail(span, err)
+		if err := saved.Reject(); err != nil {
+			slog.ErrorContext(ctx, "failed to reject pix payment", "error", err)
+			uc.metrics.PaymentFailed("reject")
+			return false
+		}
+		uc.updateStatus(ctx, saved, "Pagamento PIX recusado pelo BACEN")
+		uc.notify(ctx, saved, NotificationRejected, "Pagamento PIX recusado pelo BACEN")
+		return false
+	}
+
+	err := saved.Authorize()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to authorize pix payment", "error", err)
+		uc.metrics.PaymentFailed("authorize")
+		tracing.Fail(span, err)
+		return false
+	}
+
+	// Atualizar status no banco
+	err = uc.repo.UpdateStatus(ctx, saved.ID, saved.Status)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update payment status", "status", saved.Status, "error", err)
+		uc.metrics.PaymentFailed("persist")
+		tracing.Fail(span, err)
+	} else {
+		slog.InfoContext(ctx, "pix payment authorized", "status", saved.Status)
+		uc.metrics.PaymentStatus(saved.Status)
+		// Emitir evento de autorização
+		if uc.eventBroadcaster != nil {
+			uc.emitStatusEvent(saved, "Pagamento PIX autorizado pelo BACEN")
+		}
+	}
+	return true
+}
+
+// screen executa o antifraude. Retorna false quando o fluxo deve parar
+// (pagamento recusado ou retido para revisão manual).
+func (uc *CreatePixPaymentUseCase) screen(ctx context.Context, saved *PixPayment) bool {
+	if uc.fraudChecker == nil {
+		return true
+	}
+
+	ctx, span := tracer.Start(ctx, "FraudCheck")
+	defer span.End()
+
+	assessment, err := uc.fraudChecker.Check(ctx, saved)
+	if err != nil {
+		// Falha segura: sem histórico não dá para decidir, então vai para revisão
+		slog.ErrorContext(ctx, "fraud check failed, holding payment for review", "error", err)
+		uc.metrics.PaymentFailed("fraud_check")
+		tracing.Fail(span, err)
+		assessment = FraudAssessment{Decision: FraudReview, Reasons: []string{"fraud check unavailable"}}
+	}
+	span.SetAttributes(attribute.String("fraud.decision", string(assessment.Decision)))
+	saved.Risk = &assessment
+	if err := uc.repo.SaveRiskAssessment(ctx, saved.ID, assessment); err != nil {
+		slog.ErrorContext(ctx, "failed to save fraud assessment", "error", err)
+		uc.metrics.PaymentFailed("persist")
+	}
+
+	switch assessment.Decision {
+	case FraudDeny:
+		if err := saved.Reject(); err != nil {
+			slog.ErrorContext(ctx, "failed to reject pix payment", "error", err)
+			uc.metrics.PaymentFailed("reject")
+			return false
+		}
+		uc.updateStatus(ctx, saved, "Pagamento PIX recusado pelo antifraude")
+		uc.notify(ctx, saved, NotificationRejected, "Pagamento PIX recusado pela análise antifraude")
+		slog.WarnContext(ctx, "pix payment rejected by fraud check", "reasons", assessment.Reasons)
+		return false
+	case FraudReview:
+		if err := saved.HoldForReview(uc.clock.Now().Add(uc.reviewDeadline)); err != nil {
+			slog.ErrorContext(ctx, "failed to hold pix payment for review", "error", err)
+			uc.metrics.PaymentFailed("review")
+			return false
+		}
+		if _, err := uc.repo.SaveReview(ctx, saved, StatusCreated); err != nil {
+			slog.ErrorContext(ctx, "failed to update payment status", "status", saved.Status, "error", err)
+			uc.metrics.PaymentFailed("persist")
+			return false
+		}
+		uc.metrics.PaymentStatus(saved.Status)
+		if uc.eventBroadcaster != nil {
+			uc.emitStatusEvent(saved, "Pagamento PIX retido para revisão manual")
+		}
+		uc.notify(ctx, saved, NotificationPendingReview, "Pagamento PIX em análise")
+		slog.WarnContext(ctx, "pix payment held for manual review", "reasons", assessment.Reasons, "deadline", saved.Review.Deadline)
+		return false
+	}
+	return true
+}
+
+// settle liquida um pagamento já autorizado (também usado após aprovação manual)
+func (uc *CreatePixPaymentUseCase) settle(ctx context.Context, saved *PixPayment) {
+	ctx, span := tracer.Start(ctx, "SettlePixPayment", trace.WithAttributes(attribute.Int64("payment.id", saved.ID)))
+	defer span.End()
+
+	// Simular liquidação no BACEN (após a espera do perfil, 3s na demo)
+	uc.clock.Sleep(uc.timings.Settlement)
+	err := saved.Settle()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to settle pix payment", "error", err)
+		uc.metrics.PaymentFailed("settle")
+		tracing.Fail(span, err)
+		return
+	}
+
+	// Atualizar status no banco
+	err = uc.repo.UpdateStatus(ctx, saved.ID, saved.Status)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update payment status", "status", saved.Status, "error", err)
+		uc.metrics.PaymentFailed("persist")
+		tracing.Fail(span, err)
+	} else {
+		slog.InfoContext(ctx, "pix payment settled", "status", saved.Status)
+		uc.metrics.PaymentStatus(saved.Status)
+		uc.metrics.PaymentSettled(uc.clock.Now().Sub(saved.CreatedAt))
+		// Emitir evento de liquidação
+		if uc.eventBroadcaster != nil {
+			uc.emitStatusEvent(saved, "Pagamento PIX liquidado com sucesso")
+		}
+	}
+
+	// Criar notificação de liquidação
+	uc.notify(ctx, saved, NotificationSettled, "Pagamento PIX liquidado com sucesso")
+
+	// Notificar liquidação ao BACEN. O pagamento já está liquidado aqui: a
+	// falha só é registrada, para conciliação
+	if err := uc.gateway.Settle(ctx, saved); err != nil {
+		slog.ErrorContext(ctx, "failed to notify pix settlement to bacen", "error", err)
+		uc.metrics.PaymentFailed("bacen_settle")
+		tracing.Fail(span, err)
+	}
+
+	slog.InfoContext(ctx, "pix payment flow finished", "status", saved.Status)
+}
+
+// updateStatus persiste o novo status e emite o evento correspondente
+func (uc *CreatePixPaymentUseCase) updateStatus(ctx context.Context, saved *PixPayment, message string) {
+	if err := uc.repo.UpdateStatus(ctx, saved.ID, saved.Status); err != nil {
+		slog.ErrorContext(ctx, "failed to update payment status", "status", saved.Status, "error", err)
+		uc.metrics.PaymentFailed("persist")
+		return
+	}
+	uc.metrics.PaymentStatus(saved.Status)
+	if uc.eventBroadcaster != nil {
+		uc.emitStatusEvent(saved, message)
+	}
+}
+
+// notify avisa o pagador pelo Notifier do deployable
+func (uc *CreatePixPaymentUseCase) notify(ctx context.Context, saved *PixPayment, notificationType NotificationType, message string) {
+	if err := uc.notifier.Notify(ctx, saved, notificationType, message); err != nil {
+		slog.ErrorContext(ctx, "failed to send notification", "type", notificationType, "error", err)
+		uc.metrics.PaymentFailed("notify")
+	}
+}
+
+// emitStatusEvent emite um evento de mudança de status
+func (uc *CreatePixPaymentUseCase) emitStatusEvent(payment *PixPayment, message string) {
+	event := PaymentEvent{
+		PaymentID:  payment.ID,
+		MerchantID: payment.MerchantID,
+		Status:     payment.Status,
+		Amount:     payment.Amount,
+		Timestamp:  uc.clock.Now(),
+		Message:    message,
+	}
+	uc.eventBroadcaster.Broadcast(payment.ID, event)
+}
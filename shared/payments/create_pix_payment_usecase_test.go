@@ -0,0 +1,315 @@
+package payments_test
+
+import (
+	"context"
+	"errors"
+	"fintech-shared/payments"
+	"fintech-shared/payments/paymentstest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	now     = time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC) // 12h em Brasília: fora do período noturno
+	timings = payments.FlowTimings{Startup: time.Second, Authorization: 2 * time.Second, Settlement: 3 * time.Second}
+)
+
+const reviewDeadline = time.Hour
+
+type fixture struct {
+	repo     *paymentstest.Repository
+	notifier *paymentstest.Notifier
+	gateway  *paymentstest.Gateway
+	events   *paymentstest.Broadcaster
+	clock    *paymentstest.Clock
+	limits   payments.TransactionLimits
+	create   *payments.CreatePixPaymentUseCase
+	review   *payments.ReviewPaymentUseCase
+}
+
+// newFixture monta os casos de uso com os fakes de paymentstest, com o relógio em now
+func newFixture(fraud payments.FraudChecker) *fixture {
+	clock := paymentstest.NewClock(now)
+	f := &fixture{
+		repo:     paymentstest.NewRepository(clock),
+		notifier: &paymentstest.Notifier{},
+		gateway:  &paymentstest.Gateway{},
+		events:   paymentstest.NewBroadcaster(),
+		clock:    clock,
+		limits:   payments.DefaultTransactionLimits(),
+	}
+	f.create = payments.NewCreatePixPaymentUseCase(f.repo, f.notifier, f.gateway, f.events,
+		f.limits, fraud, reviewDeadline, nil, timings, f.clock)
+	f.review = payments.NewReviewPaymentUseCase(f.repo, f.create)
+	return f
+}
+
+// save grava um pagamento CREATED direto no repositório, sem disparar o fluxo
+func (f *fixture) save(t *testing.T, payerID string, amount float64) *payments.PixPayment {
+	t.Helper()
+	payment, err := payments.NewPixPayment("loja-a", payerID, amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := f.repo.Save(context.Background(), payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return saved
+}
+
+func TestCreatePixPayment_ReturnsBeforeTheFlow(t *testing.T) {
+	f := newFixture(nil)
+
+	payment, err := f.create.Execute(context.Background(), "loja-a", "pagador-1", 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.ID == 0 || payment.Status != payments.StatusCreated || !payment.CreatedAt.Equal(now) {
+		t.Errorf("payment = %+v", payment)
+	}
+
+	// O fluxo segue em background; com o relógio manual ele não espera de verdade
+	f.events.WaitFor(t, payment.ID, payments.StatusSettled)
+	stored, err := f.repo.FindByID(context.Background(), payment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != payments.StatusSettled {
+		t.Errorf("stored status = %s, want SETTLED", stored.Status)
+	}
+	// O pagamento devolvido não muda com o fluxo: a resposta já foi serializada com ele
+	if payment.Status != payments.StatusCreated {
+		t.Errorf("returned payment status = %s after the flow, want CREATED", payment.Status)
+	}
+	if got, want := f.clock.Sleeps(), []time.Duration{timings.Startup, timings.Authorization, timings.Settlement}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sleeps = %v, want %v", got, want)
+	}
+}
+
+func TestCreatePixPayment_RejectsInvalidPayments(t *testing.T) {
+	tests := []struct {
+		name    string
+		at      time.Time
+		payerID string
+		amount  float64
+		want    error
+	}{
+		{"missing payer", now, "", 10, payments.ErrInvalidPayment},
+		{"zero amount", now, "pagador-1", 0, payments.ErrInvalidAmount},
+		{"over transaction limit", now, "pagador-1", 1_000_001, payments.ErrLimitExceeded},
+		{"over daily limit", now, "pagador-rico", 600_000, payments.ErrLimitExceeded},
+		{"over nightly limit", time.Date(2024, 1, 16, 1, 0, 0, 0, time.UTC), "pagador-1", 1_001, payments.ErrLimitExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(nil)
+			// 1,5 milhão já pago hoje pelo pagador-rico
+			for i := 0; i < 3; i++ {
+				f.save(t, "pagador-rico", 500_000)
+			}
+			f.clock.Advance(tt.at.Sub(now))
+
+			_, err := f.create.Execute(context.Background(), "loja-a", tt.payerID, tt.amount)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if all, _ := f.repo.FindAllByMerchant(context.Background(), "loja-a"); len(all) != 3 {
+				t.Errorf("stored %d payments, want only the 3 from setup", len(all))
+			}
+		})
+	}
+}
+
+// Pedidos simultâneos do mesmo pagador: a soma do dia e a gravação são
+// atômicas, então só passam os que cabem no limite diário (2 milhões)
+func TestCreatePixPayment_DailyLimitUnderConcurrency(t *testing.T) {
+	f := newFixture(nil)
+	const attempts, amount = 10, 500_000
+
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := f.create.Execute(context.Background(), "loja-a", "pagador-1", amount)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	accepted := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			accepted++
+		case !errors.Is(err, payments.ErrLimitExceeded):
+			t.Errorf("err = %v, want nil or ErrLimitExceeded", err)
+		}
+	}
+	if accepted != 4 {
+		t.Errorf("accepted = %d, want 4", accepted)
+	}
+	if total, _ := f.repo.SumAmountByPayerSince(context.Background(), "pagador-1", payments.StartOfDay(now)); total != f.limits.DailyPerPayer {
+		t.Errorf("daily total = %v, want the limit", total)
+	}
+}
+
+// O fluxo em background, executado de forma síncrona, para cada decisão do antifraude
+func TestCreatePixPayment_Flow(t *testing.T) {
+	tests := []struct {
+		name          string
+		fraud         payments.FraudChecker
+		wantStatus    payments.PaymentStatus
+		wantEvents    []payments.PaymentStatus
+		wantNotified  []payments.NotificationType
+		wantGateway   []string
+		wantRisk      payments.FraudDecision
+		wantHeldUntil time.Time
+	}{
+		{
+			name:         "without fraud checker",
+			wantStatus:   payments.StatusSettled,
+			wantEvents:   []payments.PaymentStatus{payments.StatusAuthorized, payments.StatusSettled},
+			wantNotified: []payments.NotificationType{payments.NotificationCreated, payments.NotificationAuthorized, payments.NotificationSettled},
+			wantGateway:  []string{"NotifyCreation 1", "Authorize 1", "Settle 1"},
+		},
+		{
+			name:         "approved",
+			fraud:        fraudDecision{Decision: payments.FraudApprove},
+			wantStatus:   payments.StatusSettled,
+			wantEvents:   []payments.PaymentStatus{payments.StatusAuthorized, payments.StatusSettled},
+			wantNotified: []payments.NotificationType{payments.NotificationCreated, payments.NotificationAuthorized, payments.NotificationSettled},
+			wantGateway:  []string{"NotifyCreation 1", "Authorize 1", "Settle 1"},
+			wantRisk:     payments.FraudApprove,
+		},
+		{
+			name:         "denied",
+			fraud:        fraudDecision{Decision: payments.FraudDeny, Reasons: []string{"velocity"}},
+			wantStatus:   payments.StatusRejected,
+			wantEvents:   []payments.PaymentStatus{payments.StatusRejected},
+			wantNotified: []payments.NotificationType{payments.NotificationCreated, payments.NotificationRejected},
+			wantGateway:  []string{"NotifyCreation 1"},
+			wantRisk:     payments.FraudDeny,
+		},
+		{
+			name:          "held for review",
+			fraud:         fraudDecision{Decision: payments.FraudReview, Reasons: []string{"large_amount"}},
+			wantStatus:    payments.StatusPendingReview,
+			wantEvents:    []payments.PaymentStatus{payments.StatusPendingReview},
+			wantNotified:  []payments.NotificationType{payments.NotificationCreated, payments.NotificationPendingReview},
+			wantGateway:   []string{"NotifyCreation 1"},
+			wantRisk:      payments.FraudReview,
+			wantHeldUntil: now.Add(timings.Startup + reviewDeadline),
+		},
+		{
+			name:          "fraud check unavailable fails safe",
+			fraud:         brokenFraudChecker{},
+			wantStatus:    payments.StatusPendingReview,
+			wantEvents:    []payments.PaymentStatus{payments.StatusPendingReview},
+			wantNotified:  []payments.NotificationType{payments.NotificationCreated, payments.NotificationPendingReview},
+			wantGateway:   []string{"NotifyCreation 1"},
+			wantRisk:      payments.FraudReview,
+			wantHeldUntil: now.Add(timings.Startup + reviewDeadline),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(tt.fraud)
+			payment := f.save(t, "pagador-1", 150)
+
+			f.create.ProcessFlow(context.Background(), payment)
+
+			stored, err := f.repo.FindByID(context.Background(), payment.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Status != tt.wantStatus || payment.Status != tt.wantStatus {
+				t.Errorf("status = %s (stored %s), want %s", payment.Status, stored.Status, tt.wantStatus)
+			}
+			if got := f.events.Statuses(payment.ID); !reflect.DeepEqual(got, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", got, tt.wantEvents)
+			}
+			if got := f.notifier.Types(payment.ID); !reflect.DeepEqual(got, tt.wantNotified) {
+				t.Errorf("notifications = %v, want %v", got, tt.wantNotified)
+			}
+			if got := f.gateway.Calls(); !reflect.DeepEqual(got, tt.wantGateway) {
+				t.Errorf("gateway calls = %v, want %v", got, tt.wantGateway)
+			}
+			if tt.wantRisk == "" {
+				if stored.Risk != nil {
+					t.Errorf("risk = %+v, want none", stored.Risk)
+				}
+			} else if stored.Risk == nil || stored.Risk.Decision != tt.wantRisk {
+				t.Errorf("risk = %+v, want %s", stored.Risk, tt.wantRisk)
+			}
+			if !tt.wantHeldUntil.IsZero() && (stored.Review == nil || !stored.Review.Deadline.Equal(tt.wantHeldUntil)) {
+				t.Errorf("review = %+v, want deadline %s", stored.Review, tt.wantHeldUntil)
+			}
+		})
+	}
+}
+
+// Notificação e liquidação no BACEN são registradas, mas não desfazem o pagamento
+func TestCreatePixPayment_FlowSurvivesDownstreamFailures(t *testing.T) {
+	f := newFixture(nil)
+	f.gateway.SettleErr = errors.New("bacen timeout")
+	f.notifier.Err = errors.New("notifications unavailable")
+	payment := f.save(t, "pagador-1", 150)
+
+	f.create.ProcessFlow(context.Background(), payment)
+
+	if got := f.events.Statuses(payment.ID); !reflect.DeepEqual(got, []payments.PaymentStatus{payments.StatusAuthorized, payments.StatusSettled}) {
+		t.Errorf("events = %v", got)
+	}
+	if got := f.notifier.Types(payment.ID); len(got) != 3 {
+		t.Errorf("notifications = %v, want all 3 attempted", got)
+	}
+	if got := f.gateway.Calls(); got[len(got)-1] != "Settle 1" {
+		t.Errorf("gateway calls = %v, want settlement attempted", got)
+	}
+}
+
+// Recusado na autorização do BACEN, o pagamento termina em REJECTED sem liquidação
+func TestCreatePixPayment_FlowRejectsWhenBacenRefuses(t *testing.T) {
+	f := newFixture(nil)
+	f.gateway.AuthorizeErr = errors.New("insufficient funds")
+	payment := f.save(t, "pagador-1", 150)
+
+	f.create.ProcessFlow(context.Background(), payment)
+
+	stored, err := f.repo.FindByID(context.Background(), payment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != payments.StatusRejected {
+		t.Errorf("status = %s, want REJECTED", stored.Status)
+	}
+	if got := f.events.Statuses(payment.ID); !reflect.DeepEqual(got, []payments.PaymentStatus{payments.StatusRejected}) {
+		t.Errorf("events = %v", got)
+	}
+	if got := f.notifier.Types(payment.ID); !reflect.DeepEqual(got, []payments.NotificationType{payments.NotificationCreated, payments.NotificationRejected}) {
+		t.Errorf("notifications = %v", got)
+	}
+	if got, want := f.gateway.Calls(), []string{"NotifyCreation 1", "Authorize 1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("gateway calls = %v, want %v", got, want)
+	}
+}
+
+// fraudDecision é um antifraude que sempre devolve a mesma análise
+type fraudDecision payments.FraudAssessment
+
+func (d fraudDecision) Check(context.Context, *payments.PixPayment) (payments.FraudAssessment, error) {
+	return payments.FraudAssessment(d), nil
+}
+
+// brokenFraudChecker simula o histórico do pagador indisponível
+type brokenFraudChecker struct{}
+
+func (brokenFraudChecker) Check(context.Context, *payments.PixPayment) (payments.FraudAssessment, error) {
+	return payments.FraudAssessment{}, errors.New("payer history unavailable")
+}
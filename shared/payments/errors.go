@@ -0,0 +1,19 @@
+package payments
+
+import "errors"
+
+// Erros de domínio dos pagamentos. As APIs os traduzem para status e códigos
+// estáveis em uma tabela central; mensagens mais específicas os embrulham
+// (fmt.Errorf com %w) e continuam reconhecíveis com errors.Is.
+var (
+	// ErrNotFound indica que o pagamento não existe (o repositório o devolve no lugar do erro do driver)
+	ErrNotFound = errors.New("payment not found")
+	// ErrInvalidPayment indica um pagamento sem lojista ou pagador
+	ErrInvalidPayment = errors.New("invalid payment")
+	// ErrInvalidAmount indica um valor abaixo de um centavo ou com frações de centavo
+	ErrInvalidAmount = errors.New("amount must be at least 0.01 with at most 2 decimal places")
+	// ErrInvalidTransition indica uma mudança de status que o ciclo de vida não permite
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrLimitExceeded é o erro genérico por trás de LimitError
+	ErrLimitExceeded = errors.New("transaction limit exceeded")
+)
@@ -0,0 +1,70 @@
+package payments
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewPixPayment_ReturnsDomainErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		merchant string
+		payer    string
+		amount   float64
+		want     error
+	}{
+		{"missing merchant", "", "payer-1", 10, ErrInvalidPayment},
+		{"missing payer", "merchant-1", "", 10, ErrInvalidPayment},
+		{"zero amount", "merchant-1", "payer-1", 0, ErrInvalidAmount},
+		{"negative amount", "merchant-1", "payer-1", -5, ErrInvalidAmount},
+		{"NaN amount", "merchant-1", "payer-1", math.NaN(), ErrInvalidAmount},
+		{"infinite amount", "merchant-1", "payer-1", math.Inf(1), ErrInvalidAmount},
+		{"below one cent", "merchant-1", "payer-1", 0.001, ErrInvalidAmount},
+		{"fraction of a cent", "merchant-1", "payer-1", 10.005, ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPixPayment(tt.merchant, tt.payer, tt.amount); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPixPayment_AcceptsCents(t *testing.T) {
+	for _, amount := range []float64{0.01, 0.29, 1.1, 19.99, 100, 1_000_000.07} {
+		if _, err := NewPixPayment("merchant-1", "payer-1", amount); err != nil {
+			t.Errorf("NewPixPayment(%v) = %v, want nil", amount, err)
+		}
+	}
+}
+
+func TestTransitions_ReturnErrInvalidTransition(t *testing.T) {
+	settled := &PixPayment{Status: StatusSettled}
+	transitions := map[string]error{
+		"authorize": settled.Authorize(),
+		"reject":    settled.Reject(),
+		"settle":    settled.Settle(),
+		"hold":      settled.HoldForReview(time.Now()),
+		"approve":   settled.Approve("reviewer", "", time.Now()),
+		"expire":    settled.ExpireReview(time.Now()),
+	}
+	for name, err := range transitions {
+		if !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("%s: err = %v, want ErrInvalidTransition", name, err)
+		}
+	}
+	if settled.Status != StatusSettled {
+		t.Errorf("status = %s, want unchanged", settled.Status)
+	}
+}
+
+func TestLimitError_IsErrLimitExceeded(t *testing.T) {
+	err := DefaultTransactionLimits().Check(1_000_001, 0, time.Now())
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("err = %v, want *LimitError wrapping ErrLimitExceeded", err)
+	}
+}
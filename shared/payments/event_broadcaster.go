@@ -0,0 +1,28 @@
+package payments
+
+import "time"
+
+// PaymentEvent representa um evento de mudança de status
+type PaymentEvent struct {
+	PaymentID  int64         `json:"payment_id"`
+	MerchantID string        `json:"merchant_id,omitempty"`
+	Status     PaymentStatus `json:"status"`
+	Amount     float64       `json:"amount"`
+	Timestamp  time.Time     `json:"timestamp"`
+	Message    string        `json:"message"`
+}
+
+// EventBroadcaster interface para emitir eventos de mudança de status
+type EventBroadcaster interface {
+	Broadcast(paymentID int64, event PaymentEvent)
+}
+
+// EventBroadcasters distribui cada evento para vários broadcasters
+// (ex.: clientes SSE/WebSocket e webhooks dos lojistas)
+type EventBroadcasters []EventBroadcaster
+
+func (bs EventBroadcasters) Broadcast(paymentID int64, event PaymentEvent) {
+	for _, b := range bs {
+		b.Broadcast(paymentID, event)
+	}
+}
@@ -0,0 +1,8 @@
+package payments
+
+import "context"
+
+// ProcessFlow roda de forma síncrona o fluxo que Execute dispara em background
+func (uc *CreatePixPaymentUseCase) ProcessFlow(ctx context.Context, payment *PixPayment) {
+	uc.processPaymentFlow(ctx, payment)
+}
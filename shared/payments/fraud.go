@@ -0,0 +1,167 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FraudDecision é o resultado da análise antifraude
+type FraudDecision string
+
+const (
+	FraudApprove FraudDecision = "approve" // Segue para autorização
+	FraudReview  FraudDecision = "review"  // Retido para aprovação manual
+	FraudDeny    FraudDecision = "deny"    // Recusado (REJECTED)
+)
+
+// severity ordena as decisões: quando várias regras disparam, vence a mais severa
+func (d FraudDecision) severity() int {
+	switch d {
+	case FraudDeny:
+		return 2
+	case FraudReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// FraudAssessment é a decisão da análise e as regras que dispararam
+type FraudAssessment struct {
+	Decision FraudDecision `json:"decision"`
+	Reasons  []string      `json:"reasons,omitempty"`
+}
+
+func (a *FraudAssessment) add(decision FraudDecision, reason string) {
+	a.Reasons = append(a.Reasons, reason)
+	if decision.severity() > a.Decision.severity() {
+		a.Decision = decision
+	}
+}
+
+// FraudChecker é a etapa antifraude executada antes da autorização
+type FraudChecker interface {
+	Check(ctx context.Context, payment *PixPayment) (FraudAssessment, error)
+}
+
+// PayerHistory resume o histórico do pagador (sem o pagamento em análise)
+type PayerHistory struct {
+	RecentCount         int     // Pagamentos na janela de velocidade
+	PaymentCount        int     // Total de pagamentos anteriores
+	AverageAmount       float64 // Valor médio dos pagamentos anteriores
+	PaidRecipientBefore bool    // Já pagou este recebedor (lojista) antes
+}
+
+// PayerHistoryReader fornece o histórico do pagador para as regras
+type PayerHistoryReader interface {
+	PayerHistory(ctx context.Context, payerID, recipientID string, since time.Time, excludePaymentID int64) (PayerHistory, error)
+}
+
+// VelocityRule dispara quando o pagador faz mais de MaxPayments na janela
+type VelocityRule struct {
+	Window      time.Duration `yaml:"window"`
+	MaxPayments int           `yaml:"max_payments"`
+	Action      FraudDecision `yaml:"action"`
+}
+
+// AmountAnomalyRule dispara quando o valor passa de Multiplier vezes a média
+// histórica do pagador (apenas com pelo menos MinHistory pagamentos)
+type AmountAnomalyRule struct {
+	MinHistory int           `yaml:"min_history"`
+	Multiplier float64       `yaml:"multiplier"`
+	Action     FraudDecision `yaml:"action"`
+}
+
+// NewRecipientRule dispara no primeiro pagamento do pagador para um
+// recebedor, a partir de MinAmount
+type NewRecipientRule struct {
+	MinAmount float64       `yaml:"min_amount"`
+	Action    FraudDecision `yaml:"action"`
+}
+
+// LargeAmountRule dispara para pagamentos a partir de MinAmount, que não são
+// autorizados automaticamente (análise adicional exigida pelo BACEN)
+type LargeAmountRule struct {
+	MinAmount float64       `yaml:"min_amount"`
+	Action    FraudDecision `yaml:"action"`
+}
+
+// FraudRules é a configuração do motor de regras. Uma regra sem action fica desabilitada.
+type FraudRules struct {
+	Velocity      VelocityRule      `yaml:"velocity"`
+	AmountAnomaly AmountAnomalyRule `yaml:"amount_anomaly"`
+	NewRecipient  NewRecipientRule  `yaml:"new_recipient"`
+	LargeAmount   LargeAmountRule   `yaml:"large_amount"`
+}
+
+func DefaultFraudRules() FraudRules {
+	return FraudRules{
+		Velocity:      VelocityRule{Window: time.Minute, MaxPayments: 5, Action: FraudDeny},
+		AmountAnomaly: AmountAnomalyRule{MinHistory: 3, Multiplier: 5, Action: FraudReview},
+		NewRecipient:  NewRecipientRule{MinAmount: 5_000, Action: FraudReview},
+		LargeAmount:   LargeAmountRule{MinAmount: 100_000, Action: FraudReview},
+	}
+}
+
+func (r FraudRules) Validate() error {
+	for name, action := range map[string]FraudDecision{
+		"velocity":       r.Velocity.Action,
+		"amount_anomaly": r.AmountAnomaly.Action,
+		"new_recipient":  r.NewRecipient.Action,
+		"large_amount":   r.LargeAmount.Action,
+	} {
+		if action != "" && action != FraudApprove && action != FraudReview && action != FraudDeny {
+			return fmt.Errorf("fraud rules: invalid action %q for %s", action, name)
+		}
+	}
+	if r.Velocity.Action != "" && (r.Velocity.Window <= 0 || r.Velocity.MaxPayments <= 0) {
+		return errors.New("fraud rules: velocity requires window and max_payments > 0")
+	}
+	if r.AmountAnomaly.Action != "" && r.AmountAnomaly.Multiplier <= 0 {
+		return errors.New("fraud rules: amount_anomaly requires multiplier > 0")
+	}
+	return nil
+}
+
+// RulesFraudChecker é o motor de regras (velocidade, anomalia de valor,
+// recebedor novo e valor alto)
+type RulesFraudChecker struct {
+	rules   FraudRules
+	history PayerHistoryReader
+	clock   Clock // Início da janela de velocidade
+}
+
+func NewRulesFraudChecker(rules FraudRules, history PayerHistoryReader, clock Clock) *RulesFraudChecker {
+	return &RulesFraudChecker{rules: rules, history: history, clock: clock}
+}
+
+func (c *RulesFraudChecker) Check(ctx context.Context, payment *PixPayment) (FraudAssessment, error) {
+	assessment := FraudAssessment{Decision: FraudApprove}
+
+	since := c.clock.Now().Add(-c.rules.Velocity.Window)
+	history, err := c.history.PayerHistory(ctx, payment.PayerID, payment.MerchantID, since, payment.ID)
+	if err != nil {
+		return assessment, err
+	}
+
+	if rule := c.rules.Velocity; rule.Action != "" && history.RecentCount+1 > rule.MaxPayments {
+		assessment.add(rule.Action, fmt.Sprintf("velocity: %d payments in %s (max %d)", history.RecentCount+1, rule.Window, rule.MaxPayments))
+	}
+
+	if rule := c.rules.AmountAnomaly; rule.Action != "" && history.PaymentCount >= rule.MinHistory &&
+		payment.Amount > history.AverageAmount*rule.Multiplier {
+		assessment.add(rule.Action, fmt.Sprintf("amount_anomaly: %.2f is over %.0fx the payer average of %.2f", payment.Amount, rule.Multiplier, history.AverageAmount))
+	}
+
+	if rule := c.rules.NewRecipient; rule.Action != "" && !history.PaidRecipientBefore && payment.Amount >= rule.MinAmount {
+		assessment.add(rule.Action, fmt.Sprintf("new_recipient: first payment of %.2f to %s", payment.Amount, payment.MerchantID))
+	}
+
+	if rule := c.rules.LargeAmount; rule.Action != "" && payment.Amount >= rule.MinAmount {
+		assessment.add(rule.Action, fmt.Sprintf("large_amount: %.2f is at or above %.2f", payment.Amount, rule.MinAmount))
+	}
+
+	return assessment, nil
+}
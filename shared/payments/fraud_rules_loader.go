@@ -0,0 +1,55 @@
+package payments
+
+import (
+	"fmt"
+	"os"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// LoadFraudRules lê as regras antifraude de um arquivo YAML. Uma regra ausente do
+// arquivo mantém a configuração padrão; uma regra presente sem action fica
+// desabilitada, e os demais campos ausentes dela mantêm os valores padrão. Sem
+// arquivo, valem as regras padrão.
+func LoadFraudRules(path string) (FraudRules, error) {
+	rules := DefaultFraudRules()
+	if path == "" {
+		return rules, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, fmt.Errorf("fraud rules: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return rules, fmt.Errorf("fraud rules: invalid yaml in %s: %w", path, err)
+	}
+	// Segunda leitura só para saber quais chaves o arquivo trouxe
+	var fields map[string]map[string]any
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return rules, fmt.Errorf("fraud rules: invalid yaml in %s: %w", path, err)
+	}
+	for name, rule := range fields {
+		if _, ok := rule["action"]; !ok {
+			disableFraudRule(&rules, name)
+		}
+	}
+	if err := rules.Validate(); err != nil {
+		return rules, err
+	}
+	return rules, nil
+}
+
+// disableFraudRule zera a action da regra (chaves desconhecidas são ignoradas)
+func disableFraudRule(rules *FraudRules, name string) {
+	switch name {
+	case "velocity":
+		rules.Velocity.Action = ""
+	case "amount_anomaly":
+		rules.AmountAnomaly.Action = ""
+	case "new_recipient":
+		rules.NewRecipient.Action = ""
+	case "large_amount":
+		rules.LargeAmount.Action = ""
+	}
+}
@@ -0,0 +1,86 @@
+package payments
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fraud-rules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFraudRules(t *testing.T) {
+	path := writeRules(t, `
+velocity:
+  window: 30s
+  max_payments: 3
+  action: review
+new_recipient:
+  action: ""
+`)
+
+	rules, err := LoadFraudRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.Velocity.Window != 30*time.Second || rules.Velocity.MaxPayments != 3 || rules.Velocity.Action != FraudReview {
+		t.Errorf("velocity = %+v", rules.Velocity)
+	}
+	if rules.NewRecipient.Action != "" {
+		t.Errorf("new_recipient should be disabled, got %+v", rules.NewRecipient)
+	}
+	// Não informado no arquivo: mantém o padrão
+	if rules.AmountAnomaly != DefaultFraudRules().AmountAnomaly {
+		t.Errorf("amount_anomaly = %+v", rules.AmountAnomaly)
+	}
+}
+
+// Regra no arquivo sem action: desabilitada, como diz o config/fraud-rules.yaml
+func TestLoadFraudRules_MissingActionDisables(t *testing.T) {
+	rules, err := LoadFraudRules(writeRules(t, `
+velocity:
+  window: 2m
+large_amount:
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.Velocity.Action != "" || rules.Velocity.Window != 2*time.Minute || rules.Velocity.MaxPayments != DefaultFraudRules().Velocity.MaxPayments {
+		t.Errorf("velocity = %+v, want disabled with default max_payments", rules.Velocity)
+	}
+	if rules.LargeAmount.Action != "" {
+		t.Errorf("large_amount = %+v, want disabled", rules.LargeAmount)
+	}
+	if rules.AmountAnomaly != DefaultFraudRules().AmountAnomaly {
+		t.Errorf("amount_anomaly = %+v, want default", rules.AmountAnomaly)
+	}
+}
+
+func TestLoadFraudRules_Defaults(t *testing.T) {
+	rules, err := LoadFraudRules("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules != DefaultFraudRules() {
+		t.Errorf("rules = %+v", rules)
+	}
+}
+
+func TestLoadFraudRules_Invalid(t *testing.T) {
+	if _, err := LoadFraudRules(writeRules(t, "velocity:\n  action: block\n")); err == nil {
+		t.Error("expected invalid action error")
+	}
+	if _, err := LoadFraudRules(writeRules(t, "velocity: [")); err == nil {
+		t.Error("expected yaml error")
+	}
+	if _, err := LoadFraudRules(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected missing file error")
+	}
+}
@@ -0,0 +1,96 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fixedClock parado em now (paymentstest.Clock importa este pacote)
+type fixedClock struct{ now time.Time }
+
+func (c fixedClock) Now() time.Time                     { return c.now }
+func (fixedClock) Sleep(time.Duration)                  {}
+func (fixedClock) After(time.Duration) <-chan time.Time { return nil }
+
+type stubHistory struct {
+	history PayerHistory
+	err     error
+	since   time.Time
+}
+
+func (s *stubHistory) PayerHistory(ctx context.Context, payerID, recipientID string, since time.Time, excludePaymentID int64) (PayerHistory, error) {
+	s.since = since
+	return s.history, s.err
+}
+
+func TestRulesFraudChecker(t *testing.T) {
+	rules := DefaultFraudRules()
+
+	tests := []struct {
+		name     string
+		amount   float64
+		history  PayerHistory
+		want     FraudDecision
+		nReasons int
+	}{
+		{"known payer, usual amount", 100, PayerHistory{PaymentCount: 10, AverageAmount: 80, PaidRecipientBefore: true}, FraudApprove, 0},
+		{"velocity", 100, PayerHistory{RecentCount: 5, PaymentCount: 10, AverageAmount: 80, PaidRecipientBefore: true}, FraudDeny, 1},
+		{"amount anomaly", 1_000, PayerHistory{PaymentCount: 10, AverageAmount: 100, PaidRecipientBefore: true}, FraudReview, 1},
+		{"anomaly needs history", 1_000, PayerHistory{PaymentCount: 2, AverageAmount: 100, PaidRecipientBefore: true}, FraudApprove, 0},
+		{"new recipient, small amount", 100, PayerHistory{}, FraudApprove, 0},
+		{"new recipient, large amount", 5_000, PayerHistory{}, FraudReview, 1},
+		{"large amount, known payer", 100_000, PayerHistory{PaymentCount: 10, AverageAmount: 80_000, PaidRecipientBefore: true}, FraudReview, 1},
+		{"most severe wins", 5_000, PayerHistory{RecentCount: 9, PaymentCount: 9, AverageAmount: 100}, FraudDeny, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewRulesFraudChecker(rules, &stubHistory{history: tt.history}, SystemClock{})
+			got, err := checker.Check(context.Background(), &PixPayment{ID: 1, MerchantID: "loja-a", PayerID: "pagador-1", Amount: tt.amount})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Decision != tt.want || len(got.Reasons) != tt.nReasons {
+				t.Errorf("got %s %v, want %s with %d reasons", got.Decision, got.Reasons, tt.want, tt.nReasons)
+			}
+		})
+	}
+}
+
+func TestRulesFraudChecker_VelocityWindow(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	history := &stubHistory{}
+	checker := NewRulesFraudChecker(DefaultFraudRules(), history, fixedClock{now})
+
+	if _, err := checker.Check(context.Background(), &PixPayment{Amount: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(-time.Minute); !history.since.Equal(want) {
+		t.Errorf("since = %s, want %s", history.since, want)
+	}
+
+	history.err = errors.New("db down")
+	if _, err := checker.Check(context.Background(), &PixPayment{Amount: 10}); err == nil {
+		t.Error("expected history error to be returned")
+	}
+}
+
+func TestFraudRules_Validate(t *testing.T) {
+	rules := DefaultFraudRules()
+	rules.Velocity.Action = "block"
+	if err := rules.Validate(); err == nil {
+		t.Error("expected invalid action error")
+	}
+
+	rules = DefaultFraudRules()
+	rules.Velocity.Window = 0
+	if err := rules.Validate(); err == nil {
+		t.Error("expected invalid velocity error")
+	}
+
+	// Regras sem action ficam desabilitadas e não precisam de parâmetros
+	if err := (FraudRules{}).Validate(); err != nil {
+		t.Errorf("empty rules: %v", err)
+	}
+}
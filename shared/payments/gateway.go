@@ -0,0 +1,11 @@
+package payments
+
+import "context"
+
+// PixGateway é a integração com o BACEN. O contexto carrega os IDs de
+// correlação (request_id e payment_id) usados nos logs.
+type PixGateway interface {
+	NotifyCreation(ctx context.Context, payment *PixPayment)
+	Authorize(ctx context.Context, payment *PixPayment) error
+	Settle(ctx context.Context, payment *PixPayment) error
+}
@@ -0,0 +1,79 @@
+package payments
+
+import (
+	"fmt"
+	"time"
+)
+
+// Fuso usado para o período noturno e para o "dia" do limite diário.
+// O Brasil não tem horário de verão desde 2019, então UTC-3 fixo basta
+// (e não depende de tzdata na imagem).
+var brasiliaTime = time.FixedZone("BRT", -3*60*60)
+
+// TransactionLimits são os limites de negócio aplicados a cada PIX
+type TransactionLimits struct {
+	MaxPerTransaction     float64 // Valor máximo de um único pagamento
+	DailyPerPayer         float64 // Soma máxima por pagador no dia (inclui o pagamento atual)
+	NightlyPerTransaction float64 // Valor máximo no período noturno
+	NightStartHour        int     // Início do período noturno (inclusive)
+	NightEndHour          int     // Fim do período noturno (exclusive)
+}
+
+// DefaultTransactionLimits segue a regra do BACEN de limite noturno
+// reduzido entre 20h e 6h
+func DefaultTransactionLimits() TransactionLimits {
+	return TransactionLimits{
+		MaxPerTransaction:     1_000_000,
+		DailyPerPayer:         2_000_000,
+		NightlyPerTransaction: 1_000,
+		NightStartHour:        20,
+		NightEndHour:          6,
+	}
+}
+
+// LimitError indica qual limite foi excedido
+type LimitError struct {
+	Limit string // max_per_transaction | daily_per_payer | nightly_per_transaction
+	Max   float64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("amount exceeds %s limit of %.2f", e.Limit, e.Max)
+}
+
+// Unwrap permite reconhecer qualquer limite com errors.Is(err, ErrLimitExceeded)
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// IsNighttime indica se o horário está no período noturno (ex.: 20h–6h)
+func (l TransactionLimits) IsNighttime(at time.Time) bool {
+	hour := at.In(brasiliaTime).Hour()
+	if l.NightStartHour > l.NightEndHour {
+		return hour >= l.NightStartHour || hour < l.NightEndHour
+	}
+	return hour >= l.NightStartHour && hour < l.NightEndHour
+}
+
+// StartOfDay retorna a meia-noite (horário de Brasília) do dia de "at",
+// início da janela do limite diário
+func StartOfDay(at time.Time) time.Time {
+	y, m, d := at.In(brasiliaTime).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, brasiliaTime)
+}
+
+// Check valida o valor contra os limites. payerDailyTotal é a soma dos
+// pagamentos do pagador no dia, sem o pagamento atual. Limites zerados
+// ficam desabilitados.
+func (l TransactionLimits) Check(amount, payerDailyTotal float64, at time.Time) error {
+	if l.MaxPerTransaction > 0 && amount > l.MaxPerTransaction {
+		return &LimitError{Limit: "max_per_transaction", Max: l.MaxPerTransaction}
+	}
+	if l.NightlyPerTransaction > 0 && l.IsNighttime(at) && amount > l.NightlyPerTransaction {
+		return &LimitError{Limit: "nightly_per_transaction", Max: l.NightlyPerTransaction}
+	}
+	if l.DailyPerPayer > 0 && payerDailyTotal+amount > l.DailyPerPayer {
+		return &LimitError{Limit: "daily_per_payer", Max: l.DailyPerPayer}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package payments
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTransactionLimits_Check(t *testing.T) {
+	limits := TransactionLimits{
+		MaxPerTransaction:     10_000,
+		DailyPerPayer:         15_000,
+		NightlyPerTransaction: 1_000,
+		NightStartHour:        20,
+		NightEndHour:          6,
+	}
+	day := time.Date(2024, 1, 15, 14, 0, 0, 0, brasiliaTime)
+	night := time.Date(2024, 1, 15, 22, 0, 0, 0, brasiliaTime)
+	earlyMorning := time.Date(2024, 1, 15, 5, 59, 0, 0, brasiliaTime)
+
+	tests := []struct {
+		name       string
+		amount     float64
+		dailyTotal float64
+		at         time.Time
+		wantLimit  string
+	}{
+		{"within limits", 5_000, 0, day, ""},
+		{"max per transaction", 10_000.01, 0, day, "max_per_transaction"},
+		{"daily cumulative", 6_000, 10_000, day, "daily_per_payer"},
+		{"daily exactly at limit", 5_000, 10_000, day, ""},
+		{"nighttime", 1_500, 0, night, "nightly_per_transaction"},
+		{"nighttime before 6h", 1_500, 0, earlyMorning, "nightly_per_transaction"},
+		{"nighttime small amount", 500, 0, night, ""},
+		{"nighttime checked in Brasilia time", 1_500, 0, time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.Check(tt.amount, tt.dailyTotal, tt.at)
+			var limitErr *LimitError
+			switch {
+			case tt.wantLimit == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantLimit != "" && (!errors.As(err, &limitErr) || limitErr.Limit != tt.wantLimit):
+				t.Errorf("err = %v, want %s limit", err, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	// 01:30 UTC ainda é o dia anterior em Brasília
+	got := StartOfDay(time.Date(2024, 1, 16, 1, 30, 0, 0, time.UTC))
+	want := time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("StartOfDay = %s, want %s", got, want)
+	}
+}
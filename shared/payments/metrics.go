@@ -0,0 +1,31 @@
+package payments
+
+import "time"
+
+// PaymentMetrics recebe os marcos do fluxo de pagamento para instrumentação.
+// A implementação (Prometheus) fica na infra: o domínio não conhece a biblioteca.
+type PaymentMetrics interface {
+	// PaymentStatus conta cada pagamento que chega a um status
+	PaymentStatus(status PaymentStatus)
+	// PaymentFailed conta falhas do fluxo por etapa (authorize, settle, persist...)
+	PaymentFailed(stage string)
+	// PaymentSettled registra o tempo de ponta a ponta, da criação à liquidação
+	PaymentSettled(elapsed time.Duration)
+}
+
+// BroadcasterMetrics instrumenta a entrega de eventos (SSE/WebSocket e webhooks)
+type BroadcasterMetrics interface {
+	// SubscribersChanged soma delta ao total de clientes inscritos
+	SubscribersChanged(delta int)
+	// EventDropped conta eventos descartados por um destino (ex.: "sse", "webhooks")
+	EventDropped(sink string)
+}
+
+// NopMetrics descarta as métricas (uso sem instrumentação)
+type NopMetrics struct{}
+
+func (NopMetrics) PaymentStatus(PaymentStatus)  {}
+func (NopMetrics) PaymentFailed(string)         {}
+func (NopMetrics) PaymentSettled(time.Duration) {}
+func (NopMetrics) SubscribersChanged(int)       {}
+func (NopMetrics) EventDropped(string)          {}
@@ -0,0 +1,23 @@
+package payments
+
+import "context"
+
+// NotificationType identifica a etapa do fluxo avisada ao pagador. Os valores
+// são os mesmos do contrato do notifications-service (notificationsapi).
+type NotificationType string
+
+const (
+	NotificationCreated       NotificationType = "PAYMENT_CREATED"
+	NotificationPendingReview NotificationType = "PAYMENT_PENDING_REVIEW"
+	NotificationRejected      NotificationType = "PAYMENT_REJECTED"
+	NotificationAuthorized    NotificationType = "PAYMENT_AUTHORIZED"
+	NotificationSettled       NotificationType = "PAYMENT_SETTLED"
+)
+
+// Notifier avisa o pagador de cada etapa do fluxo. É a parte do fluxo que
+// muda entre os deployables: o monólito grava a notificação no mesmo banco e o
+// payments-service chama o notifications-service por HTTP. Uma falha não
+// interrompe o fluxo (consistência eventual), só é registrada.
+type Notifier interface {
+	Notify(ctx context.Context, payment *PixPayment, notificationType NotificationType, message string) error
+}
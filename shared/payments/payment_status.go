@@ -0,0 +1,11 @@
+package payments
+
+type PaymentStatus string
+
+const (
+	StatusCreated       PaymentStatus = "CREATED"
+	StatusPendingReview PaymentStatus = "PENDING_REVIEW" // Retido pelo antifraude, aguarda aprovação manual
+	StatusAuthorized    PaymentStatus = "AUTHORIZED"
+	StatusSettled       PaymentStatus = "SETTLED"
+	StatusRejected      PaymentStatus = "REJECTED" // Recusado pelo antifraude
+)
@@ -0,0 +1,78 @@
+package paymentstest
+
+import (
+	"fintech-shared/payments"
+	"sync"
+	"testing"
+	"time"
+)
+
+// WaitTimeout limita a espera pelos eventos do fluxo em background. Com o
+// Clock manual o fluxo termina em milissegundos: estourar o prazo é um bug.
+const WaitTimeout = 5 * time.Second
+
+// Broadcaster implementa payments.EventBroadcaster guardando os eventos, e
+// permite esperar pelos que são emitidos pelo fluxo em background
+type Broadcaster struct {
+	mu      sync.Mutex
+	events  []payments.PaymentEvent
+	changed chan struct{}
+}
+
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{changed: make(chan struct{})}
+}
+
+func (b *Broadcaster) Broadcast(paymentID int64, event payments.PaymentEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// Events devolve os eventos do pagamento, na ordem em que foram emitidos
+func (b *Broadcaster) Events(paymentID int64) []payments.PaymentEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var events []payments.PaymentEvent
+	for _, event := range b.events {
+		if event.PaymentID == paymentID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Statuses devolve a sequência de status emitida para o pagamento
+func (b *Broadcaster) Statuses(paymentID int64) []payments.PaymentStatus {
+	var statuses []payments.PaymentStatus
+	for _, event := range b.Events(paymentID) {
+		statuses = append(statuses, event.Status)
+	}
+	return statuses
+}
+
+// WaitFor espera o evento do pagamento com o status; falha o teste após WaitTimeout
+func (b *Broadcaster) WaitFor(t testing.TB, paymentID int64, status payments.PaymentStatus) payments.PaymentEvent {
+	t.Helper()
+	timeout := time.After(WaitTimeout)
+	for {
+		b.mu.Lock()
+		changed := b.changed
+		for _, event := range b.events {
+			if event.PaymentID == paymentID && event.Status == status {
+				b.mu.Unlock()
+				return event
+			}
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-timeout:
+			t.Fatalf("payment %d never reached %s (events: %v)", paymentID, status, b.Statuses(paymentID))
+			return payments.PaymentEvent{}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package paymentstest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock é um relógio manual: Sleep avança o tempo na hora, sem dormir, e
+// guarda as esperas pedidas para os testes conferirem. After só dispara
+// quando o teste avança o relógio (Advance ou Sleep) até o horário pedido.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+	timers []timer
+}
+
+// timer é um After pendente
+type timer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *Clock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	c.fire()
+}
+
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.timers = append(c.timers, timer{at: c.now.Add(d), ch: ch})
+	c.fire()
+	return ch
+}
+
+// Advance avança o relógio sem registrar espera (ex.: passar o prazo da revisão)
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.fire()
+}
+
+// Waiting conta os After ainda não disparados (ex.: esperar um laço em
+// background chegar à espera antes de avançar o relógio)
+func (c *Clock) Waiting() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// fire dispara os After vencidos; chamado com o mu travado
+func (c *Clock) fire() {
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.ch <- c.now
+	}
+	c.timers = pending
+}
+
+// Sleeps devolve as esperas pedidas, na ordem
+func (c *Clock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
@@ -0,0 +1,43 @@
+package paymentstest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock_After(t *testing.T) {
+	start := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	clock := NewClock(start)
+
+	ch := clock.After(time.Minute)
+	clock.Advance(30 * time.Second)
+	select {
+	case <-ch:
+		t.Fatal("After fired before the deadline")
+	default:
+	}
+	if clock.Waiting() != 1 {
+		t.Errorf("waiting = %d, want 1", clock.Waiting())
+	}
+
+	// Sleep também avança o relógio
+	clock.Sleep(30 * time.Second)
+	select {
+	case at := <-ch:
+		if want := start.Add(time.Minute); !at.Equal(want) {
+			t.Errorf("fired at %s, want %s", at, want)
+		}
+	default:
+		t.Fatal("After did not fire at the deadline")
+	}
+	if clock.Waiting() != 0 {
+		t.Errorf("waiting = %d, want 0", clock.Waiting())
+	}
+
+	// Sem espera: dispara na hora
+	select {
+	case <-clock.After(0):
+	default:
+		t.Error("After(0) should fire immediately")
+	}
+}
@@ -0,0 +1,360 @@
+package paymentstest
+
+import (
+	"context"
+	"errors"
+	"fintech-shared/payments"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// RunRepositoryTests verifica o contrato de payments.PixPaymentRepository.
+// Roda contra o Repository em memória e contra o PostgreSQL de cada
+// deployable, para que o fake não se afaste do SQL. newRepository deve
+// devolver um repositório vazio a cada chamada.
+func RunRepositoryTests(t *testing.T, newRepository func(t *testing.T) payments.PixPaymentRepository) {
+	// Prazos em microssegundos: a precisão do TIMESTAMPTZ
+	base := time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	save := func(t *testing.T, repo payments.PixPaymentRepository, merchantID, payerID string, amount float64) *payments.PixPayment {
+		t.Helper()
+		payment, err := payments.NewPixPayment(merchantID, payerID, amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved, err := repo.SaveWithinLimit(ctx, payment, time.Time{}, func(float64) error { return nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+		return saved
+	}
+	// payerTotal lê a soma que SaveWithinLimit passa ao check, recusando a gravação
+	errProbe := errors.New("probe")
+	payerTotal := func(t *testing.T, repo payments.PixPaymentRepository, payerID string, since time.Time) float64 {
+		t.Helper()
+		var total float64
+		probe, _ := payments.NewPixPayment("loja-a", payerID, 1)
+		_, err := repo.SaveWithinLimit(ctx, probe, since, func(payerTotal float64) error {
+			total = payerTotal
+			return errProbe
+		})
+		if err != errProbe {
+			t.Fatalf("err = %v, want the check error", err)
+		}
+		return total
+	}
+	hold := func(t *testing.T, repo payments.PixPaymentRepository, deadline time.Time) *payments.PixPayment {
+		t.Helper()
+		payment := save(t, repo, "loja-a", "pagador-1", 150)
+		if err := payment.HoldForReview(deadline); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := repo.SaveReview(ctx, payment, payments.StatusCreated); !ok || err != nil {
+			t.Fatalf("SaveReview = %v, %v", ok, err)
+		}
+		return payment
+	}
+	find := func(t *testing.T, repo payments.PixPaymentRepository, id int64) *payments.PixPayment {
+		t.Helper()
+		payment, err := repo.FindByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return payment
+	}
+	ids := func(found []*payments.PixPayment) []int64 {
+		var out []int64
+		for _, p := range found {
+			out = append(out, p.ID)
+		}
+		return out
+	}
+
+	t.Run("save assigns id and created_at", func(t *testing.T) {
+		repo := newRepository(t)
+		first := save(t, repo, "loja-a", "pagador-1", 150.25)
+		second := save(t, repo, "loja-a", "pagador-1", 10)
+		if first.ID == 0 || second.ID <= first.ID {
+			t.Errorf("ids = %d, %d, want increasing", first.ID, second.ID)
+		}
+		if first.CreatedAt.IsZero() || second.CreatedAt.Before(first.CreatedAt) {
+			t.Errorf("created_at = %s, %s", first.CreatedAt, second.CreatedAt)
+		}
+
+		stored := find(t, repo, first.ID)
+		if stored.MerchantID != "loja-a" || stored.PayerID != "pagador-1" || stored.Amount != 150.25 ||
+			stored.Status != payments.StatusCreated || !stored.CreatedAt.Equal(first.CreatedAt) {
+			t.Errorf("stored = %+v, want %+v", stored, first)
+		}
+		if stored.Risk != nil || stored.Review != nil {
+			t.Errorf("risk = %+v, review = %+v, want none", stored.Risk, stored.Review)
+		}
+	})
+
+	t.Run("find unknown id", func(t *testing.T) {
+		if _, err := newRepository(t).FindByID(ctx, 999); !errors.Is(err, payments.ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("find all by merchant newest first", func(t *testing.T) {
+		repo := newRepository(t)
+		a1 := save(t, repo, "loja-a", "pagador-1", 10)
+		save(t, repo, "loja-b", "pagador-1", 20)
+		a2 := save(t, repo, "loja-a", "pagador-2", 30)
+
+		found, err := repo.FindAllByMerchant(ctx, "loja-a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ids(found), []int64{a2.ID, a1.ID}; !slices.Equal(got, want) {
+			t.Errorf("ids = %v, want %v", got, want)
+		}
+		if found, _ := repo.FindAllByMerchant(ctx, "loja-z"); len(found) != 0 {
+			t.Errorf("unknown merchant = %v, want none", ids(found))
+		}
+	})
+
+	t.Run("update status", func(t *testing.T) {
+		repo := newRepository(t)
+		payment := save(t, repo, "loja-a", "pagador-1", 10)
+
+		if err := repo.UpdateStatus(ctx, payment.ID, payments.StatusAuthorized); err != nil {
+			t.Fatal(err)
+		}
+		if stored := find(t, repo, payment.ID); stored.Status != payments.StatusAuthorized {
+			t.Errorf("status = %s, want AUTHORIZED", stored.Status)
+		}
+		// UPDATE sem linhas não é erro
+		if err := repo.UpdateStatus(ctx, 999, payments.StatusSettled); err != nil {
+			t.Errorf("unknown id: err = %v", err)
+		}
+	})
+
+	t.Run("save review compares and sets", func(t *testing.T) {
+		repo := newRepository(t)
+		held := hold(t, repo, base.Add(time.Hour))
+
+		stored := find(t, repo, held.ID)
+		if stored.Status != payments.StatusPendingReview || stored.Review == nil ||
+			!stored.Review.Deadline.Equal(base.Add(time.Hour)) || stored.Review.Decision != "" || stored.Review.ReviewedAt != nil {
+			t.Fatalf("held = %+v, review = %+v", stored, stored.Review)
+		}
+
+		// Um segundo revisor que leu o pagamento antes da decisão perde a corrida
+		late := find(t, repo, held.ID)
+		if err := stored.RejectReview("analista-1", "fraude confirmada", base); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := repo.SaveReview(ctx, stored, payments.StatusPendingReview); !ok || err != nil {
+			t.Fatalf("SaveReview = %v, %v", ok, err)
+		}
+		if err := late.Approve("analista-2", "", base); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := repo.SaveReview(ctx, late, payments.StatusPendingReview); ok || err != nil {
+			t.Errorf("late SaveReview = %v, %v, want false", ok, err)
+		}
+
+		decided := find(t, repo, held.ID)
+		review := decided.Review
+		if decided.Status != payments.StatusRejected || review == nil || review.Decision != payments.ReviewRejected ||
+			review.ReviewedBy != "analista-1" || review.Notes != "fraude confirmada" ||
+			review.ReviewedAt == nil || !review.ReviewedAt.Equal(base) || !review.Deadline.Equal(base.Add(time.Hour)) {
+			t.Errorf("decided = %+v, review = %+v", decided, review)
+		}
+	})
+
+	t.Run("review queues ordered by deadline", func(t *testing.T) {
+		repo := newRepository(t)
+		later := hold(t, repo, base.Add(2*time.Hour))
+		soon := hold(t, repo, base.Add(time.Hour))
+		alsoSoon := hold(t, repo, base.Add(time.Hour))
+		save(t, repo, "loja-a", "pagador-1", 10)
+
+		pending, err := repo.FindPendingReview(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ids(pending), []int64{soon.ID, alsoSoon.ID, later.ID}; !slices.Equal(got, want) {
+			t.Errorf("pending = %v, want %v", got, want)
+		}
+
+		// O prazo vence no próprio instante (<=)
+		expired, err := repo.FindExpiredReviews(ctx, base.Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ids(expired), []int64{soon.ID, alsoSoon.ID}; !slices.Equal(got, want) {
+			t.Errorf("expired = %v, want %v", got, want)
+		}
+		if expired, _ := repo.FindExpiredReviews(ctx, base); len(expired) != 0 {
+			t.Errorf("expired before any deadline = %v", ids(expired))
+		}
+	})
+
+	t.Run("risk assessment", func(t *testing.T) {
+		repo := newRepository(t)
+		denied := save(t, repo, "loja-a", "pagador-1", 10)
+		approved := save(t, repo, "loja-a", "pagador-1", 10)
+
+		assessments := map[int64]payments.FraudAssessment{
+			denied.ID:   {Decision: payments.FraudDeny, Reasons: []string{"velocity", "new_recipient"}},
+			approved.ID: {Decision: payments.FraudApprove},
+		}
+		for id, assessment := range assessments {
+			if err := repo.SaveRiskAssessment(ctx, id, assessment); err != nil {
+				t.Fatal(err)
+			}
+			risk := find(t, repo, id).Risk
+			if risk == nil || risk.Decision != assessment.Decision || !slices.Equal(risk.Reasons, assessment.Reasons) {
+				t.Errorf("payment %d risk = %+v, want %+v", id, risk, assessment)
+			}
+		}
+		if err := repo.SaveRiskAssessment(ctx, 999, payments.FraudAssessment{Decision: payments.FraudDeny}); err != nil {
+			t.Errorf("unknown id: err = %v", err)
+		}
+	})
+
+	t.Run("payer total since", func(t *testing.T) {
+		repo := newRepository(t)
+		first := save(t, repo, "loja-a", "pagador-1", 100.10)
+		last := save(t, repo, "loja-b", "pagador-1", 200.20)
+		save(t, repo, "loja-a", "pagador-2", 999)
+
+		tests := []struct {
+			since time.Time
+			want  float64
+		}{
+			{first.CreatedAt, 300.30},
+			{last.CreatedAt.Add(time.Hour), 0},
+		}
+		for _, tt := range tests {
+			if total := payerTotal(t, repo, "pagador-1", tt.since); total-tt.want > 0.001 || total-tt.want < -0.001 {
+				t.Errorf("since %s: total = %v, want %v", tt.since, total, tt.want)
+			}
+		}
+	})
+
+	t.Run("payer total skips rejected payments", func(t *testing.T) {
+		repo := newRepository(t)
+		paid := save(t, repo, "loja-a", "pagador-1", 100)
+		rejected := save(t, repo, "loja-a", "pagador-1", 900)
+		if err := repo.UpdateStatus(ctx, rejected.ID, payments.StatusRejected); err != nil {
+			t.Fatal(err)
+		}
+		held := hold(t, repo, base.Add(time.Hour))
+
+		// Retido para revisão ainda pode ser liquidado e conta para o limite
+		if total, want := payerTotal(t, repo, "pagador-1", paid.CreatedAt), 100+held.Amount; total != want {
+			t.Errorf("total = %v, want %v without the rejected payment", total, want)
+		}
+	})
+
+	t.Run("save within limit", func(t *testing.T) {
+		repo := newRepository(t)
+		first := save(t, repo, "loja-a", "pagador-1", 100)
+		save(t, repo, "loja-a", "pagador-2", 999)
+		since := first.CreatedAt.Add(-time.Minute)
+
+		var seen float64
+		payment, _ := payments.NewPixPayment("loja-b", "pagador-1", 50)
+		saved, err := repo.SaveWithinLimit(ctx, payment, since, func(total float64) error {
+			seen = total
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen != 100 || saved.ID == 0 || find(t, repo, saved.ID).Amount != 50 {
+			t.Errorf("check saw %v, saved = %+v", seen, saved)
+		}
+
+		// Recusado pelo check: o erro volta sem alteração e nada é gravado
+		refused := errors.New("over the limit")
+		payment, _ = payments.NewPixPayment("loja-b", "pagador-1", 70)
+		if _, err := repo.SaveWithinLimit(ctx, payment, since, func(float64) error { return refused }); err != refused {
+			t.Errorf("err = %v, want the check error", err)
+		}
+		if total := payerTotal(t, repo, "pagador-1", since); total != 150 {
+			t.Errorf("total after refusal = %v, want 150", total)
+		}
+	})
+
+	t.Run("save within limit is atomic per payer", func(t *testing.T) {
+		repo := newRepository(t)
+		// Outro pagador, só para ancorar a janela no relógio do repositório
+		since := save(t, repo, "loja-a", "pagador-2", 1).CreatedAt.Add(-time.Minute)
+		const limit, amount, attempts = 500.0, 100.0, 10
+
+		// Sem a trava, várias somas veriam o mesmo total e todas passariam
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		accepted := 0
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				payment, _ := payments.NewPixPayment("loja-a", "pagador-1", amount)
+				_, err := repo.SaveWithinLimit(ctx, payment, since, func(total float64) error {
+					if total+amount > limit {
+						return payments.ErrLimitExceeded
+					}
+					return nil
+				})
+				if err != nil && !errors.Is(err, payments.ErrLimitExceeded) {
+					t.Error(err)
+					return
+				}
+				if err == nil {
+					mu.Lock()
+					accepted++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if accepted != int(limit/amount) {
+			t.Errorf("accepted = %d, want %d", accepted, int(limit/amount))
+		}
+		if total := payerTotal(t, repo, "pagador-1", since); total != limit {
+			t.Errorf("total = %v, want %v", total, limit)
+		}
+	})
+
+	t.Run("payer history", func(t *testing.T) {
+		repo := newRepository(t)
+		paid := save(t, repo, "loja-a", "pagador-1", 100)
+		rejected := save(t, repo, "loja-b", "pagador-1", 900)
+		if err := repo.UpdateStatus(ctx, rejected.ID, payments.StatusRejected); err != nil {
+			t.Fatal(err)
+		}
+		save(t, repo, "loja-a", "pagador-1", 300)
+		current := save(t, repo, "loja-b", "pagador-1", 50)
+		save(t, repo, "loja-b", "pagador-2", 70)
+
+		// O pagamento em análise fica de fora; a recusa conta só para a velocidade
+		history, err := repo.PayerHistory(ctx, "pagador-1", "loja-b", paid.CreatedAt, current.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if history.RecentCount != 3 || history.PaymentCount != 2 || history.AverageAmount != 200 || history.PaidRecipientBefore {
+			t.Errorf("history = %+v", history)
+		}
+		history, err = repo.PayerHistory(ctx, "pagador-1", "loja-a", current.CreatedAt.Add(time.Hour), current.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if history.RecentCount != 0 || history.PaymentCount != 2 || !history.PaidRecipientBefore {
+			t.Errorf("history without recent payments = %+v", history)
+		}
+
+		if history, _ := repo.PayerHistory(ctx, "pagador-novo", "loja-a", paid.CreatedAt, 0); history != (payments.PayerHistory{}) {
+			t.Errorf("new payer history = %+v, want empty", history)
+		}
+	})
+}
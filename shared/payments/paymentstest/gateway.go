@@ -0,0 +1,45 @@
+package paymentstest
+
+import (
+	"context"
+	"fintech-shared/payments"
+	"fmt"
+	"sync"
+)
+
+// Gateway implementa payments.PixGateway registrando as chamadas, sem a
+// latência simulada do BACEN. AuthorizeErr e SettleErr simulam recusas.
+type Gateway struct {
+	AuthorizeErr error
+	SettleErr    error
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (g *Gateway) NotifyCreation(ctx context.Context, payment *payments.PixPayment) {
+	g.record("NotifyCreation", payment)
+}
+
+func (g *Gateway) Authorize(ctx context.Context, payment *payments.PixPayment) error {
+	g.record("Authorize", payment)
+	return g.AuthorizeErr
+}
+
+func (g *Gateway) Settle(ctx context.Context, payment *payments.PixPayment) error {
+	g.record("Settle", payment)
+	return g.SettleErr
+}
+
+// Calls devolve as chamadas na ordem, no formato "Settle 42"
+func (g *Gateway) Calls() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.calls...)
+}
+
+func (g *Gateway) record(method string, payment *payments.PixPayment) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls = append(g.calls, fmt.Sprintf("%s %d", method, payment.ID))
+}
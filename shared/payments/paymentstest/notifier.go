@@ -0,0 +1,42 @@
+package paymentstest
+
+import (
+	"context"
+	"fintech-shared/payments"
+	"sync"
+)
+
+// Notifier implementa payments.Notifier registrando os avisos em vez de
+// entregá-los. Err simula a notificação fora do ar: os avisos continuam
+// registrados.
+type Notifier struct {
+	Err error
+
+	mu   sync.Mutex
+	sent []notification
+}
+
+type notification struct {
+	paymentID        int64
+	notificationType payments.NotificationType
+}
+
+func (n *Notifier) Notify(ctx context.Context, payment *payments.PixPayment, notificationType payments.NotificationType, message string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, notification{paymentID: payment.ID, notificationType: notificationType})
+	return n.Err
+}
+
+// Types devolve os tipos avisados para o pagamento, na ordem
+func (n *Notifier) Types(paymentID int64) []payments.NotificationType {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var types []payments.NotificationType
+	for _, sent := range n.sent {
+		if sent.paymentID == paymentID {
+			types = append(types, sent.notificationType)
+		}
+	}
+	return types
+}
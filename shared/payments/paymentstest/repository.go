@@ -0,0 +1,236 @@
+// Package paymentstest tem implementações em memória das portas do domínio de
+// pagamentos (repositório, notifier, gateway, broadcaster e relógio) para os testes dos
+// dois deployables. Elas seguem o comportamento das implementações reais
+// (PostgreSQL, BACEN, SSE), sem rede nem esperas.
+package paymentstest
+
+import (
+	"context"
+	"fintech-shared/payments"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Repository implementa payments.PixPaymentRepository em memória, com as
+// mesmas regras das consultas SQL (ordenação, filtros e compare-and-set)
+type Repository struct {
+	mu       sync.Mutex
+	clock    payments.Clock
+	nextID   int64
+	payments map[int64]*payments.PixPayment
+}
+
+// NewRepository cria o repositório; created_at vem do relógio (nil: relógio real)
+func NewRepository(clock payments.Clock) *Repository {
+	if clock == nil {
+		clock = payments.SystemClock{}
+	}
+	return &Repository{clock: clock, payments: map[int64]*payments.PixPayment{}}
+}
+
+// Save grava o pagamento sem a soma do limite diário. Não faz parte de
+// payments.PixPaymentRepository: serve aos testes que precisam de pagamentos
+// já gravados.
+func (r *Repository) Save(ctx context.Context, payment *payments.PixPayment) (*payments.PixPayment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.insert(payment), nil
+}
+
+// SaveWithinLimit soma e grava sem soltar o mu, como a transação com o
+// advisory lock do pagador no PostgreSQL
+func (r *Repository) SaveWithinLimit(ctx context.Context, payment *payments.PixPayment, since time.Time, check func(payerTotal float64) error) (*payments.PixPayment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := check(r.payerTotal(payment.PayerID, since)); err != nil {
+		return nil, err
+	}
+	return r.insert(payment), nil
+}
+
+// insert grava o pagamento novo; chamado com o mu travado
+func (r *Repository) insert(payment *payments.PixPayment) *payments.PixPayment {
+	// Como o INSERT: só as colunas do pagamento novo, com ID e created_at do banco
+	r.nextID++
+	payment.ID = r.nextID
+	payment.CreatedAt = r.clock.Now()
+	r.payments[payment.ID] = &payments.PixPayment{
+		ID:         payment.ID,
+		MerchantID: payment.MerchantID,
+		PayerID:    payment.PayerID,
+		Amount:     payment.Amount,
+		Status:     payment.Status,
+		CreatedAt:  payment.CreatedAt,
+	}
+	return payment
+}
+
+func (r *Repository) FindByID(ctx context.Context, id int64) (*payments.PixPayment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment, ok := r.payments[id]
+	if !ok {
+		return nil, payments.ErrNotFound
+	}
+	return clone(payment), nil
+}
+
+// FindAllByMerchant lista os pagamentos do lojista, mais recentes primeiro
+func (r *Repository) FindAllByMerchant(ctx context.Context, merchantID string) ([]*payments.PixPayment, error) {
+	found := r.filter(func(p *payments.PixPayment) bool { return p.MerchantID == merchantID })
+	sort.SliceStable(found, func(i, j int) bool {
+		if !found[i].CreatedAt.Equal(found[j].CreatedAt) {
+			return found[i].CreatedAt.After(found[j].CreatedAt)
+		}
+		return found[i].ID > found[j].ID
+	})
+	return found, nil
+}
+
+// SumAmountByPayerSince é a soma que SaveWithinLimit passa ao check. Como
+// Save, fica fora de payments.PixPaymentRepository e serve aos testes.
+func (r *Repository) SumAmountByPayerSince(ctx context.Context, payerID string, since time.Time) (float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.payerTotal(payerID, since), nil
+}
+
+// payerTotal soma os pagamentos do pagador desde since, sem os recusados,
+// como o sumByPayerSQL; chamado com o mu travado
+func (r *Repository) payerTotal(payerID string, since time.Time) float64 {
+	var total float64
+	for _, p := range r.payments {
+		if p.PayerID == payerID && !p.CreatedAt.Before(since) && p.Status != payments.StatusRejected {
+			total += p.Amount
+		}
+	}
+	return total
+}
+
+// UpdateStatus não falha para IDs inexistentes, como o UPDATE sem linhas
+func (r *Repository) UpdateStatus(ctx context.Context, id int64, status payments.PaymentStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if payment, ok := r.payments[id]; ok {
+		payment.Status = status
+	}
+	return nil
+}
+
+// SaveReview grava status e revisão só se o status atual for "from"
+func (r *Repository) SaveReview(ctx context.Context, payment *payments.PixPayment, from payments.PaymentStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.payments[payment.ID]
+	if !ok || stored.Status != from {
+		return false, nil
+	}
+	stored.Status = payment.Status
+	// A revisão só é lida de volta quando tem prazo (review_deadline)
+	stored.Review = nil
+	if payment.Review != nil && !payment.Review.Deadline.IsZero() {
+		stored.Review = cloneReview(payment.Review)
+	}
+	return true, nil
+}
+
+// FindPendingReview lista a fila de revisão manual (prazo mais próximo primeiro)
+func (r *Repository) FindPendingReview(ctx context.Context) ([]*payments.PixPayment, error) {
+	return r.reviewQueue(func(p *payments.PixPayment) bool { return true }), nil
+}
+
+// FindExpiredReviews lista os pagamentos retidos cujo prazo já passou
+func (r *Repository) FindExpiredReviews(ctx context.Context, now time.Time) ([]*payments.PixPayment, error) {
+	return r.reviewQueue(func(p *payments.PixPayment) bool { return !p.Review.Deadline.After(now) }), nil
+}
+
+func (r *Repository) SaveRiskAssessment(ctx context.Context, id int64, assessment payments.FraudAssessment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if payment, ok := r.payments[id]; ok {
+		reasons := append([]string{}, assessment.Reasons...)
+		payment.Risk = &payments.FraudAssessment{Decision: assessment.Decision, Reasons: reasons}
+	}
+	return nil
+}
+
+// PayerHistory agrega o histórico do pagador como a consulta SQL: tentativas
+// recusadas contam para a velocidade, mas não para média nem recebedores
+func (r *Repository) PayerHistory(ctx context.Context, payerID, recipientID string, since time.Time, excludePaymentID int64) (payments.PayerHistory, error) {
+	var history payments.PayerHistory
+	var sum float64
+	for _, p := range r.filter(func(p *payments.PixPayment) bool { return p.PayerID == payerID && p.ID != excludePaymentID }) {
+		if !p.CreatedAt.Before(since) {
+			history.RecentCount++
+		}
+		if p.Status == payments.StatusRejected {
+			continue
+		}
+		history.PaymentCount++
+		sum += p.Amount
+		if p.MerchantID == recipientID {
+			history.PaidRecipientBefore = true
+		}
+	}
+	if history.PaymentCount > 0 {
+		history.AverageAmount = sum / float64(history.PaymentCount)
+	}
+	return history, nil
+}
+
+// reviewQueue devolve os pagamentos em PENDING_REVIEW que passam no filtro,
+// ordenados por prazo e ID
+func (r *Repository) reviewQueue(keep func(*payments.PixPayment) bool) []*payments.PixPayment {
+	found := r.filter(func(p *payments.PixPayment) bool {
+		return p.Status == payments.StatusPendingReview && p.Review != nil && keep(p)
+	})
+	sort.SliceStable(found, func(i, j int) bool {
+		if !found[i].Review.Deadline.Equal(found[j].Review.Deadline) {
+			return found[i].Review.Deadline.Before(found[j].Review.Deadline)
+		}
+		return found[i].ID < found[j].ID
+	})
+	return found
+}
+
+// filter devolve cópias dos pagamentos que passam no filtro, em ordem de ID
+func (r *Repository) filter(keep func(*payments.PixPayment) bool) []*payments.PixPayment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var found []*payments.PixPayment
+	for _, p := range r.payments {
+		if keep(p) {
+			found = append(found, clone(p))
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].ID < found[j].ID })
+	return found
+}
+
+// clone copia o pagamento: quem lê não altera o que está guardado, como no banco
+func clone(p *payments.PixPayment) *payments.PixPayment {
+	c := *p
+	if p.Risk != nil {
+		c.Risk = &payments.FraudAssessment{Decision: p.Risk.Decision, Reasons: append([]string{}, p.Risk.Reasons...)}
+	}
+	if p.Review != nil {
+		c.Review = cloneReview(p.Review)
+	}
+	return &c
+}
+
+func cloneReview(review *payments.PaymentReview) *payments.PaymentReview {
+	c := *review
+	if review.ReviewedAt != nil {
+		reviewedAt := *review.ReviewedAt
+		c.ReviewedAt = &reviewedAt
+	}
+	return &c
+}
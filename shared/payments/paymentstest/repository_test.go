@@ -0,0 +1,12 @@
+package paymentstest
+
+import (
+	"fintech-shared/payments"
+	"testing"
+)
+
+func TestRepository_Conformance(t *testing.T) {
+	RunRepositoryTests(t, func(t *testing.T) payments.PixPaymentRepository {
+		return NewRepository(nil)
+	})
+}
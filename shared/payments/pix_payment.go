@@ -0,0 +1,66 @@
+package payments
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PixPayment struct {
+	ID         int64            `json:"id"`
+	MerchantID string           `json:"merchant_id"` // Lojista dono do pagamento
+	PayerID    string           `json:"payer_id"`    // Pagador (base do limite diário)
+	Amount     float64          `json:"amount"`
+	Status     PaymentStatus    `json:"status"`
+	Risk       *FraudAssessment `json:"risk,omitempty"`   // Resultado da análise antifraude
+	Review     *PaymentReview   `json:"review,omitempty"` // Revisão manual (quando retido)
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+func NewPixPayment(merchantID, payerID string, amount float64) (*PixPayment, error) {
+	if merchantID == "" {
+		return nil, fmt.Errorf("%w: merchant is required", ErrInvalidPayment)
+	}
+	if payerID == "" {
+		return nil, fmt.Errorf("%w: payer_id is required", ErrInvalidPayment)
+	}
+	if !(amount >= 0.01) || math.IsInf(amount, 1) || !cents(amount) { // Também recusa NaN
+		return nil, ErrInvalidAmount
+	}
+	return &PixPayment{MerchantID: merchantID, PayerID: payerID, Amount: amount, Status: StatusCreated}, nil
+}
+
+// cents diz se o valor tem no máximo duas casas decimais, pela menor
+// representação decimal do float (amount*100 erra em valores como 0.29)
+func cents(amount float64) bool {
+	s := strconv.FormatFloat(amount, 'f', -1, 64)
+	dot := strings.IndexByte(s, '.')
+	return dot < 0 || len(s)-dot-1 <= 2
+}
+
+func (p *PixPayment) Authorize() error {
+	if p.Status != StatusCreated {
+		return fmt.Errorf("%w: only CREATED payments can be authorized", ErrInvalidTransition)
+	}
+	p.Status = StatusAuthorized
+	return nil
+}
+
+// Reject recusa o pagamento (decisão "deny" do antifraude)
+func (p *PixPayment) Reject() error {
+	if p.Status != StatusCreated {
+		return fmt.Errorf("%w: only CREATED payments can be rejected", ErrInvalidTransition)
+	}
+	p.Status = StatusRejected
+	return nil
+}
+
+func (p *PixPayment) Settle() error {
+	if p.Status != StatusAuthorized {
+		return fmt.Errorf("%w: only AUTHORIZED payments can be settled", ErrInvalidTransition)
+	}
+	p.Status = StatusSettled
+	return nil
+}
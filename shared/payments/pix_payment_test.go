@@ -0,0 +1,58 @@
+package payments
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// A máquina de estados completa: cada transição a partir de cada status.
+// As que não estão na tabela devem falhar sem alterar o pagamento.
+func TestPixPayment_StateMachine(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	transitions := map[string]func(*PixPayment) error{
+		"authorize": (*PixPayment).Authorize,
+		"reject":    (*PixPayment).Reject,
+		"settle":    (*PixPayment).Settle,
+		"hold":      func(p *PixPayment) error { return p.HoldForReview(now.Add(time.Hour)) },
+		"approve":   func(p *PixPayment) error { return p.Approve("analista-1", "", now) },
+		"refuse":    func(p *PixPayment) error { return p.RejectReview("analista-1", "fraude confirmada", now) },
+		"expire":    func(p *PixPayment) error { return p.ExpireReview(now.Add(2 * time.Hour)) },
+	}
+	allowed := map[PaymentStatus]map[string]PaymentStatus{
+		StatusCreated:       {"authorize": StatusAuthorized, "reject": StatusRejected, "hold": StatusPendingReview},
+		StatusPendingReview: {"approve": StatusAuthorized, "refuse": StatusRejected, "expire": StatusRejected},
+		StatusAuthorized:    {"settle": StatusSettled},
+		StatusSettled:       {},
+		StatusRejected:      {},
+	}
+
+	for from, targets := range allowed {
+		for name, apply := range transitions {
+			t.Run(string(from)+"/"+name, func(t *testing.T) {
+				payment := &PixPayment{ID: 1, Status: from, Amount: 10}
+				if from == StatusPendingReview {
+					payment.Review = &PaymentReview{Deadline: now.Add(time.Hour)}
+				}
+
+				err := apply(payment)
+				want, ok := targets[name]
+				if !ok {
+					if !errors.Is(err, ErrInvalidTransition) {
+						t.Errorf("err = %v, want ErrInvalidTransition", err)
+					}
+					if payment.Status != from {
+						t.Errorf("status = %s, want unchanged %s", payment.Status, from)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				if payment.Status != want {
+					t.Errorf("status = %s, want %s", payment.Status, want)
+				}
+			})
+		}
+	}
+}
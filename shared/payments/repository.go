@@ -0,0 +1,23 @@
+package payments
+
+import (
+	"context"
+	"time"
+)
+
+type PixPaymentRepository interface {
+	FindByID(ctx context.Context, id int64) (*PixPayment, error)
+	FindAllByMerchant(ctx context.Context, merchantID string) ([]*PixPayment, error)
+	// SaveWithinLimit grava o pagamento só se check aceitar a soma dos pagamentos
+	// do pagador desde since, sem os recusados (REJECTED). O pagador fica
+	// travado entre a soma e a gravação: pedidos simultâneos não passam juntos
+	// do limite diário. O erro de check volta sem alteração.
+	SaveWithinLimit(ctx context.Context, payment *PixPayment, since time.Time, check func(payerTotal float64) error) (*PixPayment, error)
+	UpdateStatus(ctx context.Context, id int64, status PaymentStatus) error
+	// SaveReview grava status e revisão só se o status atual for "from" (false se já mudou)
+	SaveReview(ctx context.Context, payment *PixPayment, from PaymentStatus) (bool, error)
+	FindPendingReview(ctx context.Context) ([]*PixPayment, error)
+	FindExpiredReviews(ctx context.Context, now time.Time) ([]*PixPayment, error)
+	SaveRiskAssessment(ctx context.Context, id int64, assessment FraudAssessment) error
+	PayerHistoryReader
+}
@@ -0,0 +1,103 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNotPendingReview indica que o pagamento não está (mais) aguardando revisão
+	ErrNotPendingReview = fmt.Errorf("%w: payment is not pending review", ErrInvalidTransition)
+	// ErrReviewExpired indica que o prazo da revisão manual já passou
+	ErrReviewExpired = fmt.Errorf("%w: review deadline has expired", ErrInvalidTransition)
+	// ErrReviewNotesRequired indica uma recusa manual sem justificativa
+	ErrReviewNotesRequired = errors.New("notes are required to reject a payment")
+)
+
+// ReviewDecision é o desfecho da revisão manual
+type ReviewDecision string
+
+const (
+	ReviewApproved ReviewDecision = "approved"
+	ReviewRejected ReviewDecision = "rejected"
+	ReviewExpired  ReviewDecision = "expired" // Prazo esgotado sem decisão: o pagamento é recusado
+)
+
+// SystemReviewer identifica decisões automáticas (expiração)
+const SystemReviewer = "system"
+
+// PaymentReview registra a retenção para revisão e a decisão do revisor
+type PaymentReview struct {
+	Deadline   time.Time      `json:"deadline"`
+	Decision   ReviewDecision `json:"decision,omitempty"`
+	ReviewedBy string         `json:"reviewed_by,omitempty"`
+	Notes      string         `json:"notes,omitempty"`
+	ReviewedAt *time.Time     `json:"reviewed_at,omitempty"`
+}
+
+// HoldForReview retém o pagamento para revisão manual até o prazo informado
+func (p *PixPayment) HoldForReview(deadline time.Time) error {
+	if p.Status != StatusCreated {
+		return fmt.Errorf("%w: only CREATED payments can be held for review", ErrInvalidTransition)
+	}
+	p.Status = StatusPendingReview
+	p.Review = &PaymentReview{Deadline: deadline}
+	return nil
+}
+
+// Approve libera um pagamento retido, que segue como autorizado
+func (p *PixPayment) Approve(reviewer, notes string, at time.Time) error {
+	if err := p.checkReviewable(at); err != nil {
+		return err
+	}
+	p.Status = StatusAuthorized
+	p.decide(ReviewApproved, reviewer, notes, at)
+	return nil
+}
+
+// RejectReview recusa um pagamento retido; a justificativa é obrigatória
+func (p *PixPayment) RejectReview(reviewer, notes string, at time.Time) error {
+	if err := p.checkReviewable(at); err != nil {
+		return err
+	}
+	if notes == "" {
+		return ErrReviewNotesRequired
+	}
+	p.Status = StatusRejected
+	p.decide(ReviewRejected, reviewer, notes, at)
+	return nil
+}
+
+// ExpireReview recusa um pagamento cujo prazo de revisão passou sem decisão
+func (p *PixPayment) ExpireReview(at time.Time) error {
+	if p.Status != StatusPendingReview {
+		return ErrNotPendingReview
+	}
+	if p.Review != nil && at.Before(p.Review.Deadline) {
+		return fmt.Errorf("%w: review deadline has not passed yet", ErrInvalidTransition)
+	}
+	p.Status = StatusRejected
+	p.decide(ReviewExpired, SystemReviewer, "review deadline expired", at)
+	return nil
+}
+
+func (p *PixPayment) checkReviewable(at time.Time) error {
+	if p.Status != StatusPendingReview {
+		return ErrNotPendingReview
+	}
+	if p.Review != nil && !at.Before(p.Review.Deadline) {
+		return ErrReviewExpired
+	}
+	return nil
+}
+
+func (p *PixPayment) decide(decision ReviewDecision, reviewer, notes string, at time.Time) {
+	if p.Review == nil {
+		p.Review = &PaymentReview{}
+	}
+	p.Review.Decision = decision
+	p.Review.ReviewedBy = reviewer
+	p.Review.Notes = notes
+	p.Review.ReviewedAt = &at
+}
@@ -0,0 +1,166 @@
+package payments
+
+import (
+	"context"
+	"fintech-shared/logging"
+	"fintech-shared/tracing"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ReviewPaymentUseCase trata a fila de revisão manual de pagamentos retidos
+// pelo antifraude. Aprovado, o pagamento segue o fluxo normal até a
+// liquidação; recusado ou expirado, o fluxo termina em REJECTED.
+type ReviewPaymentUseCase struct {
+	repo PixPaymentRepository
+	flow *CreatePixPaymentUseCase
+}
+
+func NewReviewPaymentUseCase(repo PixPaymentRepository, flow *CreatePixPaymentUseCase) *ReviewPaymentUseCase {
+	return &ReviewPaymentUseCase{repo: repo, flow: flow}
+}
+
+// ListPending retorna os pagamentos aguardando revisão
+func (uc *ReviewPaymentUseCase) ListPending(ctx context.Context) ([]*PixPayment, error) {
+	return uc.repo.FindPendingReview(ctx)
+}
+
+// Approve libera o pagamento retido. Ele ainda passa pela autorização do
+// BACEN antes de a decisão ser gravada: recusado lá, o pagamento termina em
+// REJECTED, com a recusa nas notas da revisão.
+func (uc *ReviewPaymentUseCase) Approve(ctx context.Context, paymentID int64, reviewer, notes string) (*PixPayment, error) {
+	ctx = logging.WithPaymentID(ctx, paymentID)
+	var refusal error
+	payment, err := uc.decide(ctx, paymentID, func(p *PixPayment) error {
+		now := uc.flow.clock.Now()
+		if err := p.checkReviewable(now); err != nil {
+			return err
+		}
+		if refusal = uc.flow.gateway.Authorize(ctx, p); refusal != nil {
+			return p.RejectReview(reviewer, "Recusado pelo BACEN na autorização", now)
+		}
+		return p.Approve(reviewer, notes, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if refusal != nil {
+		slog.WarnContext(ctx, "pix payment refused by bacen after manual review", "reviewer", reviewer, "error", refusal)
+		uc.flow.metrics.PaymentFailed("bacen_authorize")
+		uc.terminate(ctx, payment, "Pagamento PIX recusado pelo BACEN")
+		return payment, nil
+	}
+
+	// Liberado pelo revisor, o pagamento retido (ex.: regra large_amount) segue para a liquidação
+	slog.InfoContext(ctx, "pix payment released after manual review", "reviewer", reviewer, "amount", payment.Amount)
+	uc.flow.metrics.PaymentStatus(payment.Status)
+	if uc.flow.eventBroadcaster != nil {
+		uc.flow.emitStatusEvent(payment, "Pagamento PIX aprovado na revisão manual")
+	}
+	uc.flow.notify(ctx, payment, NotificationAuthorized, "Pagamento PIX aprovado na revisão manual")
+
+	// A liquidação continua em background, como no fluxo normal, sem herdar
+	// o cancelamento da requisição do revisor, numa cópia do pagamento devolvido
+	settling := *payment
+	go uc.flow.settle(context.WithoutCancel(ctx), &settling)
+
+	return payment, nil
+}
+
+func (uc *ReviewPaymentUseCase) Reject(ctx context.Context, paymentID int64, reviewer, notes string) (*PixPayment, error) {
+	ctx = logging.WithPaymentID(ctx, paymentID)
+	payment, err := uc.decide(ctx, paymentID, func(p *PixPayment) error {
+		return p.RejectReview(reviewer, notes, uc.flow.clock.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "pix payment rejected in manual review", "reviewer", reviewer)
+	uc.terminate(ctx, payment, "Pagamento PIX recusado na revisão manual")
+	return payment, nil
+}
+
+// ExpireOverdue recusa os pagamentos cujo prazo de revisão passou sem decisão
+func (uc *ReviewPaymentUseCase) ExpireOverdue(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "ExpirePaymentReviews")
+	defer span.End()
+
+	now := uc.flow.clock.Now()
+	overdue, err := uc.repo.FindExpiredReviews(ctx, now)
+	if err != nil {
+		tracing.Fail(span, err)
+		return 0, err
+	}
+
+	expired := 0
+	for _, payment := range overdue {
+		paymentCtx := logging.WithPaymentID(ctx, payment.ID)
+		if err := payment.ExpireReview(now); err != nil {
+			continue
+		}
+		ok, err := uc.repo.SaveReview(paymentCtx, payment, StatusPendingReview)
+		if err != nil {
+			slog.ErrorContext(paymentCtx, "failed to expire payment review", "error", err)
+			uc.flow.metrics.PaymentFailed("persist")
+			continue
+		}
+		if !ok {
+			continue // Decidido por um revisor nesse meio tempo
+		}
+		slog.WarnContext(paymentCtx, "payment review expired, payment rejected")
+		uc.terminate(paymentCtx, payment, "Pagamento PIX recusado: prazo de revisão expirado")
+		expired++
+	}
+	span.SetAttributes(attribute.Int("reviews.expired", expired))
+	return expired, nil
+}
+
+// StartExpiry verifica periodicamente a fila de revisão em background, até o
+// ctx (o de shutdown do servidor) ser cancelado. A espera passa pelo Clock do fluxo.
+func (uc *ReviewPaymentUseCase) StartExpiry(ctx context.Context, interval time.Duration) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-uc.flow.clock.After(interval):
+			}
+			if _, err := uc.ExpireOverdue(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to expire payment reviews", "error", err)
+			}
+		}
+	}()
+}
+
+// decide aplica a decisão ao pagamento e grava com compare-and-set: só uma
+// decisão (de um revisor ou da expiração) vale para o mesmo pagamento
+func (uc *ReviewPaymentUseCase) decide(ctx context.Context, paymentID int64, apply func(*PixPayment) error) (*PixPayment, error) {
+	payment, err := uc.repo.FindByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(payment); err != nil {
+		return nil, err
+	}
+
+	ok, err := uc.repo.SaveReview(ctx, payment, StatusPendingReview)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotPendingReview
+	}
+	return payment, nil
+}
+
+// terminate encerra o fluxo de um pagamento recusado na revisão
+func (uc *ReviewPaymentUseCase) terminate(ctx context.Context, payment *PixPayment, message string) {
+	uc.flow.metrics.PaymentStatus(payment.Status)
+	if uc.flow.eventBroadcaster != nil {
+		uc.flow.emitStatusEvent(payment, message)
+	}
+	uc.flow.notify(ctx, payment, NotificationRejected, message)
+}
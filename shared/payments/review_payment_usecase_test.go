@@ -0,0 +1,198 @@
+package payments_test
+
+import (
+	"context"
+	"errors"
+	"fintech-shared/payments"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// hold grava um pagamento retido para revisão com o prazo informado
+func (f *fixture) hold(t *testing.T, deadline time.Duration) *payments.PixPayment {
+	t.Helper()
+	payment := f.save(t, "pagador-1", 150)
+	if err := payment.HoldForReview(f.clock.Now().Add(deadline)); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := f.repo.SaveReview(context.Background(), payment, payments.StatusCreated); !ok || err != nil {
+		t.Fatalf("SaveReview = %v, %v", ok, err)
+	}
+	return payment
+}
+
+func TestReviewPayment_ApproveSettles(t *testing.T) {
+	f := newFixture(nil)
+	held := f.hold(t, reviewDeadline)
+
+	payment, err := f.review.Approve(context.Background(), held.ID, "analista-1", "cliente confirmou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != payments.StatusAuthorized || payment.Review.Decision != payments.ReviewApproved || payment.Review.ReviewedBy != "analista-1" {
+		t.Errorf("payment = %+v, review = %+v", payment, payment.Review)
+	}
+
+	f.events.WaitFor(t, held.ID, payments.StatusSettled)
+	if got := f.events.Statuses(held.ID); !reflect.DeepEqual(got, []payments.PaymentStatus{payments.StatusAuthorized, payments.StatusSettled}) {
+		t.Errorf("events = %v", got)
+	}
+	if got := f.notifier.Types(held.ID); len(got) == 0 || got[0] != payments.NotificationAuthorized {
+		t.Errorf("notifications = %v, want PAYMENT_AUTHORIZED first", got)
+	}
+	if got := f.gateway.Calls(); len(got) == 0 || got[0] != fmt.Sprintf("Authorize %d", held.ID) {
+		t.Errorf("gateway calls = %v, want the authorization first", got)
+	}
+}
+
+func TestReviewPayment_ApproveRefusedByBacen(t *testing.T) {
+	f := newFixture(nil)
+	f.gateway.AuthorizeErr = errors.New("insufficient funds")
+	held := f.hold(t, reviewDeadline)
+
+	payment, err := f.review.Approve(context.Background(), held.ID, "analista-1", "cliente confirmou")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != payments.StatusRejected || payment.Review.Decision != payments.ReviewRejected || payment.Review.ReviewedBy != "analista-1" {
+		t.Errorf("payment = %+v, review = %+v", payment, payment.Review)
+	}
+	if stored, _ := f.repo.FindByID(context.Background(), held.ID); stored.Status != payments.StatusRejected {
+		t.Errorf("stored status = %s, want REJECTED", stored.Status)
+	}
+	if got := f.events.Statuses(held.ID); !reflect.DeepEqual(got, []payments.PaymentStatus{payments.StatusRejected}) {
+		t.Errorf("events = %v", got)
+	}
+	if got := f.notifier.Types(held.ID); !reflect.DeepEqual(got, []payments.NotificationType{payments.NotificationRejected}) {
+		t.Errorf("notifications = %v", got)
+	}
+	if got, want := f.gateway.Calls(), []string{fmt.Sprintf("Authorize %d", held.ID)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("gateway calls = %v, want %v", got, want)
+	}
+}
+
+func TestReviewPayment_Reject(t *testing.T) {
+	f := newFixture(nil)
+	held := f.hold(t, reviewDeadline)
+
+	if _, err := f.review.Reject(context.Background(), held.ID, "analista-1", ""); !errors.Is(err, payments.ErrReviewNotesRequired) {
+		t.Fatalf("reject without notes: err = %v, want ErrReviewNotesRequired", err)
+	}
+	payment, err := f.review.Reject(context.Background(), held.ID, "analista-1", "fraude confirmada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != payments.StatusRejected || payment.Review.Notes != "fraude confirmada" {
+		t.Errorf("payment = %+v, review = %+v", payment, payment.Review)
+	}
+	if got := f.events.Statuses(held.ID); !reflect.DeepEqual(got, []payments.PaymentStatus{payments.StatusRejected}) {
+		t.Errorf("events = %v", got)
+	}
+	if got := f.notifier.Types(held.ID); !reflect.DeepEqual(got, []payments.NotificationType{payments.NotificationRejected}) {
+		t.Errorf("notifications = %v", got)
+	}
+}
+
+func TestReviewPayment_DecisionErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		decide func(f *fixture, id int64) error
+		want   error
+	}{
+		{"unknown payment", func(f *fixture, id int64) error {
+			_, err := f.review.Approve(context.Background(), id+1, "analista-1", "")
+			return err
+		}, payments.ErrNotFound},
+		{"already decided", func(f *fixture, id int64) error {
+			if _, err := f.review.Reject(context.Background(), id, "analista-1", "fraude"); err != nil {
+				return err
+			}
+			_, err := f.review.Approve(context.Background(), id, "analista-2", "")
+			return err
+		}, payments.ErrNotPendingReview},
+		{"deadline passed", func(f *fixture, id int64) error {
+			f.clock.Advance(reviewDeadline)
+			_, err := f.review.Approve(context.Background(), id, "analista-1", "")
+			return err
+		}, payments.ErrReviewExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(nil)
+			held := f.hold(t, reviewDeadline)
+
+			if err := tt.decide(f, held.ID); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewPayment_ExpireOverdue(t *testing.T) {
+	f := newFixture(nil)
+	overdue := f.hold(t, time.Hour)
+	pending := f.hold(t, 3*time.Hour)
+	f.clock.Advance(2 * time.Hour)
+
+	expired, err := f.review.ExpireOverdue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired != 1 {
+		t.Errorf("expired = %d, want 1", expired)
+	}
+
+	stored, _ := f.repo.FindByID(context.Background(), overdue.ID)
+	if stored.Status != payments.StatusRejected || stored.Review.Decision != payments.ReviewExpired || stored.Review.ReviewedBy != payments.SystemReviewer {
+		t.Errorf("overdue = %+v, review = %+v", stored, stored.Review)
+	}
+	queue, _ := f.review.ListPending(context.Background())
+	if len(queue) != 1 || queue[0].ID != pending.ID {
+		t.Errorf("pending queue = %v, want only payment %d", queue, pending.ID)
+	}
+
+	// Uma segunda passada não encontra mais nada vencido
+	if expired, _ := f.review.ExpireOverdue(context.Background()); expired != 0 {
+		t.Errorf("second pass expired = %d, want 0", expired)
+	}
+}
+
+func TestReviewPayment_StartExpiry(t *testing.T) {
+	f := newFixture(nil)
+	overdue := f.hold(t, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f.review.StartExpiry(ctx, time.Minute)
+
+	// O laço espera pelo Clock: só verifica a fila quando o relógio avança
+	waitUntil(t, func() bool { return f.clock.Waiting() == 1 })
+	f.clock.Advance(2 * time.Hour)
+	waitUntil(t, func() bool {
+		stored, _ := f.repo.FindByID(context.Background(), overdue.ID)
+		return stored.Status == payments.StatusRejected
+	})
+
+	// Cancelado o ctx, o laço termina e não volta a esperar o relógio
+	waitUntil(t, func() bool { return f.clock.Waiting() == 1 })
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	f.clock.Advance(time.Minute)
+	time.Sleep(20 * time.Millisecond)
+	if n := f.clock.Waiting(); n != 0 {
+		t.Errorf("expiry loop still running after cancel (%d waits)", n)
+	}
+}
+
+// waitUntil espera a condição do laço em background (até 2s)
+func waitUntil(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in 2s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
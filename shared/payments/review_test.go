@@ -0,0 +1,61 @@
+package payments
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func heldPayment(t *testing.T, deadline time.Time) *PixPayment {
+	t.Helper()
+	payment := &PixPayment{ID: 1, Status: StatusCreated, Amount: 150_000}
+	if err := payment.HoldForReview(deadline); err != nil {
+		t.Fatal(err)
+	}
+	return payment
+}
+
+func TestPixPayment_Review(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	deadline := now.Add(time.Hour)
+
+	payment := heldPayment(t, deadline)
+	if err := payment.Approve("analista-1", "cliente confirmou por telefone", now); err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != StatusAuthorized || payment.Review.Decision != ReviewApproved || payment.Review.ReviewedBy != "analista-1" {
+		t.Errorf("approved payment = %+v %+v", payment, payment.Review)
+	}
+	if err := payment.Approve("analista-2", "", now); !errors.Is(err, ErrNotPendingReview) {
+		t.Errorf("second approval: err = %v, want ErrNotPendingReview", err)
+	}
+
+	payment = heldPayment(t, deadline)
+	if err := payment.RejectReview("analista-1", "", now); !errors.Is(err, ErrReviewNotesRequired) {
+		t.Errorf("reject without notes: err = %v", err)
+	}
+	if err := payment.RejectReview("analista-1", "conta de destino suspeita", now); err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != StatusRejected || payment.Review.Decision != ReviewRejected {
+		t.Errorf("rejected payment = %+v %+v", payment, payment.Review)
+	}
+}
+
+func TestPixPayment_ExpireReview(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	payment := heldPayment(t, now)
+
+	if err := payment.ExpireReview(now.Add(-time.Second)); err == nil {
+		t.Error("expected error before the deadline")
+	}
+	if err := payment.Approve("analista-1", "", now); !errors.Is(err, ErrReviewExpired) {
+		t.Errorf("approve after deadline: err = %v, want ErrReviewExpired", err)
+	}
+	if err := payment.ExpireReview(now); err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != StatusRejected || payment.Review.Decision != ReviewExpired || payment.Review.ReviewedBy != SystemReviewer {
+		t.Errorf("expired payment = %+v %+v", payment, payment.Review)
+	}
+}
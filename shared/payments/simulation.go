@@ -0,0 +1,193 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// LatencyDistribution é a forma da latência simulada de uma chamada
+type LatencyDistribution string
+
+const (
+	LatencyFixed     LatencyDistribution = "fixed"     // Sempre Mean (padrão)
+	LatencyUniform   LatencyDistribution = "uniform"   // Uniforme entre Min e Max
+	LatencyNormal    LatencyDistribution = "normal"    // Normal com Mean e StdDev
+	LatencyLogNormal LatencyDistribution = "lognormal" // Cauda longa, dada pela mediana (P50) e pelo P99
+)
+
+// z do percentil 99 da normal padrão, para derivar o sigma da lognormal
+const z99 = 2.3263478740408408
+
+// Latency descreve a latência de uma chamada simulada ao BACEN. Min e Max
+// também limitam o sorteio da normal e da lognormal (Max 0 = sem teto).
+type Latency struct {
+	Distribution LatencyDistribution `yaml:"distribution"`
+	Mean         time.Duration       `yaml:"mean"`
+	StdDev       time.Duration       `yaml:"stddev"`
+	Min          time.Duration       `yaml:"min"`
+	Max          time.Duration       `yaml:"max"`
+	P50          time.Duration       `yaml:"p50"`
+	P99          time.Duration       `yaml:"p99"`
+}
+
+// FixedLatency é a latência constante (a simulação original)
+func FixedLatency(d time.Duration) Latency {
+	return Latency{Distribution: LatencyFixed, Mean: d}
+}
+
+// Sample sorteia uma latência da distribuição
+func (l Latency) Sample(rng *rand.Rand) time.Duration {
+	var d time.Duration
+	switch l.Distribution {
+	case LatencyUniform:
+		d = l.Min + time.Duration(rng.Int64N(int64(l.Max-l.Min)+1))
+	case LatencyNormal:
+		d = l.Mean + time.Duration(rng.NormFloat64()*float64(l.StdDev))
+	case LatencyLogNormal:
+		sigma := math.Log(float64(l.P99)/float64(l.P50)) / z99
+		d = time.Duration(float64(l.P50) * math.Exp(sigma*rng.NormFloat64()))
+	default:
+		d = l.Mean
+	}
+	if d < l.Min {
+		d = l.Min
+	}
+	if l.Max > 0 && d > l.Max {
+		d = l.Max
+	}
+	return d
+}
+
+func (l Latency) Validate() error {
+	if l.Mean < 0 || l.StdDev < 0 || l.Min < 0 || l.Max < 0 || l.P50 < 0 || l.P99 < 0 {
+		return errors.New("latencies must not be negative")
+	}
+	if l.Max > 0 && l.Max < l.Min {
+		return errors.New("max must be >= min")
+	}
+	switch l.Distribution {
+	case "", LatencyFixed, LatencyNormal:
+	case LatencyUniform:
+		if l.Max == 0 {
+			return errors.New("uniform requires max > 0")
+		}
+	case LatencyLogNormal:
+		if l.P50 <= 0 || l.P99 < l.P50 {
+			return errors.New("lognormal requires 0 < p50 <= p99")
+		}
+	default:
+		return fmt.Errorf("unknown distribution %q", l.Distribution)
+	}
+	return nil
+}
+
+// FlowTimings são as esperas entre as etapas do fluxo de pagamento. Na demo
+// elas deixam o monitor acompanhar cada mudança de status; em teste, zero.
+type FlowTimings struct {
+	Startup       time.Duration `yaml:"startup"`       // Antes de começar (tempo para o SSE conectar)
+	Authorization time.Duration `yaml:"authorization"` // Até a autorização
+	Settlement    time.Duration `yaml:"settlement"`    // Até a liquidação
+}
+
+// GatewayProfile é a latência de cada chamada ao BACEN simulado
+type GatewayProfile struct {
+	NotifyCreation Latency `yaml:"notify_creation"`
+	Authorize      Latency `yaml:"authorize"`
+	Settle         Latency `yaml:"settle"`
+}
+
+// SimulationProfile junta as esperas do fluxo e a latência do gateway
+type SimulationProfile struct {
+	Flow    FlowTimings    `yaml:"flow"`
+	Gateway GatewayProfile `yaml:"gateway"`
+}
+
+// DefaultSimulationProfile é o perfil padrão
+const DefaultSimulationProfile = "demo"
+
+var simulationProfiles = map[string]SimulationProfile{
+	// Passos visíveis no monitor e BACEN com latência fixa
+	"demo": {
+		Flow: FlowTimings{Startup: time.Second, Authorization: 2 * time.Second, Settlement: 3 * time.Second},
+		Gateway: GatewayProfile{
+			NotifyCreation: FixedLatency(100 * time.Millisecond),
+			Authorize:      FixedLatency(200 * time.Millisecond),
+			Settle:         FixedLatency(300 * time.Millisecond),
+		},
+	},
+	// Sem nenhuma espera (testes automatizados e desenvolvimento)
+	"instant": {},
+	// Sem as pausas da demo e com a cauda longa de uma rede real, para que os
+	// testes de carga vejam filas e timeouts parecidos com os de produção
+	"load": {
+		Gateway: GatewayProfile{
+			NotifyCreation: Latency{Distribution: LatencyLogNormal, P50: 40 * time.Millisecond, P99: 250 * time.Millisecond, Max: 2 * time.Second},
+			Authorize:      Latency{Distribution: LatencyLogNormal, P50: 120 * time.Millisecond, P99: 800 * time.Millisecond, Max: 5 * time.Second},
+			Settle:         Latency{Distribution: LatencyLogNormal, P50: 150 * time.Millisecond, P99: time.Second, Max: 5 * time.Second},
+		},
+	},
+}
+
+// SimulationProfileByName devolve um perfil pronto ("" é o padrão)
+func SimulationProfileByName(name string) (SimulationProfile, error) {
+	if name == "" {
+		name = DefaultSimulationProfile
+	}
+	profile, ok := simulationProfiles[name]
+	if !ok {
+		names := make([]string, 0, len(simulationProfiles))
+		for n := range simulationProfiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return SimulationProfile{}, fmt.Errorf("simulation: unknown profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return profile, nil
+}
+
+// LoadProfile monta o perfil de simulação: parte do perfil nomeado ("" é o
+// demo) e aplica o arquivo YAML por cima. Campos ausentes no arquivo mantêm
+// os valores do perfil.
+func LoadProfile(name, path string) (SimulationProfile, error) {
+	profile, err := SimulationProfileByName(name)
+	if err != nil {
+		return profile, err
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return profile, fmt.Errorf("simulation: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &profile); err != nil {
+			return profile, fmt.Errorf("simulation: invalid yaml in %s: %w", path, err)
+		}
+	}
+	if err := profile.Validate(); err != nil {
+		return profile, err
+	}
+	return profile, nil
+}
+
+func (p SimulationProfile) Validate() error {
+	if p.Flow.Startup < 0 || p.Flow.Authorization < 0 || p.Flow.Settlement < 0 {
+		return errors.New("simulation: flow timings must not be negative")
+	}
+	for name, latency := range map[string]Latency{
+		"notify_creation": p.Gateway.NotifyCreation,
+		"authorize":       p.Gateway.Authorize,
+		"settle":          p.Gateway.Settle,
+	} {
+		if err := latency.Validate(); err != nil {
+			return fmt.Errorf("simulation: gateway %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package payments
+
+import (
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSimulationProfileByName(t *testing.T) {
+	for _, name := range []string{"", "demo", "instant", "load"} {
+		profile, err := SimulationProfileByName(name)
+		if err != nil {
+			t.Fatalf("%q: %v", name, err)
+		}
+		if err := profile.Validate(); err != nil {
+			t.Errorf("%q: %v", name, err)
+		}
+	}
+
+	demo, _ := SimulationProfileByName("")
+	if demo.Flow != (FlowTimings{Startup: time.Second, Authorization: 2 * time.Second, Settlement: 3 * time.Second}) {
+		t.Errorf("default flow = %+v, want the demo timings", demo.Flow)
+	}
+	if instant, _ := SimulationProfileByName("instant"); instant != (SimulationProfile{}) {
+		t.Errorf("instant = %+v, want no delays", instant)
+	}
+	if _, err := SimulationProfileByName("turbo"); err == nil {
+		t.Error("expected unknown profile error")
+	}
+}
+
+func TestLatency_Sample(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	samples := func(l Latency) []time.Duration {
+		out := make([]time.Duration, 10_000)
+		for i := range out {
+			out[i] = l.Sample(rng)
+		}
+		slices.Sort(out)
+		return out
+	}
+
+	if got := (Latency{}).Sample(rng); got != 0 {
+		t.Errorf("zero latency = %s", got)
+	}
+	if got := FixedLatency(100 * time.Millisecond).Sample(rng); got != 100*time.Millisecond {
+		t.Errorf("fixed = %s", got)
+	}
+
+	uniform := samples(Latency{Distribution: LatencyUniform, Min: 50 * time.Millisecond, Max: 150 * time.Millisecond})
+	if uniform[0] < 50*time.Millisecond || uniform[len(uniform)-1] > 150*time.Millisecond {
+		t.Errorf("uniform outside [50ms, 150ms]: %s..%s", uniform[0], uniform[len(uniform)-1])
+	}
+
+	// A normal é cortada em Min: nunca uma latência negativa
+	normal := samples(Latency{Distribution: LatencyNormal, Mean: 10 * time.Millisecond, StdDev: 20 * time.Millisecond})
+	if normal[0] < 0 {
+		t.Errorf("normal sampled %s", normal[0])
+	}
+
+	// Mediana e P99 da lognormal próximos dos configurados (±15%), com o teto respeitado
+	lognormal := samples(Latency{Distribution: LatencyLogNormal, P50: 100 * time.Millisecond, P99: time.Second, Max: 3 * time.Second})
+	within := func(got, want time.Duration) bool {
+		return got > want*85/100 && got < want*115/100
+	}
+	if p50 := lognormal[len(lognormal)/2]; !within(p50, 100*time.Millisecond) {
+		t.Errorf("lognormal p50 = %s, want ~100ms", p50)
+	}
+	if p99 := lognormal[len(lognormal)*99/100]; !within(p99, time.Second) {
+		t.Errorf("lognormal p99 = %s, want ~1s", p99)
+	}
+	if slowest := lognormal[len(lognormal)-1]; slowest > 3*time.Second {
+		t.Errorf("lognormal max = %s, want <= 3s", slowest)
+	}
+}
+
+func TestSimulationProfile_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile SimulationProfile
+	}{
+		{"negative flow timing", SimulationProfile{Flow: FlowTimings{Settlement: -time.Second}}},
+		{"negative latency", SimulationProfile{Gateway: GatewayProfile{Settle: FixedLatency(-time.Millisecond)}}},
+		{"unknown distribution", SimulationProfile{Gateway: GatewayProfile{Authorize: Latency{Distribution: "pareto"}}}},
+		{"uniform without max", SimulationProfile{Gateway: GatewayProfile{Authorize: Latency{Distribution: LatencyUniform, Min: time.Millisecond}}}},
+		{"max below min", SimulationProfile{Gateway: GatewayProfile{Authorize: Latency{Distribution: LatencyNormal, Min: time.Second, Max: time.Millisecond}}}},
+		{"lognormal p99 below p50", SimulationProfile{Gateway: GatewayProfile{NotifyCreation: Latency{Distribution: LatencyLogNormal, P50: time.Second, P99: time.Millisecond}}}},
+	}
+	for _, tt := range tests {
+		if err := tt.profile.Validate(); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func writeProfile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "simulation.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := writeProfile(t, `
+flow:
+  settlement: 500ms
+gateway:
+  settle:
+    distribution: lognormal
+    p50: 80ms
+    p99: 1s
+`)
+
+	profile, err := LoadProfile("demo", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := FlowTimings{Startup: time.Second, Authorization: 2 * time.Second, Settlement: 500 * time.Millisecond}
+	if profile.Flow != want {
+		t.Errorf("flow = %+v, want %+v", profile.Flow, want)
+	}
+	if settle := profile.Gateway.Settle; settle.Distribution != LatencyLogNormal || settle.P50 != 80*time.Millisecond || settle.P99 != time.Second {
+		t.Errorf("settle = %+v", settle)
+	}
+	// Não informado no arquivo: mantém o perfil
+	if profile.Gateway.Authorize != FixedLatency(200*time.Millisecond) {
+		t.Errorf("authorize = %+v", profile.Gateway.Authorize)
+	}
+}
+
+func TestLoadProfile_Named(t *testing.T) {
+	for _, name := range []string{"", "instant", "load"} {
+		profile, err := LoadProfile(name, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, _ := SimulationProfileByName(name); profile != want {
+			t.Errorf("%q: profile = %+v", name, profile)
+		}
+	}
+}
+
+func TestLoadProfile_Invalid(t *testing.T) {
+	if _, err := LoadProfile("turbo", ""); err == nil {
+		t.Error("expected unknown profile error")
+	}
+	if _, err := LoadProfile("", writeProfile(t, "gateway:\n  settle:\n    distribution: pareto\n")); err == nil {
+		t.Error("expected invalid distribution error")
+	}
+	if _, err := LoadProfile("", writeProfile(t, "flow: [")); err == nil {
+		t.Error("expected yaml error")
+	}
+	if _, err := LoadProfile("", filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected missing file error")
+	}
+}
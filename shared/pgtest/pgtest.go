@@ -0,0 +1,284 @@
+// Package pgtest dá aos testes de integração dos repositórios um PostgreSQL
+// descartável. Com TEST_DATABASE_URL, usa esse servidor; senão, sobe um
+// "postgres" do PATH num diretório temporário, que vive até o fim do pacote
+// de testes. Sem nenhum dos dois, os testes são pulados.
+//
+// Cada teste recebe um banco próprio (NewDatabase, NewPool), apagado no fim.
+// O pacote de testes precisa de: func TestMain(m *testing.M) { os.Exit(pgtest.Run(m)) }
+package pgtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EnvDatabaseURL aponta para um servidor já disponível (ex.: o do docker-compose).
+// O usuário precisa poder criar bancos: cada teste usa um banco próprio.
+const EnvDatabaseURL = "TEST_DATABASE_URL"
+
+// startTimeout é quanto o postgres local tem para aceitar conexões
+const startTimeout = 30 * time.Second
+
+// errUnavailable indica que não há servidor para os testes (pula, não falha)
+var errUnavailable = errors.New("pgtest: set " + EnvDatabaseURL + " or put postgres on PATH")
+
+var (
+	once    sync.Once
+	local   *server
+	baseDSN string
+	initErr error
+	dbSeq   atomic.Int64
+)
+
+// Run executa os testes do pacote e para o postgres local, se algum teste o
+// subiu. Uso: func TestMain(m *testing.M) { os.Exit(pgtest.Run(m)) }
+func Run(m *testing.M) int {
+	code := m.Run()
+	if local != nil {
+		local.stop()
+	}
+	return code
+}
+
+// DSN devolve o DSN de administração do servidor de testes, subindo o postgres
+// local na primeira chamada. Pula o teste se não houver servidor.
+func DSN(t testing.TB) string {
+	t.Helper()
+	once.Do(func() { baseDSN, initErr = start() })
+	if errors.Is(initErr, errUnavailable) {
+		t.Skip(initErr)
+	}
+	if initErr != nil {
+		t.Fatal(initErr)
+	}
+	return baseDSN
+}
+
+// DatabaseName gera um nome de banco único para o teste
+func DatabaseName() string {
+	return fmt.Sprintf("pgtest_%d_%d", os.Getpid(), dbSeq.Add(1))
+}
+
+// WithDatabase troca o banco do DSN (formato URL)
+func WithDatabase(dsn, database string) (string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", fmt.Errorf("pgtest: invalid DSN: %w", err)
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return "", fmt.Errorf("pgtest: DSN must be a postgres:// URL, got %q", u.Scheme)
+	}
+	u.Path = "/" + database
+	return u.String(), nil
+}
+
+// SchemaFile localiza um arquivo de db/ (ex.: "init-payments.sql") subindo a
+// partir do diretório do pacote de testes até a raiz do repositório
+func SchemaFile(t testing.TB, name string) string {
+	t.Helper()
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		path := filepath.Join(dir, "db", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			t.Fatalf("pgtest: db/%s not found above the test directory", name)
+		}
+		dir = parent
+	}
+}
+
+// NewDatabase cria um banco vazio e devolve um pool para ele. O banco é
+// apagado no fim do teste; sem servidor disponível, o teste é pulado.
+func NewDatabase(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+	ctx := context.Background()
+	dsn := DSN(t)
+
+	admin, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect to test server: %v", err)
+	}
+	name := DatabaseName()
+	if _, err := admin.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		admin.Close(ctx)
+		t.Fatalf("create database: %v", err)
+	}
+	t.Cleanup(func() {
+		defer admin.Close(ctx)
+		if _, err := admin.Exec(ctx, "DROP DATABASE IF EXISTS "+name); err != nil {
+			t.Errorf("drop database %s: %v", name, err)
+		}
+	})
+
+	dsn, err = WithDatabase(dsn, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect to %s: %v", name, err)
+	}
+	// Registrado depois do DROP: roda antes dele
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// NewPool cria um banco com o schema de db/<schema> (ex.: "init-payments.sql")
+func NewPool(t testing.TB, schema string) *pgxpool.Pool {
+	t.Helper()
+	pool := NewDatabase(t)
+	sql, err := os.ReadFile(SchemaFile(t, schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sem argumentos o pgx usa o protocolo simples, que aceita vários comandos
+	if _, err := pool.Exec(context.Background(), string(sql)); err != nil {
+		t.Fatalf("apply %s: %v", schema, err)
+	}
+	return pool
+}
+
+func start() (string, error) {
+	if dsn := os.Getenv(EnvDatabaseURL); dsn != "" {
+		return dsn, nil
+	}
+	postgres, err := exec.LookPath("postgres")
+	if err != nil {
+		return "", errUnavailable
+	}
+	// O postgres se recusa a rodar como root
+	if os.Geteuid() == 0 {
+		return "", fmt.Errorf("%w (postgres cannot run as root)", errUnavailable)
+	}
+	local, err = startServer(postgres)
+	if err != nil {
+		return "", err
+	}
+	return local.dsn(), nil
+}
+
+// server é um postgres local, com dados, socket e log num diretório temporário
+type server struct {
+	dir    string
+	port   int
+	cmd    *exec.Cmd
+	exited chan struct{} // Fechado quando o processo termina
+	err    error         // Resultado do processo, válido depois de exited
+}
+
+func startServer(postgres string) (*server, error) {
+	dir, err := os.MkdirTemp("", "pgtest-")
+	if err != nil {
+		return nil, err
+	}
+	data := filepath.Join(dir, "data")
+
+	// O initdb vem da mesma instalação do postgres
+	initdb := filepath.Join(filepath.Dir(postgres), "initdb")
+	out, err := exec.Command(initdb, "-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync").CombinedOutput()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("pgtest: initdb: %w\n%s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	logFile, err := os.Create(filepath.Join(dir, "postgres.log"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	defer logFile.Close()
+
+	// Durabilidade desligada: o banco é jogado fora no fim dos testes
+	s := &server{dir: dir, port: port, exited: make(chan struct{})}
+	s.cmd = exec.Command(postgres, "-D", data, "-p", strconv.Itoa(port), "-k", dir,
+		"-c", "listen_addresses=127.0.0.1", "-c", "fsync=off",
+		"-c", "synchronous_commit=off", "-c", "full_page_writes=off")
+	s.cmd.Stdout = logFile
+	s.cmd.Stderr = logFile
+	if err := s.cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("pgtest: start postgres: %w", err)
+	}
+	go func() {
+		s.err = s.cmd.Wait()
+		close(s.exited)
+	}()
+
+	if err := s.waitReady(); err != nil {
+		s.cmd.Process.Kill()
+		<-s.exited
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return s, nil
+}
+
+// waitReady espera o postgres anunciar no log que aceita conexões
+func (s *server) waitReady() error {
+	deadline := time.After(startTimeout)
+	for {
+		logged, _ := os.ReadFile(filepath.Join(s.dir, "postgres.log"))
+		if bytes.Contains(logged, []byte("ready to accept connections")) {
+			return nil
+		}
+		select {
+		case <-s.exited:
+			return fmt.Errorf("pgtest: postgres exited: %v\n%s", s.err, logged)
+		case <-deadline:
+			return fmt.Errorf("pgtest: postgres not ready after %s\n%s", startTimeout, logged)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func (s *server) dsn() string {
+	return fmt.Sprintf("postgres://postgres@127.0.0.1:%d/postgres?sslmode=disable", s.port)
+}
+
+// stop faz o desligamento rápido (SIGINT) e apaga os dados
+func (s *server) stop() {
+	s.cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-s.exited:
+	case <-time.After(startTimeout):
+		s.cmd.Process.Kill()
+		<-s.exited
+	}
+	os.RemoveAll(s.dir)
+}
+
+// freePort reserva uma porta livre no loopback e a devolve para o postgres
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,48 @@
+package pgtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithDatabase(t *testing.T) {
+	tests := []struct {
+		dsn     string
+		want    string
+		wantErr bool
+	}{
+		{"postgres://postgres@127.0.0.1:5432/postgres?sslmode=disable", "postgres://postgres@127.0.0.1:5432/pgtest_1?sslmode=disable", false},
+		{"postgresql://user:secret@db:5432", "postgresql://user:secret@db:5432/pgtest_1", false},
+		{"host=db user=postgres dbname=postgres", "", true},
+		{"mysql://root@db/app", "", true},
+	}
+	for _, tt := range tests {
+		got, err := WithDatabase(tt.dsn, "pgtest_1")
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.dsn)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.dsn, got, err, tt.want)
+		}
+	}
+}
+
+func TestSchemaFile(t *testing.T) {
+	path := SchemaFile(t, "init-payments.sql")
+	if filepath.Base(filepath.Dir(path)) != "db" {
+		t.Errorf("path = %s, want a file in db/", path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDatabaseName(t *testing.T) {
+	if a, b := DatabaseName(), DatabaseName(); a == b {
+		t.Errorf("repeated database name %s", a)
+	}
+}
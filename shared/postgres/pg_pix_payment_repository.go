@@ -0,0 +1,257 @@
+// Package postgres tem os repositórios PostgreSQL usados pelo monólito e pelo
+// payments-service: as tabelas pix_payments e webhook_* são as mesmas nos dois
+// bancos, criadas pelas migrações de cada deployable.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fintech-shared/payments"
+	"fintech-shared/tracing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// paymentColumns é a lista de colunas lida por scanPayment
+const paymentColumns = `id, merchant_id, payer_id, amount, status, risk_decision, risk_reasons,
+	review_deadline, review_decision, reviewed_by, review_notes, reviewed_at, created_at`
+
+// PgPixPaymentRepository implementa payments.PixPaymentRepository usando PostgreSQL
+type PgPixPaymentRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgPixPaymentRepository(pool *pgxpool.Pool) *PgPixPaymentRepository {
+	return &PgPixPaymentRepository{pool: pool}
+}
+
+// insertPaymentSQL grava um pagamento novo; id e created_at vêm do banco
+const insertPaymentSQL = "INSERT INTO pix_payments (merchant_id, payer_id, amount, status) VALUES ($1, $2, $3, $4) RETURNING id, created_at"
+
+// sumByPayerSQL soma os pagamentos do pagador a partir de $2 (limite diário).
+// Os recusados não saíram da conta do pagador e não consomem o limite.
+const sumByPayerSQL = "SELECT COALESCE(SUM(amount), 0) FROM pix_payments WHERE payer_id = $1 AND created_at >= $2 AND status <> 'REJECTED'"
+
+// SaveWithinLimit soma e grava na mesma transação, com um advisory lock do
+// pagador: outra transação do mesmo pagador espera o COMMIT e já soma este
+// pagamento. Pagadores diferentes não se bloqueiam.
+func (r *PgPixPaymentRepository) SaveWithinLimit(ctx context.Context, payment *payments.PixPayment, since time.Time, check func(payerTotal float64) error) (*payments.PixPayment, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "PixPaymentRepository.SaveWithinLimit")
+	defer cancel()
+	defer span.End()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // Sem efeito após o Commit
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", payment.PayerID); err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+	var total float64
+	if err := tx.QueryRow(ctx, sumByPayerSQL, payment.PayerID, since).Scan(&total); err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+	if err := check(total); err != nil {
+		return nil, err
+	}
+	err = tx.QueryRow(ctx, insertPaymentSQL,
+		payment.MerchantID, payment.PayerID, payment.Amount, string(payment.Status),
+	).Scan(&payment.ID, &payment.CreatedAt)
+	if err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+	return payment, nil
+}
+
+func (r *PgPixPaymentRepository) FindByID(ctx context.Context, id int64) (*payments.PixPayment, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "PixPaymentRepository.FindByID")
+	defer cancel()
+	defer span.End()
+
+	payment, err := scanPayment(r.pool.QueryRow(ctx,
+		"SELECT "+paymentColumns+" FROM pix_payments WHERE id = $1",
+		id,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, payments.ErrNotFound
+	}
+	return payment, tracing.ObserveDB(span, err)
+}
+
+func (r *PgPixPaymentRepository) UpdateStatus(ctx context.Context, id int64, status payments.PaymentStatus) error {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "PixPaymentRepository.UpdateStatus")
+	defer cancel()
+	defer span.End()
+
+	_, err := r.pool.Exec(ctx,
+		"UPDATE pix_payments SET status = $1 WHERE id = $2",
+		string(status), id,
+	)
+
+	return tracing.ObserveDB(span, err)
+}
+
+// SaveReview grava o status e a revisão de forma atômica (compare-and-set),
+// evitando que dois revisores (ou o revisor e a expiração) decidam o mesmo pagamento
+func (r *PgPixPaymentRepository) SaveReview(ctx context.Context, payment *payments.PixPayment, from payments.PaymentStatus) (bool, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "PixPaymentRepository.SaveReview")
+	defer cancel()
+	defer span.End()
+
+	review := payment.Review
+	if review == nil {
+		review = &payments.PaymentReview{}
+	}
+	var deadline *time.Time
+	if !review.Deadline.IsZero() {
+		deadline = &review.Deadline
+	}
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE pix_payments
+		SET status = $1, review_deadline = $2, review_decision = NULLIF($3, ''), reviewed_by = NULLIF($4, ''),
+			review_notes = NULLIF($5, ''), reviewed_at = $6
+		WHERE id = $7 AND status = $8`,
+		string(payment.Status), deadline, string(review.Decision), review.ReviewedBy,
+		review.Notes, review.ReviewedAt, payment.ID, string(from),
+	)
+	if err != nil {
+		return false, tracing.ObserveDB(span, err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// SaveRiskAssessment grava a decisão do antifraude e as regras que dispararam
+func (r *PgPixPaymentRepository) SaveRiskAssessment(ctx context.Context, id int64, assessment payments.FraudAssessment) error {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "PixPaymentRepository.SaveRiskAssessment")
+	defer cancel()
+	defer span.End()
+
+	reasons := assessment.Reasons
+	if reasons == nil {
+		reasons = []string{}
+	}
+	_, err := r.pool.Exec(ctx,
+		"UPDATE pix_payments SET risk_decision = $1, risk_reasons = $2 WHERE id = $3",
+		string(assessment.Decision), reasons, id,
+	)
+
+	return tracing.ObserveDB(span, err)
+}
+
+func (r *PgPixPaymentRepository) FindAllByMerchant(ctx context.Context, merchantID string) ([]*payments.PixPayment, error) {
+	return r.findAll(ctx, "PixPaymentRepository.FindAllByMerchant",
+		"SELECT "+paymentColumns+" FROM pix_payments WHERE merchant_id = $1 ORDER BY created_at DESC, id DESC",
+		merchantID,
+	)
+}
+
+// FindPendingReview lista a fila de revisão manual (prazo mais próximo primeiro)
+func (r *PgPixPaymentRepository) FindPendingReview(ctx context.Context) ([]*payments.PixPayment, error) {
+	return r.findAll(ctx, "PixPaymentRepository.FindPendingReview",
+		"SELECT "+paymentColumns+" FROM pix_payments WHERE status = $1 ORDER BY review_deadline, id",
+		string(payments.StatusPendingReview),
+	)
+}
+
+// FindExpiredReviews lista os pagamentos retidos cujo prazo de revisão já passou
+func (r *PgPixPaymentRepository) FindExpiredReviews(ctx context.Context, now time.Time) ([]*payments.PixPayment, error) {
+	return r.findAll(ctx, "PixPaymentRepository.FindExpiredReviews",
+		"SELECT "+paymentColumns+" FROM pix_payments WHERE status = $1 AND review_deadline <= $2 ORDER BY review_deadline, id",
+		string(payments.StatusPendingReview), now,
+	)
+}
+
+// findAll executa a consulta no span "operation"
+func (r *PgPixPaymentRepository) findAll(ctx context.Context, operation, query string, args ...any) ([]*payments.PixPayment, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, operation)
+	defer cancel()
+	defer span.End()
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+	defer rows.Close()
+
+	var paymentsList []*payments.PixPayment
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, tracing.ObserveDB(span, err)
+		}
+		paymentsList = append(paymentsList, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+
+	return paymentsList, nil
+}
+
+// PayerHistory agrega o histórico do pagador para o antifraude. Tentativas
+// recusadas contam para a velocidade, mas não para média nem recebedores.
+func (r *PgPixPaymentRepository) PayerHistory(ctx context.Context, payerID, recipientID string, since time.Time, excludePaymentID int64) (payments.PayerHistory, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "PixPaymentRepository.PayerHistory")
+	defer cancel()
+	defer span.End()
+
+	var history payments.PayerHistory
+	err := r.pool.QueryRow(ctx,
+		`SELECT
+			COUNT(*) FILTER (WHERE created_at >= $2),
+			COUNT(*) FILTER (WHERE status <> 'REJECTED'),
+			COALESCE(AVG(amount) FILTER (WHERE status <> 'REJECTED'), 0),
+			COALESCE(BOOL_OR(merchant_id = $3 AND status <> 'REJECTED'), false)
+		FROM pix_payments WHERE payer_id = $1 AND id <> $4`,
+		payerID, since, recipientID, excludePaymentID,
+	).Scan(&history.RecentCount, &history.PaymentCount, &history.AverageAmount, &history.PaidRecipientBefore)
+
+	return history, tracing.ObserveDB(span, err)
+}
+
+// scanPayment lê uma linha com as colunas de paymentColumns
+func scanPayment(row pgx.Row) (*payments.PixPayment, error) {
+	var payment payments.PixPayment
+	var status string
+	var riskDecision, reviewDecision, reviewedBy, reviewNotes *string
+	var riskReasons []string
+	var reviewDeadline, reviewedAt *time.Time
+	err := row.Scan(&payment.ID, &payment.MerchantID, &payment.PayerID, &payment.Amount, &status,
+		&riskDecision, &riskReasons,
+		&reviewDeadline, &reviewDecision, &reviewedBy, &reviewNotes, &reviewedAt,
+		&payment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	payment.Status = payments.PaymentStatus(status)
+	if riskDecision != nil {
+		payment.Risk = &payments.FraudAssessment{Decision: payments.FraudDecision(*riskDecision), Reasons: riskReasons}
+	}
+	if reviewDeadline != nil {
+		payment.Review = &payments.PaymentReview{
+			Deadline:   *reviewDeadline,
+			Decision:   payments.ReviewDecision(deref(reviewDecision)),
+			ReviewedBy: deref(reviewedBy),
+			Notes:      deref(reviewNotes),
+			ReviewedAt: reviewedAt,
+		}
+	}
+	return &payment, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
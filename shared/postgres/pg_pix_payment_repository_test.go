@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"fintech-shared/payments"
+	"fintech-shared/payments/paymentstest"
+	"fintech-shared/pgtest"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pgtest.Run(m))
+}
+
+// schemas são os bancos dos deployables que usam os repositórios, gerados a
+// partir das migrações de cada um (as migrações rodam a mesma suíte no próprio
+// deployable)
+var schemas = []string{"init-monolith.sql", "init-payments.sql"}
+
+func TestPgPixPaymentRepository_Conformance(t *testing.T) {
+	pgtest.DSN(t) // Sem servidor, pula a suíte inteira de uma vez
+	for _, schema := range schemas {
+		t.Run(schema, func(t *testing.T) {
+			paymentstest.RunRepositoryTests(t, func(t *testing.T) payments.PixPaymentRepository {
+				return NewPgPixPaymentRepository(pgtest.NewPool(t, schema))
+			})
+		})
+	}
+}
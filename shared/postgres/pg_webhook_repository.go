@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fintech-shared/tracing"
+	"fintech-shared/webhooks"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PgWebhookRepository implementa webhooks.WebhookRepository usando PostgreSQL
+type PgWebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgWebhookRepository(pool *pgxpool.Pool) *PgWebhookRepository {
+	return &PgWebhookRepository{pool: pool}
+}
+
+func (r *PgWebhookRepository) SaveEndpoint(ctx context.Context, endpoint *webhooks.WebhookEndpoint) (*webhooks.WebhookEndpoint, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "WebhookRepository.SaveEndpoint")
+	defer cancel()
+	defer span.End()
+
+	var id int64
+	var createdAt time.Time
+	err := r.pool.QueryRow(ctx,
+		"INSERT INTO webhook_endpoints (merchant_id, url, secret, event_types) VALUES ($1, $2, $3, $4) RETURNING id, created_at",
+		endpoint.MerchantID, endpoint.URL, endpoint.Secret, endpoint.EventTypes,
+	).Scan(&id, &createdAt)
+
+	if err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+
+	endpoint.ID = id
+	endpoint.CreatedAt = createdAt
+	return endpoint, nil
+}
+
+func (r *PgWebhookRepository) FindEndpointByID(ctx context.Context, id int64) (*webhooks.WebhookEndpoint, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "WebhookRepository.FindEndpointByID")
+	defer cancel()
+	defer span.End()
+
+	var endpoint webhooks.WebhookEndpoint
+	err := r.pool.QueryRow(ctx,
+		"SELECT id, merchant_id, url, secret, event_types, created_at FROM webhook_endpoints WHERE id = $1",
+		id,
+	).Scan(&endpoint.ID, &endpoint.MerchantID, &endpoint.URL, &endpoint.Secret, &endpoint.EventTypes, &endpoint.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, webhooks.ErrEndpointNotFound
+	}
+	if err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+
+	return &endpoint, nil
+}
+
+func (r *PgWebhookRepository) FindEndpointsByMerchant(ctx context.Context, merchantID string) ([]*webhooks.WebhookEndpoint, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "WebhookRepository.FindEndpointsByMerchant")
+	defer cancel()
+	defer span.End()
+
+	rows, err := r.pool.Query(ctx,
+		"SELECT id, merchant_id, url, secret, event_types, created_at FROM webhook_endpoints WHERE merchant_id = $1 ORDER BY id",
+		merchantID,
+	)
+	if err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+	defer rows.Close()
+
+	var endpoints []*webhooks.WebhookEndpoint
+	for rows.Next() {
+		var endpoint webhooks.WebhookEndpoint
+		if err := rows.Scan(&endpoint.ID, &endpoint.MerchantID, &endpoint.URL, &endpoint.Secret, &endpoint.EventTypes, &endpoint.CreatedAt); err != nil {
+			return nil, tracing.ObserveDB(span, err)
+		}
+		endpoints = append(endpoints, &endpoint)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+
+	return endpoints, nil
+}
+
+func (r *PgWebhookRepository) SaveDelivery(ctx context.Context, delivery *webhooks.WebhookDelivery) (*webhooks.WebhookDelivery, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "WebhookRepository.SaveDelivery")
+	defer cancel()
+	defer span.End()
+
+	var id int64
+	var createdAt time.Time
+	err := r.pool.QueryRow(ctx,
+		`INSERT INTO webhook_deliveries (endpoint_id, payment_id, event_type, attempt, status_code, success, error, next_retry_at, payload)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`,
+		delivery.EndpointID, delivery.PaymentID, delivery.EventType, delivery.Attempt,
+		delivery.StatusCode, delivery.Success, delivery.Error, delivery.NextRetryAt, delivery.Payload,
+	).Scan(&id, &createdAt)
+
+	if err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+
+	delivery.ID = id
+	delivery.CreatedAt = createdAt
+	return delivery, nil
+}
+
+func (r *PgWebhookRepository) FindDeliveriesByEndpoint(ctx context.Context, endpointID int64) ([]*webhooks.WebhookDelivery, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "WebhookRepository.FindDeliveriesByEndpoint")
+	defer cancel()
+	defer span.End()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, endpoint_id, payment_id, event_type, attempt, status_code, success, error, next_retry_at, created_at
+		 FROM webhook_deliveries WHERE endpoint_id = $1 ORDER BY id DESC`,
+		endpointID,
+	)
+	if err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+	defer rows.Close()
+
+	var deliveries []*webhooks.WebhookDelivery
+	for rows.Next() {
+		var delivery webhooks.WebhookDelivery
+		if err := rows.Scan(&delivery.ID, &delivery.EndpointID, &delivery.PaymentID, &delivery.EventType, &delivery.Attempt,
+			&delivery.StatusCode, &delivery.Success, &delivery.Error, &delivery.NextRetryAt, &delivery.CreatedAt); err != nil {
+			return nil, tracing.ObserveDB(span, err)
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDueRetries reserva as retentativas vencidas com FOR UPDATE SKIP LOCKED:
+// vários workers (ou réplicas) dividem a fila sem reenviar a mesma entrega.
+// Só as linhas com payload são retentadas: as copiadas de outro banco pela
+// migração de dados não o trazem, e a retentativa fica com quem as registrou.
+func (r *PgWebhookRepository) ClaimDueRetries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*webhooks.WebhookDelivery, error) {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "WebhookRepository.ClaimDueRetries")
+	defer cancel()
+	defer span.End()
+
+	rows, err := r.pool.Query(ctx,
+		`UPDATE webhook_deliveries SET next_retry_at = $2
+		 WHERE id IN (
+		   SELECT id FROM webhook_deliveries
+		   WHERE next_retry_at <= $1 AND retried_at IS NULL AND payload IS NOT NULL
+		   ORDER BY next_retry_at LIMIT $3
+		   FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING id, endpoint_id, payment_id, event_type, attempt, status_code, success, error, next_retry_at, created_at, payload`,
+		now, now.Add(lease), limit,
+	)
+	if err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+	defer rows.Close()
+
+	var deliveries []*webhooks.WebhookDelivery
+	for rows.Next() {
+		var delivery webhooks.WebhookDelivery
+		if err := rows.Scan(&delivery.ID, &delivery.EndpointID, &delivery.PaymentID, &delivery.EventType, &delivery.Attempt,
+			&delivery.StatusCode, &delivery.Success, &delivery.Error, &delivery.NextRetryAt, &delivery.CreatedAt, &delivery.Payload); err != nil {
+			return nil, tracing.ObserveDB(span, err)
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, tracing.ObserveDB(span, err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *PgWebhookRepository) MarkRetried(ctx context.Context, id int64) error {
+	ctx, span, cancel := tracing.StartDBSpan(ctx, "WebhookRepository.MarkRetried")
+	defer cancel()
+	defer span.End()
+
+	_, err := r.pool.Exec(ctx, "UPDATE webhook_deliveries SET retried_at = now() WHERE id = $1", id)
+	return tracing.ObserveDB(span, err)
+}
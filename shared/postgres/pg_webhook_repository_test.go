@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fintech-shared/pgtest"
+	"fintech-shared/webhooks"
+	"testing"
+	"time"
+)
+
+func TestPgWebhookRepository_ClaimDueRetries(t *testing.T) {
+	pgtest.DSN(t)
+	for _, schema := range schemas {
+		t.Run(schema, func(t *testing.T) {
+			testClaimDueRetries(t, NewPgWebhookRepository(pgtest.NewPool(t, schema)))
+		})
+	}
+}
+
+func testClaimDueRetries(t *testing.T, repo *PgWebhookRepository) {
+	ctx := context.Background()
+	endpoint, err := repo.SaveEndpoint(ctx, &webhooks.WebhookEndpoint{
+		MerchantID: "merchant-a", URL: "https://lojista.example.com/hook", Secret: "s3cr3t",
+		EventTypes: []string{webhooks.EventPaymentSettled},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	save := func(next *time.Time, payload []byte) *webhooks.WebhookDelivery {
+		t.Helper()
+		delivery, err := repo.SaveDelivery(ctx, &webhooks.WebhookDelivery{
+			EndpointID: endpoint.ID, PaymentID: 1, EventType: webhooks.EventPaymentSettled, Attempt: 1,
+			StatusCode: 503, Error: "endpoint returned status 503", NextRetryAt: next, Payload: payload,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return delivery
+	}
+	past, future := now.Add(-time.Second), now.Add(time.Minute)
+	due := save(&past, []byte(`{"type":"payment.settled"}`))
+	save(&future, []byte(`{}`)) // Ainda não venceu
+	save(nil, nil)              // Sem retentativa
+	save(&past, nil)            // Copiada de outro banco, sem o corpo
+
+	claimed, err := repo.ClaimDueRetries(ctx, now, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != due.ID || string(claimed[0].Payload) != `{"type":"payment.settled"}` {
+		t.Fatalf("claimed = %+v, want delivery %d", claimed, due.ID)
+	}
+	if !claimed[0].NextRetryAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("next_retry_at = %s, want the lease end %s", claimed[0].NextRetryAt, now.Add(time.Minute))
+	}
+
+	// Reservada: só volta depois do lease
+	if again, _ := repo.ClaimDueRetries(ctx, now, time.Minute, 10); len(again) != 0 {
+		t.Errorf("claimed again before the lease ended: %+v", again)
+	}
+	if again, _ := repo.ClaimDueRetries(ctx, now.Add(time.Minute), time.Minute, 10); len(again) != 2 {
+		t.Errorf("claimed after the lease = %d deliveries, want 2", len(again))
+	}
+
+	// Retentada: sai da fila de vez
+	if err := repo.MarkRetried(ctx, due.ID); err != nil {
+		t.Fatal(err)
+	}
+	later, err := repo.ClaimDueRetries(ctx, now.Add(time.Hour), time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, delivery := range later {
+		if delivery.ID == due.ID {
+			t.Error("retried delivery claimed again")
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Package ratelimit limita as requisições às APIs de pagamento por lojista (ou
+// por IP, sem autenticação) com um token bucket em memória, por instância.
+package ratelimit
+
+import (
+	"fintech-shared/auth"
+	"fintech-shared/httpapi"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Buckets parados há mais tempo que isso são descartados (já estariam cheios)
+const idleTTL = 10 * time.Minute
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// TokenBucketLimiter limita requisições por cliente: cada chave tem um balde
+// com capacidade "burst" que é reabastecido a "rate" tokens por segundo
+type TokenBucketLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewTokenBucketLimiter(rate float64, burst int) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow consome um token da chave. Quando não há token disponível, retorna
+// false e o tempo até o próximo token.
+func (l *TokenBucketLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = b
+	}
+
+	// Reabastecer proporcionalmente ao tempo desde a última requisição
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.lastSeen).Seconds()*l.rate)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep remove buckets ociosos para a memória não crescer com IPs/chaves antigos
+func (l *TokenBucketLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTTL {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Middleware aplica o limite por credencial (API key/JWT) ou, sem principal
+// autenticado, por IP. Deve ficar depois do middleware de autenticação.
+// Excedido o limite, responde 429 com Retry-After (segundos).
+func (l *TokenBucketLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := clientKey(r)
+		allowed, wait := l.Allow(key)
+		if !allowed {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			slog.WarnContext(r.Context(), "rate limit exceeded", "method", r.Method, "path", r.URL.Path, "client", key, "retry_after_s", retryAfter)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			httpapi.WriteProblem(w, r, http.StatusTooManyRequests, httpapi.CodeRateLimited, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func clientKey(r *http.Request) string {
+	if p, ok := auth.PrincipalFrom(r.Context()); ok {
+		return "principal:" + p.Subject
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
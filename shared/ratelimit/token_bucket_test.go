@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"fintech-shared/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketLimiter_Allow(t *testing.T) {
+	now := time.Now()
+	l := NewTokenBucketLimiter(2, 3) // 2 tokens/s, rajada de 3
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("request %d within burst was limited", i+1)
+		}
+	}
+	ok, wait := l.Allow("a")
+	if ok {
+		t.Fatal("request over burst was allowed")
+	}
+	if wait != 500*time.Millisecond {
+		t.Errorf("wait = %s, want 500ms", wait)
+	}
+
+	// Outra chave tem seu próprio balde
+	if ok, _ := l.Allow("b"); !ok {
+		t.Error("independent key was limited")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Error("request after refill was limited")
+	}
+}
+
+func TestTokenBucketLimiter_Middleware(t *testing.T) {
+	l := NewTokenBucketLimiter(0.5, 1)
+	handler := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(subject string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payments/pix", nil)
+		if subject != "" {
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Subject: subject}))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := request("apikey:key-a"); rec.Code != http.StatusOK {
+		t.Fatalf("first request: status = %d", rec.Code)
+	}
+	rec := request("apikey:key-a")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request: status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+
+	// Outro principal e requisições sem principal (por IP) não são afetados
+	if rec := request("apikey:key-b"); rec.Code != http.StatusOK {
+		t.Errorf("other principal: status = %d", rec.Code)
+	}
+	if rec := request(""); rec.Code != http.StatusOK {
+		t.Errorf("by IP: status = %d", rec.Code)
+	}
+}
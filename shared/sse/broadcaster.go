@@ -0,0 +1,101 @@
+// Package sse entrega as mudanças de status dos pagamentos aos clientes
+// conectados por Server-Sent Events. O transporte WebSocket (pacote ws) se
+// inscreve no mesmo Broadcaster.
+package sse
+
+import (
+	"fintech-shared/payments"
+	"log/slog"
+	"sync"
+)
+
+// keyPaymentID é a mesma chave de log usada pelo logging dos deployables
+const keyPaymentID = "payment_id"
+
+// Broadcaster gerencia os clientes conectados, por pagamento. Implementa
+// payments.EventBroadcaster.
+type Broadcaster struct {
+	clients map[int64]map[chan payments.PaymentEvent]bool
+	mu      sync.RWMutex
+	metrics payments.BroadcasterMetrics
+}
+
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		clients: make(map[int64]map[chan payments.PaymentEvent]bool),
+		metrics: payments.NopMetrics{},
+	}
+}
+
+// SetMetrics define onde registrar clientes inscritos e eventos descartados
+func (b *Broadcaster) SetMetrics(metrics payments.BroadcasterMetrics) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.metrics = metrics
+}
+
+// Subscribe adiciona um cliente para receber eventos de um pagamento específico
+func (b *Broadcaster) Subscribe(paymentID int64) chan payments.PaymentEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan payments.PaymentEvent, 10) // Buffer de 10 eventos
+
+	if b.clients[paymentID] == nil {
+		b.clients[paymentID] = make(map[chan payments.PaymentEvent]bool)
+	}
+	b.clients[paymentID][ch] = true
+	b.metrics.SubscribersChanged(1)
+
+	slog.Debug("event client subscribed", keyPaymentID, paymentID, "clients", len(b.clients[paymentID]))
+	return ch
+}
+
+// Unsubscribe remove um cliente
+func (b *Broadcaster) Unsubscribe(paymentID int64, ch chan payments.PaymentEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.remove(paymentID, ch) {
+		slog.Debug("event client unsubscribed", keyPaymentID, paymentID)
+	}
+}
+
+// remove tira o cliente e fecha o canal uma única vez (o cliente pode já ter
+// sido removido por canal cheio). Deve ser chamado com o lock.
+func (b *Broadcaster) remove(paymentID int64, ch chan payments.PaymentEvent) bool {
+	clients, ok := b.clients[paymentID]
+	if !ok || !clients[ch] {
+		return false
+	}
+	delete(clients, ch)
+	if len(clients) == 0 {
+		delete(b.clients, paymentID)
+	}
+	close(ch)
+	b.metrics.SubscribersChanged(-1)
+	return true
+}
+
+// Broadcast envia um evento para todos os clientes de um pagamento
+func (b *Broadcaster) Broadcast(paymentID int64, event payments.PaymentEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if clients, ok := b.clients[paymentID]; ok {
+		for ch := range clients {
+			select {
+			case ch <- event:
+			default:
+				// Canal cheio, remover cliente
+				slog.Warn("event client channel full, removing client", keyPaymentID, paymentID)
+				b.metrics.EventDropped("sse")
+				go func(c chan payments.PaymentEvent) {
+					b.mu.Lock()
+					b.remove(paymentID, c)
+					b.mu.Unlock()
+				}(ch)
+			}
+		}
+	}
+}
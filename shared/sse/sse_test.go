@@ -0,0 +1,92 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"fintech-shared/payments"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBroadcaster(t *testing.T) {
+	b := NewBroadcaster()
+	first, second := b.Subscribe(1), b.Subscribe(1)
+	other := b.Subscribe(2)
+
+	b.Broadcast(1, payments.PaymentEvent{PaymentID: 1, Status: payments.StatusAuthorized})
+	for _, ch := range []chan payments.PaymentEvent{first, second} {
+		if event := <-ch; event.Status != payments.StatusAuthorized {
+			t.Errorf("event = %+v", event)
+		}
+	}
+	select {
+	case event := <-other:
+		t.Errorf("payment 2 received %+v", event)
+	default:
+	}
+
+	// Unsubscribe fecha o canal; repetir não causa pânico
+	b.Unsubscribe(1, first)
+	b.Unsubscribe(1, first)
+	if _, ok := <-first; ok {
+		t.Error("channel still open after unsubscribe")
+	}
+}
+
+func TestStream(t *testing.T) {
+	b := NewBroadcaster()
+	payment := &payments.PixPayment{ID: 7, MerchantID: "loja-a", Amount: 10, Status: payments.StatusCreated}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Stream(w, r, b, payment)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		var lines []string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line == "\n" {
+				return strings.Join(lines, "")
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if got := readEvent(); !strings.HasPrefix(got, "event: initial\n") || !strings.Contains(got, `"status":"CREATED"`) {
+		t.Errorf("initial event = %q", got)
+	}
+	// O cliente já está inscrito depois do evento inicial
+	deadline := time.Now().Add(time.Second)
+	for {
+		b.mu.RLock()
+		subscribed := len(b.clients[7]) == 1
+		b.mu.RUnlock()
+		if subscribed || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	b.Broadcast(7, payments.PaymentEvent{PaymentID: 7, Status: payments.StatusSettled})
+	if got := readEvent(); !strings.HasPrefix(got, "event: status_change\n") || !strings.Contains(got, `"status":"SETTLED"`) {
+		t.Errorf("status event = %q", got)
+	}
+}
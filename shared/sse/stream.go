@@ -0,0 +1,78 @@
+package sse
+
+import (
+	"encoding/json"
+	"fintech-shared/payments"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Stream envia o status atual do pagamento e depois cada mudança, até o
+// cliente desconectar. Autenticação e a busca do pagamento ficam com o
+// handler; o contexto de r já deve carregar o payment_id dos logs.
+func Stream(w http.ResponseWriter, r *http.Request, broadcaster *Broadcaster, payment *payments.PixPayment) {
+	ctx := r.Context()
+
+	// CORS é tratado pelo middleware, apenas para origens configuradas
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// Enviar status inicial imediatamente
+	initialEvent := payments.PaymentEvent{
+		PaymentID:  payment.ID,
+		MerchantID: payment.MerchantID,
+		Status:     payment.Status,
+		Amount:     payment.Amount,
+		Timestamp:  time.Now(),
+		Message:    "Status inicial do pagamento",
+	}
+	if err := WriteEvent(w, "initial", initialEvent); err != nil {
+		slog.ErrorContext(ctx, "failed to send initial sse event", "error", err)
+		return
+	}
+	flush(w)
+
+	eventChan := broadcaster.Subscribe(payment.ID)
+	defer broadcaster.Unsubscribe(payment.ID, eventChan)
+
+	// Enviar eventos em tempo real
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-eventChan:
+			if !ok {
+				return // Removido pelo broadcaster (canal cheio)
+			}
+			if err := WriteEvent(w, "status_change", event); err != nil {
+				slog.ErrorContext(ctx, "failed to send sse event", "error", err)
+				return
+			}
+			flush(w)
+		}
+	}
+}
+
+// WriteEvent escreve um evento no formato SSE ("event:" e "data:" em JSON)
+func WriteEvent(w io.Writer, eventType string, data payments.PaymentEvent) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write([]byte("event: " + eventType + "\n"))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte("data: " + string(jsonData) + "\n\n"))
+	return err
+}
+
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,36 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryTimeout limita cada operação no banco
+const queryTimeout = 5 * time.Second
+
+var dbTracer = otel.Tracer("fintech-shared/tracing")
+
+// StartDBSpan abre o span de uma operação dos repositórios (filho do span de
+// quem chamou) e aplica o timeout da consulta
+func StartDBSpan(ctx context.Context, operation string) (context.Context, trace.Span, context.CancelFunc) {
+	ctx, span := dbTracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL),
+	)
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	return ctx, span, cancel
+}
+
+// ObserveDB marca o span como falho quando há erro ("não encontrado" não é falha) e devolve o erro
+func ObserveDB(span trace.Span, err error) error {
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		Fail(span, err)
+	}
+	return err
+}
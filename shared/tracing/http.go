@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"fintech-shared/httpapi"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Handler abre um span por requisição, continuando o trace do traceparent
+// recebido. O nome do span usa a rota registrada no mux (ex.: "GET /pix/{id}"),
+// não a URL. Health check, probes e métricas não geram spans.
+func Handler(next http.Handler, mux *httpapi.ServeMux) http.Handler {
+	return otelhttp.NewHandler(next, "http.server",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			_, route := mux.Handler(r)
+			switch {
+			case route == "":
+				route = r.Method + " unmatched"
+			case !strings.Contains(route, " "):
+				// Padrão sem método (health, métricas, Swagger)
+				route = r.Method + " " + route
+			}
+			return route
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			switch r.URL.Path {
+			case "/health", "/livez", "/readyz", "/metrics":
+				return false
+			}
+			return true
+		}),
+	)
+}
+
+// Transport instrumenta as chamadas HTTP de saída: cria o span do cliente e
+// injeta o traceparent na requisição
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
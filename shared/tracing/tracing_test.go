@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"fintech-shared/httpapi"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTransportAndHandler_PropagateTraceContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	mux := httpapi.NewServeMux()
+	mux.HandleFunc("POST /notifications", func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusCreated)
+	})
+	server := httptest.NewServer(Handler(mux, mux))
+	defer server.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "ProcessPixPayment")
+	client := &http.Client{Transport: Transport(nil)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/notifications", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	if traceparent == "" {
+		t.Fatal("traceparent header was not sent")
+	}
+
+	traceID := parent.SpanContext().TraceID()
+	var serverSpan sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() != traceID {
+			t.Errorf("span %q is in trace %s, want %s", span.Name(), span.SpanContext().TraceID(), traceID)
+		}
+		if span.Name() == "POST /notifications" {
+			serverSpan = span
+		}
+	}
+	if serverSpan == nil {
+		t.Fatal("server span named after the route was not recorded")
+	}
+	if !serverSpan.Parent().IsRemote() {
+		t.Error("server span should continue the remote (client) span")
+	}
+}
+
+func TestHandler_SkipsHealthAndMetrics(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	mux := httpapi.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+	handler := Handler(mux, mux)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if n := len(recorder.Ended()); n != 0 {
+		t.Errorf("recorded %d spans for /health, want 0", n)
+	}
+}
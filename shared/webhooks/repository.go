@@ -0,0 +1,20 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+)
+
+type WebhookRepository interface {
+	SaveEndpoint(ctx context.Context, endpoint *WebhookEndpoint) (*WebhookEndpoint, error)
+	FindEndpointByID(ctx context.Context, id int64) (*WebhookEndpoint, error)
+	FindEndpointsByMerchant(ctx context.Context, merchantID string) ([]*WebhookEndpoint, error)
+	SaveDelivery(ctx context.Context, delivery *WebhookDelivery) (*WebhookDelivery, error)
+	FindDeliveriesByEndpoint(ctx context.Context, endpointID int64) ([]*WebhookDelivery, error)
+	// ClaimDueRetries reserva até limit entregas com a retentativa vencida em now,
+	// adiando o next_retry_at delas por lease: se o processo cair antes do
+	// MarkRetried, a retentativa vence de novo e outro worker a assume
+	ClaimDueRetries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error)
+	// MarkRetried tira a entrega da fila depois que a tentativa seguinte foi registrada
+	MarkRetried(ctx context.Context, id int64) error
+}
@@ -0,0 +1,115 @@
+// Package webhooks é o cadastro de webhooks dos lojistas e a entrega dos
+// eventos de pagamento a eles (WebhookDispatcher), com assinatura HMAC e
+// retentativas persistidas.
+package webhooks
+
+import (
+	"errors"
+	"fintech-shared/egress"
+	"fintech-shared/payments"
+	"fmt"
+	"time"
+)
+
+// Tipos de evento que podem ser assinados por um webhook
+const (
+	EventPaymentCreated       = "payment.created"
+	EventPaymentPendingReview = "payment.pending_review"
+	EventPaymentAuthorized    = "payment.authorized"
+	EventPaymentSettled       = "payment.settled"
+	EventPaymentRejected      = "payment.rejected"
+)
+
+// EventTypeForStatus converte o status do pagamento no tipo de evento do webhook
+func EventTypeForStatus(status payments.PaymentStatus) string {
+	switch status {
+	case payments.StatusCreated:
+		return EventPaymentCreated
+	case payments.StatusPendingReview:
+		return EventPaymentPendingReview
+	case payments.StatusAuthorized:
+		return EventPaymentAuthorized
+	case payments.StatusSettled:
+		return EventPaymentSettled
+	case payments.StatusRejected:
+		return EventPaymentRejected
+	}
+	return ""
+}
+
+func isValidEventType(eventType string) bool {
+	switch eventType {
+	case EventPaymentCreated, EventPaymentPendingReview, EventPaymentAuthorized, EventPaymentSettled, EventPaymentRejected:
+		return true
+	}
+	return false
+}
+
+var (
+	// ErrEndpointNotFound indica que o endpoint não existe (o repositório o devolve no lugar do erro do driver)
+	ErrEndpointNotFound = errors.New("webhook not found")
+	// ErrInvalidEndpoint indica um cadastro de endpoint inválido
+	ErrInvalidEndpoint = errors.New("invalid webhook endpoint")
+)
+
+// WebhookEndpoint é um endpoint registrado por um lojista para receber
+// callbacks de mudança de status dos seus pagamentos
+type WebhookEndpoint struct {
+	ID         int64     `json:"id"`
+	MerchantID string    `json:"merchant_id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"-"` // Nunca devolvido pela API
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewWebhookEndpoint(merchantID, endpointURL, secret string, eventTypes []string) (*WebhookEndpoint, error) {
+	if merchantID == "" {
+		return nil, fmt.Errorf("%w: merchant is required", ErrInvalidEndpoint)
+	}
+	// Só https e sem apontar para a rede interna; o dispatcher confere de novo o
+	// endereço resolvido na hora de cada entrega
+	if err := egress.CheckURL(endpointURL); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEndpoint, err)
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("%w: secret is required", ErrInvalidEndpoint)
+	}
+	if len(eventTypes) == 0 {
+		return nil, fmt.Errorf("%w: at least one event type is required", ErrInvalidEndpoint)
+	}
+	for _, eventType := range eventTypes {
+		if !isValidEventType(eventType) {
+			return nil, fmt.Errorf("%w: unknown event type: %s", ErrInvalidEndpoint, eventType)
+		}
+	}
+	return &WebhookEndpoint{MerchantID: merchantID, URL: endpointURL, Secret: secret, EventTypes: eventTypes}, nil
+}
+
+// Subscribes indica se o endpoint assinou o tipo de evento
+func (e *WebhookEndpoint) Subscribes(eventType string) bool {
+	for _, t := range e.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery registra uma tentativa de entrega de um evento a um endpoint
+type WebhookDelivery struct {
+	ID          int64      `json:"id"`
+	EndpointID  int64      `json:"endpoint_id"`
+	PaymentID   int64      `json:"payment_id"`
+	EventType   string     `json:"event_type"`
+	Attempt     int        `json:"attempt"`
+	StatusCode  int        `json:"status_code"` // 0 quando não houve resposta
+	Success     bool       `json:"success"`
+	Error       string     `json:"error,omitempty"`
+	NextRetryAt *time.Time `json:"next_retry_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Payload é o corpo enviado, guardado só quando há retentativa agendada:
+	// o worker de retentativas reenvia exatamente o mesmo evento
+	Payload []byte `json:"-"`
+}
@@ -0,0 +1,275 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fintech-shared/egress"
+	"fintech-shared/logging"
+	"fintech-shared/payments"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers enviados em cada entrega. O lojista valida a assinatura
+// recalculando HMAC-SHA256(secret, timestamp + "." + body).
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+)
+
+// Intervalo máximo entre tentativas, independente do número da tentativa
+const maxBackoff = 5 * time.Minute
+
+// Fila de retentativas em webhook_deliveries
+const (
+	retryBatch = 50          // Retentativas reservadas por consulta
+	retryLease = time.Minute // Reserva de cada retentativa: se o processo cair, ela vence de novo
+)
+
+// WebhookPayload é o corpo enviado aos lojistas
+type WebhookPayload struct {
+	Type string                `json:"type"`
+	Data payments.PaymentEvent `json:"data"`
+}
+
+// WebhookDispatcher consome os mesmos PaymentEvents emitidos pelo use case
+// (implementa payments.EventBroadcaster) e os entrega aos endpoints registrados,
+// com assinatura HMAC e retentativas com backoff exponencial. As retentativas
+// ficam em webhook_deliveries (next_retry_at) e sobrevivem a um restart.
+type WebhookDispatcher struct {
+	repo        WebhookRepository
+	httpClient  *http.Client
+	queue       chan payments.PaymentEvent
+	maxAttempts int
+	baseBackoff time.Duration
+	clock       payments.Clock
+	metrics     payments.BroadcasterMetrics
+}
+
+func NewWebhookDispatcher(repo WebhookRepository, maxAttempts int, baseBackoff time.Duration, clock payments.Clock) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo: repo,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+			// Só endereços públicos, conferidos no dial: a URL vem do lojista
+			Transport: egress.Transport(),
+			// A entrega vale para a URL cadastrada: um 3xx é devolvido como falha
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		queue:       make(chan payments.PaymentEvent, 100),
+		maxAttempts: maxAttempts,
+		baseBackoff: baseBackoff,
+		clock:       clock,
+		metrics:     payments.NopMetrics{},
+	}
+}
+
+// SetMetrics define onde registrar os eventos descartados por fila cheia.
+// Deve ser chamado antes de Start.
+func (d *WebhookDispatcher) SetMetrics(metrics payments.BroadcasterMetrics) {
+	d.metrics = metrics
+}
+
+// Start inicia os workers que consomem a fila de eventos e o laço que, a cada
+// retryInterval, reenvia as retentativas vencidas. Todos param com o ctx.
+func (d *WebhookDispatcher) Start(ctx context.Context, workers int, retryInterval time.Duration) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event := <-d.queue:
+					d.dispatch(ctx, event)
+				}
+			}
+		}()
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-d.clock.After(retryInterval):
+			}
+			d.retryDue(ctx)
+		}
+	}()
+}
+
+// Broadcast enfileira o evento sem bloquear o fluxo do pagamento
+func (d *WebhookDispatcher) Broadcast(paymentID int64, event payments.PaymentEvent) {
+	select {
+	case d.queue <- event:
+	default:
+		slog.Warn("webhook queue full, event dropped", logging.KeyPaymentID, paymentID, "status", event.Status)
+		d.metrics.EventDropped("webhooks")
+	}
+}
+
+func (d *WebhookDispatcher) dispatch(ctx context.Context, event payments.PaymentEvent) {
+	eventType := EventTypeForStatus(event.Status)
+	if eventType == "" || event.MerchantID == "" {
+		return
+	}
+
+	// O evento chega pela fila, sem a requisição de origem: os logs da entrega
+	// são correlacionados pelo payment_id
+	ctx = logging.WithPaymentID(ctx, event.PaymentID)
+
+	// Cada lojista só recebe eventos dos seus próprios pagamentos
+	endpoints, err := d.repo.FindEndpointsByMerchant(ctx, event.MerchantID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to find webhook endpoints", "error", err)
+		return
+	}
+
+	body, err := json.Marshal(WebhookPayload{Type: eventType, Data: event})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode webhook event", "error", err)
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(eventType) {
+			continue
+		}
+		// A primeira tentativa sai daqui; as seguintes, do laço de retentativas
+		d.attempt(ctx, endpoint, event.PaymentID, eventType, body, 1)
+	}
+}
+
+// retryDue reserva as retentativas vencidas e faz a tentativa seguinte de cada uma
+func (d *WebhookDispatcher) retryDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		due, err := d.repo.ClaimDueRetries(ctx, d.clock.Now(), retryLease, retryBatch)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to claim webhook retries", "error", err)
+			return
+		}
+		for _, previous := range due {
+			d.retry(ctx, previous)
+		}
+		if len(due) < retryBatch {
+			return
+		}
+	}
+}
+
+func (d *WebhookDispatcher) retry(ctx context.Context, previous *WebhookDelivery) {
+	ctx = logging.WithPaymentID(ctx, previous.PaymentID)
+	endpoint, err := d.repo.FindEndpointByID(ctx, previous.EndpointID)
+	if err != nil {
+		// Continua reservada: volta a vencer depois do retryLease
+		slog.ErrorContext(ctx, "failed to find webhook endpoint", "endpoint_id", previous.EndpointID, "error", err)
+		return
+	}
+	d.attempt(ctx, endpoint, previous.PaymentID, previous.EventType, previous.Payload, previous.Attempt+1)
+	if err := d.repo.MarkRetried(ctx, previous.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to mark webhook delivery as retried", "delivery_id", previous.ID, "error", err)
+	}
+}
+
+// attempt faz uma tentativa de entrega e a registra. Se a falha for temporária,
+// o registro agenda a próxima (next_retry_at) e guarda o corpo para o reenvio.
+func (d *WebhookDispatcher) attempt(ctx context.Context, endpoint *WebhookEndpoint, paymentID int64, eventType string, body []byte, attempt int) {
+	statusCode, err := d.post(ctx, endpoint, eventType, body)
+
+	delivery := &WebhookDelivery{
+		EndpointID: endpoint.ID,
+		PaymentID:  paymentID,
+		EventType:  eventType,
+		Attempt:    attempt,
+		StatusCode: statusCode,
+		Success:    err == nil,
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	// Um endereço proibido não passa a ser permitido na próxima tentativa
+	retry := err != nil && attempt < d.maxAttempts && isRetryable(statusCode) && !errors.Is(err, egress.ErrForbiddenAddress)
+	var wait time.Duration
+	if retry {
+		wait = d.backoff(attempt)
+		next := d.clock.Now().Add(wait)
+		delivery.NextRetryAt = &next
+		delivery.Payload = body
+	}
+
+	if _, saveErr := d.repo.SaveDelivery(ctx, delivery); saveErr != nil {
+		slog.ErrorContext(ctx, "failed to save webhook delivery", "error", saveErr)
+	}
+
+	switch {
+	case err == nil:
+		slog.InfoContext(ctx, "webhook delivered", "event", eventType, "endpoint_id", endpoint.ID, "attempt", attempt)
+	case !retry:
+		slog.ErrorContext(ctx, "webhook delivery abandoned", "event", eventType, "endpoint_id", endpoint.ID, "attempt", attempt, "error", err)
+	default:
+		slog.WarnContext(ctx, "webhook delivery failed, retry scheduled", "event", eventType, "endpoint_id", endpoint.ID, "attempt", attempt, "retry_in", wait.String(), "error", err)
+	}
+}
+
+func (d *WebhookDispatcher) post(ctx context.Context, endpoint *WebhookEndpoint, eventType string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	// Timestamp novo a cada tentativa, para que o lojista possa rejeitar replays
+	timestamp := d.clock.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, SignPayload(endpoint.Secret, timestamp, body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff calcula a espera exponencial: base, 2*base, 4*base... (limitado a maxBackoff)
+func (d *WebhookDispatcher) backoff(attempt int) time.Duration {
+	wait := d.baseBackoff << (attempt - 1)
+	if wait <= 0 || wait > maxBackoff {
+		return maxBackoff
+	}
+	return wait
+}
+
+// isRetryable indica se vale a pena tentar de novo: falhas de rede, 5xx,
+// 408 e 429. Os demais 4xx indicam erro permanente do lado do lojista.
+func isRetryable(statusCode int) bool {
+	return statusCode == 0 ||
+		statusCode >= 500 ||
+		statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusTooManyRequests
+}
+
+// SignPayload calcula a assinatura enviada no header X-Webhook-Signature
+func SignPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
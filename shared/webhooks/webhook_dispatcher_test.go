@@ -0,0 +1,446 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fintech-shared/payments"
+	"fintech-shared/payments/paymentstest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryWebhookRepository guarda endpoints e entregas em memória e avisa
+// em um canal a cada entrega registrada
+type memoryWebhookRepository struct {
+	mu         sync.Mutex
+	endpoints  []*WebhookEndpoint
+	deliveries []*WebhookDelivery
+	retried    map[int64]bool
+	saved      chan *WebhookDelivery
+}
+
+func newMemoryWebhookRepository() *memoryWebhookRepository {
+	return &memoryWebhookRepository{retried: make(map[int64]bool), saved: make(chan *WebhookDelivery, 100)}
+}
+
+func (r *memoryWebhookRepository) SaveEndpoint(_ context.Context, endpoint *WebhookEndpoint) (*WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	endpoint.ID = int64(len(r.endpoints) + 1)
+	r.endpoints = append(r.endpoints, endpoint)
+	return endpoint, nil
+}
+
+func (r *memoryWebhookRepository) FindEndpointByID(_ context.Context, id int64) (*WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.endpoints {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memoryWebhookRepository) FindEndpointsByMerchant(_ context.Context, merchantID string) ([]*WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*WebhookEndpoint
+	for _, e := range r.endpoints {
+		if e.MerchantID == merchantID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryWebhookRepository) SaveDelivery(_ context.Context, delivery *WebhookDelivery) (*WebhookDelivery, error) {
+	r.mu.Lock()
+	delivery.ID = int64(len(r.deliveries) + 1)
+	r.deliveries = append(r.deliveries, delivery)
+	r.mu.Unlock()
+	r.saved <- delivery
+	return delivery, nil
+}
+
+func (r *memoryWebhookRepository) FindDeliveriesByEndpoint(_ context.Context, endpointID int64) ([]*WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.EndpointID == endpointID {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryWebhookRepository) ClaimDueRetries(_ context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*WebhookDelivery
+	for _, d := range r.deliveries {
+		if len(out) == limit {
+			break
+		}
+		if d.NextRetryAt == nil || d.NextRetryAt.After(now) || r.retried[d.ID] || d.Payload == nil {
+			continue
+		}
+		until := now.Add(lease)
+		d.NextRetryAt = &until
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func (r *memoryWebhookRepository) MarkRetried(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retried[id] = true
+	return nil
+}
+
+func (r *memoryWebhookRepository) waitDelivery(t *testing.T) *WebhookDelivery {
+	t.Helper()
+	select {
+	case d := <-r.saved:
+		return d
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for webhook delivery")
+		return nil
+	}
+}
+
+func (r *memoryWebhookRepository) assertNoDelivery(t *testing.T) {
+	t.Helper()
+	select {
+	case d := <-r.saved:
+		t.Fatalf("unexpected delivery: %+v", d)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// registerEndpoint grava o endpoint direto no repositório: os receptores dos
+// testes são httptest em http://127.0.0.1, que NewWebhookEndpoint rejeita
+func registerEndpoint(t *testing.T, repo *memoryWebhookRepository, merchantID, url string, eventTypes ...string) *WebhookEndpoint {
+	t.Helper()
+	saved, _ := repo.SaveEndpoint(context.Background(), &WebhookEndpoint{MerchantID: merchantID, URL: url, Secret: "top-secret", EventTypes: eventTypes})
+	return saved
+}
+
+// startDispatcher inicia o dispatcher com um cliente HTTP sem o bloqueio de
+// endereços privados, para alcançar os receptores locais. O laço de
+// retentativas só roda quando o teste chama retryDue.
+func startDispatcher(t *testing.T, repo *memoryWebhookRepository, maxAttempts int, clock *paymentstest.Clock) *WebhookDispatcher {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcher := NewWebhookDispatcher(repo, maxAttempts, time.Second, clock)
+	dispatcher.httpClient = &http.Client{Timeout: 5 * time.Second}
+	dispatcher.Start(ctx, 1, time.Hour)
+	return dispatcher
+}
+
+func newClock() *paymentstest.Clock {
+	return paymentstest.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+}
+
+func TestWebhookDispatcher_DeliversSignedEvent(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	repo := newMemoryWebhookRepository()
+	endpoint := registerEndpoint(t, repo, "merchant-a", receiver.URL, EventPaymentAuthorized)
+
+	dispatcher := startDispatcher(t, repo, 3, newClock())
+	dispatcher.Broadcast(42, payments.PaymentEvent{MerchantID: "merchant-a", PaymentID: 42, Status: payments.StatusAuthorized, Amount: 10.5})
+
+	var req *http.Request
+	select {
+	case req = <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("receiver was not called")
+	}
+
+	if got := req.Header.Get(EventHeader); got != EventPaymentAuthorized {
+		t.Errorf("event header = %q, want %q", got, EventPaymentAuthorized)
+	}
+	timestamp, err := strconv.ParseInt(req.Header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header: %v", err)
+	}
+	if got, want := req.Header.Get(SignatureHeader), SignPayload(endpoint.Secret, timestamp, body); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.Type != EventPaymentAuthorized || payload.Data.PaymentID != 42 || payload.Data.Amount != 10.5 {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+
+	delivery := repo.waitDelivery(t)
+	if !delivery.Success || delivery.Attempt != 1 || delivery.StatusCode != http.StatusNoContent || delivery.NextRetryAt != nil || delivery.Payload != nil {
+		t.Errorf("unexpected delivery log: %+v", delivery)
+	}
+}
+
+func TestWebhookDispatcher_RetriesWithBackoff(t *testing.T) {
+	var calls int32
+	bodies := make(chan []byte, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	repo := newMemoryWebhookRepository()
+	registerEndpoint(t, repo, "merchant-a", receiver.URL, EventPaymentSettled)
+
+	clock := newClock()
+	dispatcher := startDispatcher(t, repo, 5, clock)
+	dispatcher.Broadcast(7, payments.PaymentEvent{MerchantID: "merchant-a", PaymentID: 7, Status: payments.StatusSettled})
+
+	first := repo.waitDelivery(t)
+	for attempt := 1; attempt <= 3; attempt++ {
+		var delivery *WebhookDelivery
+		if attempt == 1 {
+			delivery = first
+		} else {
+			// Antes de vencer, nada é reenviado
+			dispatcher.retryDue(context.Background())
+			repo.assertNoDelivery(t)
+
+			clock.Advance(time.Second << (attempt - 2))
+			dispatcher.retryDue(context.Background())
+			delivery = repo.waitDelivery(t)
+		}
+		if delivery.Attempt != attempt {
+			t.Fatalf("attempt = %d, want %d", delivery.Attempt, attempt)
+		}
+		lastAttempt := attempt == 3
+		if delivery.Success != lastAttempt {
+			t.Errorf("attempt %d: success = %v", attempt, delivery.Success)
+		}
+		if lastAttempt != (delivery.NextRetryAt == nil) || lastAttempt != (delivery.Payload == nil) {
+			t.Errorf("attempt %d: next_retry_at = %v, payload = %q", attempt, delivery.NextRetryAt, delivery.Payload)
+		}
+		if want := clock.Now().Add(time.Second << (attempt - 1)); !lastAttempt && !delivery.NextRetryAt.Equal(want) {
+			t.Errorf("attempt %d: next_retry_at = %s, want %s", attempt, delivery.NextRetryAt, want)
+		}
+		if !lastAttempt && delivery.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("attempt %d: status code = %d", attempt, delivery.StatusCode)
+		}
+	}
+
+	// As retentativas reenviam exatamente o mesmo evento
+	sent := <-bodies
+	for i := 2; i <= 3; i++ {
+		if body := <-bodies; !bytes.Equal(body, sent) {
+			t.Errorf("attempt %d body = %s, want %s", i, body, sent)
+		}
+	}
+
+	// Tudo entregue: a fila de retentativas ficou vazia
+	clock.Advance(time.Hour)
+	dispatcher.retryDue(context.Background())
+	repo.assertNoDelivery(t)
+}
+
+// Uma retentativa reservada e não concluída (processo caiu) volta a vencer
+// depois do retryLease
+func TestWebhookDispatcher_RetriesAfterRestart(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	repo := newMemoryWebhookRepository()
+	endpoint := registerEndpoint(t, repo, "merchant-a", receiver.URL, EventPaymentSettled)
+	clock := newClock()
+	next := clock.Now()
+	_, _ = repo.SaveDelivery(context.Background(), &WebhookDelivery{
+		EndpointID: endpoint.ID, PaymentID: 7, EventType: EventPaymentSettled, Attempt: 1,
+		StatusCode: http.StatusBadGateway, NextRetryAt: &next, Payload: []byte(`{"type":"payment.settled"}`),
+	})
+	repo.waitDelivery(t)
+
+	// Reservada por uma instância que caiu antes de tentar
+	if claimed, _ := repo.ClaimDueRetries(context.Background(), clock.Now(), retryLease, retryBatch); len(claimed) != 1 {
+		t.Fatalf("claimed = %d, want 1", len(claimed))
+	}
+
+	dispatcher := startDispatcher(t, repo, 5, clock)
+	dispatcher.retryDue(context.Background())
+	repo.assertNoDelivery(t)
+
+	clock.Advance(retryLease)
+	dispatcher.retryDue(context.Background())
+	if delivery := repo.waitDelivery(t); !delivery.Success || delivery.Attempt != 2 {
+		t.Errorf("unexpected delivery log: %+v", delivery)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("receiver calls = %d, want 1", n)
+	}
+}
+
+func TestWebhookDispatcher_StopsWithContext(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer receiver.Close()
+
+	repo := newMemoryWebhookRepository()
+	registerEndpoint(t, repo, "merchant-a", receiver.URL, EventPaymentCreated)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher := NewWebhookDispatcher(repo, 1, time.Second, newClock())
+	dispatcher.httpClient = &http.Client{Timeout: 5 * time.Second}
+	dispatcher.Start(ctx, 1, time.Second)
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+
+	dispatcher.Broadcast(1, payments.PaymentEvent{MerchantID: "merchant-a", PaymentID: 1, Status: payments.StatusCreated})
+	repo.assertNoDelivery(t)
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Errorf("receiver calls = %d after the context was canceled", n)
+	}
+}
+
+func TestWebhookDispatcher_RefusesPrivateAddresses(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer receiver.Close()
+
+	repo := newMemoryWebhookRepository()
+	registerEndpoint(t, repo, "merchant-a", receiver.URL, EventPaymentCreated)
+
+	// Cliente padrão: o receptor em 127.0.0.1 é recusado no dial
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher := NewWebhookDispatcher(repo, 5, time.Second, newClock())
+	dispatcher.Start(ctx, 1, time.Hour)
+	dispatcher.Broadcast(1, payments.PaymentEvent{MerchantID: "merchant-a", PaymentID: 1, Status: payments.StatusCreated})
+
+	delivery := repo.waitDelivery(t)
+	if delivery.Success || delivery.NextRetryAt != nil || !strings.Contains(delivery.Error, "not allowed") {
+		t.Errorf("unexpected delivery log: %+v", delivery)
+	}
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Errorf("receiver calls = %d, want 0", n)
+	}
+}
+
+func TestWebhookDispatcher_GivesUpOnPermanentFailure(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer receiver.Close()
+
+	repo := newMemoryWebhookRepository()
+	registerEndpoint(t, repo, "merchant-a", receiver.URL, EventPaymentCreated)
+
+	clock := newClock()
+	dispatcher := startDispatcher(t, repo, 5, clock)
+	dispatcher.Broadcast(1, payments.PaymentEvent{MerchantID: "merchant-a", PaymentID: 1, Status: payments.StatusCreated})
+
+	delivery := repo.waitDelivery(t)
+	if delivery.Success || delivery.NextRetryAt != nil || delivery.StatusCode != http.StatusBadRequest {
+		t.Errorf("unexpected delivery log: %+v", delivery)
+	}
+
+	clock.Advance(time.Hour)
+	dispatcher.retryDue(context.Background())
+	repo.assertNoDelivery(t)
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("receiver calls = %d, want 1", n)
+	}
+}
+
+func TestWebhookDispatcher_SkipsUnsubscribedEvents(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer receiver.Close()
+
+	repo := newMemoryWebhookRepository()
+	registerEndpoint(t, repo, "merchant-a", receiver.URL, EventPaymentSettled)
+
+	dispatcher := startDispatcher(t, repo, 1, newClock())
+	dispatcher.Broadcast(1, payments.PaymentEvent{MerchantID: "merchant-a", PaymentID: 1, Status: payments.StatusCreated})
+	dispatcher.Broadcast(1, payments.PaymentEvent{MerchantID: "merchant-a", PaymentID: 1, Status: payments.StatusSettled})
+
+	delivery := repo.waitDelivery(t)
+	if delivery.EventType != EventPaymentSettled {
+		t.Errorf("event type = %q, want %q", delivery.EventType, EventPaymentSettled)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("receiver calls = %d, want 1", n)
+	}
+}
+
+func TestWebhookDispatcher_Backoff(t *testing.T) {
+	d := NewWebhookDispatcher(nil, 10, time.Second, newClock())
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 20: maxBackoff} {
+		if got := d.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
+
+func TestWebhookDispatcher_OnlyNotifiesPaymentOwner(t *testing.T) {
+	var callsA, callsB int32
+	receiverA := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&callsA, 1)
+	}))
+	defer receiverA.Close()
+	receiverB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&callsB, 1)
+	}))
+	defer receiverB.Close()
+
+	repo := newMemoryWebhookRepository()
+	registerEndpoint(t, repo, "merchant-a", receiverA.URL, EventPaymentCreated)
+	registerEndpoint(t, repo, "merchant-b", receiverB.URL, EventPaymentCreated)
+
+	dispatcher := startDispatcher(t, repo, 1, newClock())
+	dispatcher.Broadcast(1, payments.PaymentEvent{MerchantID: "merchant-b", PaymentID: 1, Status: payments.StatusCreated})
+
+	delivery := repo.waitDelivery(t)
+	if delivery.EndpointID != 2 {
+		t.Errorf("delivered to endpoint %d, want 2", delivery.EndpointID)
+	}
+	if a, b := atomic.LoadInt32(&callsA), atomic.LoadInt32(&callsB); a != 0 || b != 1 {
+		t.Errorf("calls: merchant-a = %d, merchant-b = %d", a, b)
+	}
+}
@@ -0,0 +1,287 @@
+// Package ws é o transporte WebSocket do monitor de pagamentos, alternativa
+// ao SSE para clientes que não suportam text/event-stream. Uma conexão pode se
+// inscrever em vários pagamentos; os eventos vêm do mesmo sse.Broadcaster.
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fintech-shared/httpapi"
+	"fintech-shared/payments"
+	"fintech-shared/sse"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Tempo máximo para escrever um frame no cliente
+	writeWait = 10 * time.Second
+	// Tempo máximo sem receber pong antes de considerar a conexão morta
+	pongWait = 60 * time.Second
+	// Intervalo de ping (precisa ser menor que pongWait)
+	pingPeriod = (pongWait * 9) / 10
+	// Tamanho máximo das mensagens enviadas pelo cliente
+	maxMessageSize = 1024
+)
+
+// ClientMessage é a mensagem enviada pelo cliente para gerenciar inscrições
+type ClientMessage struct {
+	Action    string `json:"action"` // subscribe | unsubscribe | ping
+	PaymentID int64  `json:"payment_id"`
+}
+
+// Frame é o frame enviado ao cliente. Para eventos de pagamento, "data" tem
+// exatamente o mesmo formato do PaymentEvent enviado via SSE.
+type Frame struct {
+	Event     string                 `json:"event"` // initial | status_change | subscribed | unsubscribed | pong | error
+	PaymentID int64                  `json:"payment_id,omitempty"`
+	Data      *payments.PaymentEvent `json:"data,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// PaymentFinder busca o pagamento para conferir o lojista e enviar o status inicial
+type PaymentFinder interface {
+	FindByID(ctx context.Context, id int64) (*payments.PixPayment, error)
+}
+
+// Server aceita as conexões WebSocket do monitor
+type Server struct {
+	upgrader    websocket.Upgrader
+	broadcaster *sse.Broadcaster
+	payments    PaymentFinder
+}
+
+// NewServer cria o servidor; checkOrigin decide quais origens podem abrir a
+// conexão (a política de CORS do deployable)
+func NewServer(broadcaster *sse.Broadcaster, finder PaymentFinder, checkOrigin func(*http.Request) bool) *Server {
+	return &Server{
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     checkOrigin,
+		},
+		broadcaster: broadcaster,
+		payments:    finder,
+	}
+}
+
+// Serve faz o upgrade e atende a conexão até ela ser encerrada. A autenticação
+// fica com o handler: merchantID é o lojista autenticado, e só os pagamentos
+// dele podem ser acompanhados. Pagamentos iniciais vêm da query string
+// (payment_id=1&payment_id=2).
+func (s *Server) Serve(w http.ResponseWriter, r *http.Request, merchantID string) {
+	// Validar os filtros antes do upgrade para poder responder com HTTP 400
+	var initialIDs []int64
+	for _, raw := range r.URL.Query()["payment_id"] {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			httpapi.WriteProblem(w, r, http.StatusBadRequest, httpapi.CodeInvalidID, "invalid payment ID")
+			return
+		}
+		initialIDs = append(initialIDs, id)
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// O upgrader já respondeu ao cliente com o erro HTTP
+		slog.WarnContext(r.Context(), "failed to upgrade websocket connection", "error", err)
+		return
+	}
+
+	sess := &session{
+		ctx:         r.Context(),
+		conn:        conn,
+		payments:    s.payments,
+		merchantID:  merchantID,
+		broadcaster: s.broadcaster,
+		out:         make(chan Frame, 16),
+		done:        make(chan struct{}),
+		subs:        make(map[int64]chan payments.PaymentEvent),
+	}
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		sess.writeLoop()
+	}()
+
+	for _, id := range initialIDs {
+		sess.subscribe(id)
+	}
+
+	sess.readLoop()
+
+	// Conexão encerrada: liberar inscrições e aguardar o writer
+	close(sess.done)
+	for id := range sess.subs {
+		sess.unsubscribe(id, false)
+	}
+	<-writerDone
+	_ = conn.Close()
+	slog.InfoContext(r.Context(), "websocket connection closed", "remote_addr", r.RemoteAddr)
+}
+
+// session mantém o estado de uma conexão WebSocket com várias inscrições
+type session struct {
+	ctx         context.Context // Contexto do handshake (request_id nos logs)
+	conn        *websocket.Conn
+	payments    PaymentFinder
+	merchantID  string // Lojista autenticado no handshake
+	broadcaster *sse.Broadcaster
+	out         chan Frame
+	done        chan struct{}
+	subs        map[int64]chan payments.PaymentEvent // acessado apenas pelo loop de leitura
+}
+
+// readLoop processa as mensagens do cliente até a conexão ser encerrada
+func (s *session) readLoop() {
+	s.conn.SetReadLimit(maxMessageSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				slog.ErrorContext(s.ctx, "websocket read failed", "error", err)
+			}
+			return
+		}
+		// Qualquer mensagem do cliente também conta como sinal de vida
+		_ = s.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		var msg ClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			s.send(Frame{Event: "error", Error: "invalid json"})
+			continue
+		}
+
+		switch msg.Action {
+		case "subscribe":
+			s.subscribe(msg.PaymentID)
+		case "unsubscribe":
+			s.unsubscribe(msg.PaymentID, true)
+		case "ping":
+			s.send(Frame{Event: "pong"})
+		default:
+			s.send(Frame{Event: "error", PaymentID: msg.PaymentID, Error: "unknown action: " + msg.Action})
+		}
+	}
+}
+
+// writeLoop é o único goroutine que escreve na conexão (exigência do gorilla/websocket)
+func (s *session) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case frame := <-s.out:
+			_ = s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := s.conn.WriteJSON(frame); err != nil {
+				slog.ErrorContext(s.ctx, "failed to send websocket frame", "error", err)
+				// Fechar a conexão desbloqueia o loop de leitura
+				_ = s.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			_ = s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := s.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				_ = s.conn.Close()
+				return
+			}
+		case <-s.done:
+			_ = s.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(writeWait))
+			return
+		}
+	}
+}
+
+// send enfileira um frame para o writer, descartando-o se a conexão já foi encerrada
+func (s *session) send(frame Frame) bool {
+	select {
+	case s.out <- frame:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+func (s *session) subscribe(paymentID int64) {
+	if paymentID <= 0 {
+		s.send(Frame{Event: "error", Error: "payment ID is required"})
+		return
+	}
+	if _, ok := s.subs[paymentID]; ok {
+		s.send(Frame{Event: "subscribed", PaymentID: paymentID})
+		return
+	}
+
+	// Verificar se o pagamento existe e pertence ao lojista (mesmo comportamento do SSE)
+	payment, err := s.payments.FindByID(s.ctx, paymentID)
+	if err != nil && !errors.Is(err, payments.ErrNotFound) {
+		// Falha do repositório não é "não encontrado": o cliente pode tentar de novo
+		slog.ErrorContext(s.ctx, "failed to find payment for websocket subscription",
+			"payment_id", paymentID, "error", err)
+		s.send(Frame{Event: "error", PaymentID: paymentID, Error: "internal error"})
+		return
+	}
+	if err != nil || payment.MerchantID != s.merchantID {
+		s.send(Frame{Event: "error", PaymentID: paymentID, Error: "payment not found"})
+		return
+	}
+
+	eventChan := s.broadcaster.Subscribe(paymentID)
+	s.subs[paymentID] = eventChan
+
+	s.send(Frame{Event: "subscribed", PaymentID: paymentID})
+
+	// Enviar status inicial imediatamente
+	s.send(Frame{
+		Event:     "initial",
+		PaymentID: paymentID,
+		Data: &payments.PaymentEvent{
+			PaymentID:  payment.ID,
+			MerchantID: payment.MerchantID,
+			Status:     payment.Status,
+			Amount:     payment.Amount,
+			Timestamp:  time.Now(),
+			Message:    "Status inicial do pagamento",
+		},
+	})
+
+	// Encaminhar eventos em tempo real até o cancelamento da inscrição
+	go func() {
+		for event := range eventChan {
+			event := event
+			if !s.send(Frame{Event: "status_change", PaymentID: paymentID, Data: &event}) {
+				return
+			}
+		}
+	}()
+}
+
+func (s *session) unsubscribe(paymentID int64, notify bool) {
+	eventChan, ok := s.subs[paymentID]
+	if !ok {
+		if notify {
+			s.send(Frame{Event: "error", PaymentID: paymentID, Error: "not subscribed"})
+		}
+		return
+	}
+	delete(s.subs, paymentID)
+	s.broadcaster.Unsubscribe(paymentID, eventChan)
+
+	if notify {
+		s.send(Frame{Event: "unsubscribed", PaymentID: paymentID})
+	}
+}
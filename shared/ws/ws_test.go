@@ -0,0 +1,231 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"fintech-shared/payments"
+	"fintech-shared/payments/paymentstest"
+	"fintech-shared/sse"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// subscribers conta os clientes inscritos no broadcaster
+type subscribers struct {
+	payments.NopMetrics
+	n atomic.Int64
+}
+
+func (s *subscribers) SubscribersChanged(delta int) { s.n.Add(int64(delta)) }
+
+// waitFor espera o total de inscritos chegar a want (a liberação é assíncrona ao fechamento)
+func (s *subscribers) waitFor(t *testing.T, want int64) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for s.n.Load() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("subscribers = %d, want %d", s.n.Load(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+type fixture struct {
+	broadcaster *sse.Broadcaster
+	subscribers *subscribers
+	server      *httptest.Server
+	own, other  *payments.PixPayment
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := &fixture{broadcaster: sse.NewBroadcaster(), subscribers: &subscribers{}}
+	f.broadcaster.SetMetrics(f.subscribers)
+
+	repo := paymentstest.NewRepository(nil)
+	for _, merchantID := range []string{"loja-a", "loja-b"} {
+		payment, _ := payments.NewPixPayment(merchantID, "pagador-1", 150)
+		saved, err := repo.Save(context.Background(), payment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if merchantID == "loja-a" {
+			f.own = saved
+		} else {
+			f.other = saved
+		}
+	}
+
+	server := NewServer(f.broadcaster, repo, nil)
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.Serve(w, r, "loja-a")
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fixture) dial(t *testing.T, query string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(f.server.URL, "http")+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func read(t *testing.T, conn *websocket.Conn) Frame {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var frame Frame
+	if err := conn.ReadJSON(&frame); err != nil {
+		t.Fatal(err)
+	}
+	return frame
+}
+
+func expect(t *testing.T, conn *websocket.Conn, event string, paymentID int64) Frame {
+	t.Helper()
+	frame := read(t, conn)
+	if frame.Event != event || frame.PaymentID != paymentID {
+		t.Fatalf("frame = %+v, want %s for payment %d", frame, event, paymentID)
+	}
+	return frame
+}
+
+func TestServe_SubscribeAndBroadcast(t *testing.T) {
+	f := newFixture(t)
+	conn := f.dial(t, fmt.Sprintf("?payment_id=%d", f.own.ID))
+
+	expect(t, conn, "subscribed", f.own.ID)
+	initial := expect(t, conn, "initial", f.own.ID)
+	if initial.Data == nil || initial.Data.Status != payments.StatusCreated || initial.Data.MerchantID != "loja-a" {
+		t.Errorf("initial = %+v", initial.Data)
+	}
+	f.subscribers.waitFor(t, 1)
+
+	f.broadcaster.Broadcast(f.own.ID, payments.PaymentEvent{PaymentID: f.own.ID, Status: payments.StatusAuthorized})
+	if frame := expect(t, conn, "status_change", f.own.ID); frame.Data.Status != payments.StatusAuthorized {
+		t.Errorf("status_change = %+v", frame.Data)
+	}
+}
+
+func TestServe_Messages(t *testing.T) {
+	f := newFixture(t)
+	conn := f.dial(t, "")
+
+	tests := []struct {
+		name      string
+		message   string
+		event     string
+		paymentID int64
+	}{
+		{"ping", `{"action":"ping"}`, "pong", 0},
+		{"invalid json", `{"action":`, "error", 0},
+		{"unknown action", `{"action":"dance"}`, "error", 0},
+		{"missing payment id", `{"action":"subscribe"}`, "error", 0},
+		{"other merchant payment", fmt.Sprintf(`{"action":"subscribe","payment_id":%d}`, f.other.ID), "error", f.other.ID},
+		{"unsubscribe without subscription", fmt.Sprintf(`{"action":"unsubscribe","payment_id":%d}`, f.own.ID), "error", f.own.ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(tt.message)); err != nil {
+				t.Fatal(err)
+			}
+			expect(t, conn, tt.event, tt.paymentID)
+		})
+	}
+	if n := f.subscribers.n.Load(); n != 0 {
+		t.Errorf("subscribers = %d, want 0", n)
+	}
+}
+
+// brokenFinder falha como um banco fora do ar
+type brokenFinder struct{}
+
+func (brokenFinder) FindByID(ctx context.Context, id int64) (*payments.PixPayment, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestServe_SubscribeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		finder PaymentFinder
+		want   string
+	}{
+		{"missing payment", paymentstest.NewRepository(nil), "payment not found"},
+		{"repository failure", brokenFinder{}, "internal error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer(sse.NewBroadcaster(), tt.finder, nil)
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				server.Serve(w, r, "loja-a")
+			}))
+			t.Cleanup(httpServer.Close)
+			f := &fixture{server: httpServer}
+
+			conn := f.dial(t, "")
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"action":"subscribe","payment_id":42}`)); err != nil {
+				t.Fatal(err)
+			}
+			if frame := expect(t, conn, "error", 42); frame.Error != tt.want {
+				t.Errorf("error = %q, want %q", frame.Error, tt.want)
+			}
+		})
+	}
+}
+
+func TestServe_Unsubscribe(t *testing.T) {
+	f := newFixture(t)
+	conn := f.dial(t, "")
+
+	if err := conn.WriteJSON(ClientMessage{Action: "subscribe", PaymentID: f.own.ID}); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, conn, "subscribed", f.own.ID)
+	expect(t, conn, "initial", f.own.ID)
+
+	if err := conn.WriteJSON(ClientMessage{Action: "unsubscribe", PaymentID: f.own.ID}); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, conn, "unsubscribed", f.own.ID)
+	f.subscribers.waitFor(t, 0)
+
+	// Depois de desinscrito, só a resposta ao ping chega
+	f.broadcaster.Broadcast(f.own.ID, payments.PaymentEvent{PaymentID: f.own.ID, Status: payments.StatusAuthorized})
+	if err := conn.WriteJSON(ClientMessage{Action: "ping"}); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, conn, "pong", 0)
+}
+
+func TestServe_CloseReleasesSubscriptions(t *testing.T) {
+	f := newFixture(t)
+	conn := f.dial(t, fmt.Sprintf("?payment_id=%d", f.own.ID))
+	expect(t, conn, "subscribed", f.own.ID)
+	expect(t, conn, "initial", f.own.ID)
+	f.subscribers.waitFor(t, 1)
+
+	_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	conn.Close()
+	f.subscribers.waitFor(t, 0)
+}
+
+func TestServe_InvalidPaymentIDBeforeUpgrade(t *testing.T) {
+	f := newFixture(t)
+	resp, err := http.Get(f.server.URL + "?payment_id=abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+}
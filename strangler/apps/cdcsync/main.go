@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fintech-shared/logging"
+	"fintech-strangler/cdc"
+	"fintech-strangler/datamigration"
+	"fintech-strangler/infra/database"
+	"fintech-strangler/infra/metrics"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const usage = `usage: cdcsync <command> [flags]
+
+  setup [-reverse]     install change capture (triggers) and the offset/conflict tables
+  run [-reverse]       apply captured changes continuously (metrics on PORT)
+  teardown             remove the capture triggers (cdc_* tables are kept)`
+
+// cdcsync mantém os bancos dos microsserviços em dia com o banco do monólito
+// (e, com -reverse, o contrário) enquanto os dois recebem escritas durante a migração
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+	command := os.Args[1]
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	reverse := flags.Bool("reverse", false, "also sync service databases back into the monolith")
+	interval := flags.Duration("interval", time.Second, "poll interval when there are no pending changes")
+	batchSize := flags.Int("batch-size", 500, "changes read per poll")
+	keepSequences := flags.Bool("keep-monolith-sequences", true, "with -reverse, do not advance monolith sequences to IDs created by the services")
+	onConflict := flags.String("on-conflict", cdc.PolicySkip, "conflict policy: skip or overwrite")
+	_ = flags.Parse(os.Args[2:])
+
+	logging.Setup("cdcsync")
+
+	if *batchSize <= 0 {
+		log.Fatalf("invalid -batch-size: %d", *batchSize)
+	}
+	if *onConflict != cdc.PolicySkip && *onConflict != cdc.PolicyOverwrite {
+		log.Fatalf("invalid -on-conflict: %q", *onConflict)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dbs := connectAll(ctx)
+	defer func() {
+		for _, pool := range dbs {
+			pool.Close()
+		}
+	}()
+
+	var err error
+	switch command {
+	case "setup":
+		err = setup(ctx, dbs, *reverse)
+	case "teardown":
+		err = teardown(ctx, dbs)
+	case "run":
+		appMetrics := metrics.New()
+		serveMetrics(appMetrics)
+		run(ctx, streams(dbs, *reverse, *keepSequences, *onConflict, *batchSize, appMetrics), *interval)
+	default:
+		log.Fatal(usage)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// connectAll abre o banco do monólito ("monolith") e os dos serviços (pelo nome do destino)
+func connectAll(ctx context.Context) map[string]*pgxpool.Pool {
+	dbs := map[string]*pgxpool.Pool{}
+	for name, env := range map[string]string{
+		"monolith":                        database.SourceURLEnv,
+		datamigration.TargetPayments:      database.PaymentsURLEnv,
+		datamigration.TargetNotifications: database.NotificationsURLEnv,
+	} {
+		pool, err := database.Connect(ctx, env)
+		if err != nil {
+			log.Fatal(err)
+		}
+		dbs[name] = pool
+	}
+	return dbs
+}
+
+// byTarget agrupa as tabelas pelo banco do serviço
+func byTarget() map[string][]datamigration.Table {
+	groups := map[string][]datamigration.Table{}
+	for _, table := range datamigration.Tables {
+		groups[table.Target] = append(groups[table.Target], table)
+	}
+	return groups
+}
+
+// setup instala a captura na origem de cada stream e as tabelas de posição no destino.
+// Rode antes do datamigrate: mudanças feitas durante a cópia ficam capturadas.
+func setup(ctx context.Context, dbs map[string]*pgxpool.Pool, reverse bool) error {
+	if err := cdc.InstallCapture(ctx, dbs["monolith"], datamigration.Tables); err != nil {
+		return err
+	}
+	for target, tables := range byTarget() {
+		if err := cdc.InstallApply(ctx, dbs[target]); err != nil {
+			return err
+		}
+		if reverse {
+			if err := cdc.InstallCapture(ctx, dbs[target], tables); err != nil {
+				return err
+			}
+		}
+	}
+	if reverse {
+		if err := cdc.InstallApply(ctx, dbs["monolith"]); err != nil {
+			return err
+		}
+	}
+	fmt.Println("change capture installed")
+	return nil
+}
+
+func teardown(ctx context.Context, dbs map[string]*pgxpool.Pool) error {
+	if err := cdc.Uninstall(ctx, dbs["monolith"], datamigration.Tables); err != nil {
+		return err
+	}
+	for target, tables := range byTarget() {
+		if err := cdc.Uninstall(ctx, dbs[target], tables); err != nil {
+			return err
+		}
+	}
+	fmt.Println("change capture removed")
+	return nil
+}
+
+// streams monta uma stream por par de bancos: monólito -> serviço e, com reverse, serviço -> monólito
+func streams(dbs map[string]*pgxpool.Pool, reverse, keepMonolithSequences bool, policy string, batchSize int, m cdc.Metrics) []*cdc.Stream {
+	var result []*cdc.Stream
+	for target, tables := range byTarget() {
+		result = append(result, cdc.NewStream("monolith->"+target, dbs["monolith"], dbs[target], tables, policy, batchSize, m))
+		if reverse {
+			reverseStream := cdc.NewStream(target+"->monolith", dbs[target], dbs["monolith"], tables, policy, batchSize, m)
+			if keepMonolithSequences {
+				reverseStream.KeepSequences()
+			}
+			result = append(result, reverseStream)
+		}
+	}
+	return result
+}
+
+// run executa as streams em paralelo até SIGINT/SIGTERM
+func run(ctx context.Context, streams []*cdc.Stream, interval time.Duration) {
+	var wg sync.WaitGroup
+	for _, stream := range streams {
+		slog.Info("cdc stream started", "stream", stream.Name())
+		wg.Add(1)
+		go func(stream *cdc.Stream) {
+			defer wg.Done()
+			stream.Run(ctx, interval)
+		}(stream)
+	}
+	wg.Wait()
+	slog.Info("cdc sync stopped")
+}
+
+// serveMetrics expõe /metrics (lag e conflitos) e /livez na porta PORT
+func serveMetrics(m *metrics.Metrics) {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8091"
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"ok","service":"cdcsync"}`))
+	})
+	srv := &http.Server{Addr: ":" + port, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		slog.Info("cdc metrics listening", "port", port)
+		if err := srv.ListenAndServe(); err != nil {
+			log.Fatal(err)
+		}
+	}()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fintech-shared/logging"
+	"fintech-strangler/proxy"
+	"fintech-strangler/routing"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	// Logs estruturados em JSON (nível em LOG_LEVEL)
+	logging.Setup("strangler-gateway")
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8090"
+	}
+	adminPort := os.Getenv("ADMIN_PORT")
+	if adminPort == "" {
+		adminPort = "8092"
+	}
+
+	routesFile := os.Getenv("ROUTES_FILE")
+	if routesFile == "" {
+		log.Fatal("ROUTES_FILE is required")
+	}
+
+	// Rotas entre monólito e microsserviços, recarregadas quando o arquivo muda
+	watcher, router, err := routing.NewWatcher(routesFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	watcher.Start(context.Background(), envDuration("ROUTES_RELOAD_INTERVAL", 5*time.Second))
+
+	// SIGHUP força a releitura do arquivo
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if err := watcher.Reload(); err != nil {
+				slog.Error("routing config reload failed, keeping previous routes", "path", routesFile, "error", err)
+			}
+		}
+	}()
+
+	gateway := proxy.NewGateway(router, nil)
+
+	mux := http.NewServeMux()
+	// Probe do próprio gateway; /health, /livez e /readyz seguem para os backends
+	mux.HandleFunc("/_strangler/livez", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"ok","service":"strangler-gateway"}`))
+	})
+	mux.Handle("/", gateway)
+
+	// Porta de administração, fora da porta pública: o relatório do shadow traz
+	// respostas de todos os lojistas e o DELETE o zera
+	admin := http.NewServeMux()
+	// Divergências entre os backends nas leituras espelhadas (shadow nas rotas)
+	admin.Handle("/_strangler/shadow", gateway.ShadowReport())
+	adminSrv := &http.Server{
+		Addr:              ":" + adminPort,
+		Handler:           logging.RequestIDMiddleware(admin),
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      10 * time.Second,
+	}
+	go func() {
+		slog.Info("strangler admin listening", "port", adminPort)
+		log.Fatal(adminSrv.ListenAndServe())
+	}()
+
+	// Sem WriteTimeout: o SSE do monitor fica aberto enquanto o cliente quiser
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           logging.RequestIDMiddleware(mux),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	slog.Info("strangler gateway listening", "port", port, "routes_file", routesFile)
+	log.Fatal(srv.ListenAndServe())
+}
+
+// envDuration lê uma duração (ex.: "5s") da variável de ambiente, com valor padrão
+func envDuration(name string, def time.Duration) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		log.Fatalf("invalid %s: %q", name, raw)
+	}
+	return value
+}
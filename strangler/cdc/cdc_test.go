@@ -0,0 +1,56 @@
+package cdc
+
+import (
+	"fintech-strangler/datamigration"
+	"strings"
+	"testing"
+)
+
+func TestDecide(t *testing.T) {
+	tests := []struct {
+		name  string
+		op    string
+		state rowState
+		want  action
+	}{
+		{"insert new row", "INSERT", rowState{}, applyChange},
+		{"insert already copied", "INSERT", rowState{exists: true, matchesNew: true}, alreadyApplied},
+		{"insert id taken", "INSERT", rowState{exists: true}, conflictingChange},
+		{"update in sync", "UPDATE", rowState{exists: true, matchesOld: true}, applyChange},
+		{"update already applied", "UPDATE", rowState{exists: true, matchesNew: true}, alreadyApplied},
+		{"update changed in target", "UPDATE", rowState{exists: true}, conflictingChange},
+		{"update missing row", "UPDATE", rowState{}, conflictingChange},
+		{"delete in sync", "DELETE", rowState{exists: true, matchesOld: true}, applyChange},
+		{"delete already gone", "DELETE", rowState{}, alreadyApplied},
+		{"delete changed in target", "DELETE", rowState{exists: true}, conflictingChange},
+		{"unknown op", "TRUNCATE", rowState{}, conflictingChange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decide(tt.op, tt.state); got != tt.want {
+				t.Errorf("decide(%s, %+v) = %d, want %d", tt.op, tt.state, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQL(t *testing.T) {
+	table := datamigration.Table{Name: "notifications", Columns: []string{"id", "status", "message"}}
+
+	if got, want := upsertSQL(table), "INSERT INTO notifications (id, status, message) SELECT id, status, message FROM jsonb_populate_record(NULL::notifications, $1::jsonb) ON CONFLICT (id) DO UPDATE SET status = EXCLUDED.status, message = EXCLUDED.message"; got != want {
+		t.Errorf("upsertSQL = %q", got)
+	}
+	compare := compareSQL(table)
+	for _, want := range []string{
+		"(d.id, d.status, d.message) IS NOT DISTINCT FROM (o.id, o.status, o.message)",
+		"(d.id, d.status, d.message) IS NOT DISTINCT FROM (n.id, n.status, n.message)",
+		"FOR UPDATE OF d",
+	} {
+		if !strings.Contains(compare, want) {
+			t.Errorf("compareSQL is missing %q: %s", want, compare)
+		}
+	}
+	if got := syncSequenceSQL(table); !strings.Contains(got, "format('%I.%I', schemaname, sequencename)") {
+		t.Errorf("syncSequenceSQL = %q", got)
+	}
+}
@@ -0,0 +1,23 @@
+package cdc
+
+// Resultado de cada mudança recebida
+const (
+	OutcomeApplied  = "applied"  // Aplicada no destino
+	OutcomeSkipped  = "skipped"  // O destino já estava igual (ex.: linha copiada pelo datamigrate)
+	OutcomeConflict = "conflict" // O destino mudou por conta própria (registrada em cdc_conflicts)
+)
+
+// Metrics recebe os eventos da sincronização para instrumentação. A
+// implementação (Prometheus) fica na infra.
+type Metrics interface {
+	// ChangeProcessed conta as mudanças por stream, tabela, operação e resultado
+	ChangeProcessed(stream, table, op, outcome string)
+	// Lag informa as mudanças pendentes e a idade da mais antiga, em segundos
+	Lag(stream string, pending int64, seconds float64)
+}
+
+// NopMetrics descarta as métricas (uso sem instrumentação)
+type NopMetrics struct{}
+
+func (NopMetrics) ChangeProcessed(string, string, string, string) {}
+func (NopMetrics) Lag(string, int64, float64)                     {}
@@ -0,0 +1,107 @@
+package cdc
+
+import (
+	"context"
+	"fintech-strangler/datamigration"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// originSetting marca a sessão do sync: o que ele aplica não é capturado de
+// novo (sem isso, a sincronização reversa devolveria cada mudança à origem)
+const originSetting = "cdc.origin"
+
+// captureSQL cria a tabela de mudanças e a função do trigger. xid é a
+// transação que fez a mudança: o sync só lê mudanças de transações já
+// encerradas (ver Stream.readChanges).
+const captureSQL = `
+CREATE TABLE IF NOT EXISTS cdc_changes (
+  id BIGSERIAL PRIMARY KEY,
+  xid BIGINT NOT NULL DEFAULT pg_current_xact_id()::text::bigint,
+  table_name TEXT NOT NULL,
+  op TEXT NOT NULL, -- INSERT, UPDATE ou DELETE
+  row_id BIGINT NOT NULL,
+  old_data JSONB, -- Linha antes da mudança (UPDATE/DELETE)
+  new_data JSONB, -- Linha depois da mudança (INSERT/UPDATE)
+  changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cdc_changes_position ON cdc_changes (table_name, xid, id);
+
+CREATE OR REPLACE FUNCTION cdc_capture() RETURNS trigger AS $$
+BEGIN
+  IF current_setting('` + originSetting + `', true) = 'sync' THEN
+    RETURN NULL;
+  END IF;
+  IF TG_OP = 'INSERT' THEN
+    INSERT INTO cdc_changes (table_name, op, row_id, new_data) VALUES (TG_TABLE_NAME, TG_OP, NEW.id, to_jsonb(NEW));
+  ELSIF TG_OP = 'UPDATE' THEN
+    INSERT INTO cdc_changes (table_name, op, row_id, old_data, new_data) VALUES (TG_TABLE_NAME, TG_OP, NEW.id, to_jsonb(OLD), to_jsonb(NEW));
+  ELSE
+    INSERT INTO cdc_changes (table_name, op, row_id, old_data) VALUES (TG_TABLE_NAME, TG_OP, OLD.id, to_jsonb(OLD));
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+`
+
+// applySQL cria as tabelas do lado que recebe as mudanças: posição de cada
+// stream e registro dos conflitos
+const applySQL = `
+CREATE TABLE IF NOT EXISTS cdc_offsets (
+  stream TEXT PRIMARY KEY,
+  last_xid BIGINT NOT NULL,
+  last_id BIGINT NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS cdc_conflicts (
+  id BIGSERIAL PRIMARY KEY,
+  stream TEXT NOT NULL,
+  change_id BIGINT NOT NULL,
+  table_name TEXT NOT NULL,
+  row_id BIGINT NOT NULL,
+  op TEXT NOT NULL,
+  source_data JSONB, -- Mudança recebida (depois; antes, no DELETE)
+  target_data JSONB, -- Linha no destino quando o conflito foi detectado
+  resolution TEXT NOT NULL, -- skipped ou overwritten
+  detected_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+`
+
+// InstallCapture cria a captura de mudanças (tabela e triggers) nas tabelas da origem
+func InstallCapture(ctx context.Context, pool *pgxpool.Pool, tables []datamigration.Table) error {
+	if _, err := pool.Exec(ctx, captureSQL); err != nil {
+		return fmt.Errorf("cdc: install capture: %w", err)
+	}
+	for _, table := range tables {
+		sql := fmt.Sprintf(`DROP TRIGGER IF EXISTS cdc_capture ON %[1]s;
+CREATE TRIGGER cdc_capture AFTER INSERT OR UPDATE OR DELETE ON %[1]s FOR EACH ROW EXECUTE FUNCTION cdc_capture();`, table.Name)
+		if _, err := pool.Exec(ctx, sql); err != nil {
+			return fmt.Errorf("cdc: install trigger on %s: %w", table.Name, err)
+		}
+	}
+	return nil
+}
+
+// InstallApply cria as tabelas de posição e de conflitos no destino
+func InstallApply(ctx context.Context, pool *pgxpool.Pool) error {
+	if _, err := pool.Exec(ctx, applySQL); err != nil {
+		return fmt.Errorf("cdc: install apply tables: %w", err)
+	}
+	return nil
+}
+
+// Uninstall remove os triggers e a função; as tabelas cdc_* ficam para consulta
+func Uninstall(ctx context.Context, pool *pgxpool.Pool, tables []datamigration.Table) error {
+	for _, table := range tables {
+		if _, err := pool.Exec(ctx, fmt.Sprintf("DROP TRIGGER IF EXISTS cdc_capture ON %s", table.Name)); err != nil {
+			return fmt.Errorf("cdc: drop trigger on %s: %w", table.Name, err)
+		}
+	}
+	if _, err := pool.Exec(ctx, "DROP FUNCTION IF EXISTS cdc_capture()"); err != nil {
+		return fmt.Errorf("cdc: drop capture function: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,385 @@
+package cdc
+
+import (
+	"context"
+	"errors"
+	"fintech-strangler/datamigration"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Políticas de conflito: o destino mudou por conta própria desde a mudança recebida
+const (
+	// PolicySkip registra o conflito e mantém o destino como está
+	PolicySkip = "skip"
+	// PolicyOverwrite registra o conflito e aplica a mudança da origem
+	PolicyOverwrite = "overwrite"
+)
+
+// Change é uma mudança capturada pelo trigger na origem
+type Change struct {
+	ID        int64
+	XID       int64
+	Table     string
+	Op        string
+	RowID     int64
+	Old       *string // JSON da linha antes (UPDATE/DELETE)
+	New       *string // JSON da linha depois (INSERT/UPDATE)
+	ChangedAt time.Time
+}
+
+// Stream aplica as mudanças de um banco (origem) em outro (destino), na
+// ordem das transações, guardando a posição no destino junto com cada mudança
+type Stream struct {
+	name      string
+	source    *pgxpool.Pool
+	target    *pgxpool.Pool
+	tables    map[string]datamigration.Table
+	names     []string
+	policy    string
+	batchSize int
+	metrics   Metrics
+	// Sem isso, a sequência do destino avança até os IDs recebidos (ver write)
+	keepSequences bool
+}
+
+func NewStream(name string, source, target *pgxpool.Pool, tables []datamigration.Table, policy string, batchSize int, metrics Metrics) *Stream {
+	s := &Stream{
+		name:      name,
+		source:    source,
+		target:    target,
+		tables:    make(map[string]datamigration.Table, len(tables)),
+		policy:    policy,
+		batchSize: batchSize,
+		metrics:   metrics,
+	}
+	for _, table := range tables {
+		s.tables[table.Name] = table
+		s.names = append(s.names, table.Name)
+	}
+	return s
+}
+
+func (s *Stream) Name() string {
+	return s.name
+}
+
+// KeepSequences desliga o avanço da sequência do destino. Para o sentido
+// serviço -> monólito quando os serviços geram IDs numa faixa separada: sem
+// isso, a sequência do monólito pularia para a faixa dos serviços.
+func (s *Stream) KeepSequences() *Stream {
+	s.keepSequences = true
+	return s
+}
+
+// Run sincroniza continuamente até o ctx ser cancelado. Um erro (ex.: FK de
+// uma linha que ainda não chegou) é registrado e a mesma mudança é tentada de
+// novo no próximo ciclo: a stream para, mas não pula mudanças.
+func (s *Stream) Run(ctx context.Context, interval time.Duration) {
+	for {
+		applied, err := s.SyncOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "cdc sync failed", "stream", s.name, "error", err)
+		}
+		// Lote cheio: ainda há mudanças, segue sem esperar
+		if err == nil && applied == s.batchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// SyncOnce aplica um lote de mudanças e retorna quantas foram processadas
+func (s *Stream) SyncOnce(ctx context.Context) (int, error) {
+	lastXID, lastID, err := s.offset(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { s.reportLag(ctx, lastXID, lastID) }()
+
+	changes, err := s.readChanges(ctx, lastXID, lastID)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, change := range changes {
+		if err := s.apply(ctx, change); err != nil {
+			return processed, fmt.Errorf("change %d (%s %s id=%d): %w", change.ID, change.Op, change.Table, change.RowID, err)
+		}
+		lastXID, lastID = change.XID, change.ID
+		processed++
+	}
+
+	// Mudanças aplicadas saem da origem (cada stream lê só as suas tabelas)
+	if processed > 0 {
+		if _, err := s.source.Exec(ctx,
+			"DELETE FROM cdc_changes WHERE table_name = ANY($1) AND (xid, id) <= ($2, $3)", s.names, lastXID, lastID,
+		); err != nil {
+			slog.WarnContext(ctx, "failed to prune applied changes", "stream", s.name, "error", err)
+		}
+	}
+	return processed, nil
+}
+
+// offset lê a posição da stream no destino (0, 0 se ainda não começou)
+func (s *Stream) offset(ctx context.Context) (lastXID, lastID int64, err error) {
+	err = s.target.QueryRow(ctx, "SELECT last_xid, last_id FROM cdc_offsets WHERE stream = $1", s.name).Scan(&lastXID, &lastID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, 0, nil
+	}
+	return lastXID, lastID, err
+}
+
+// readChanges lê as próximas mudanças em ordem de (xid, id), só de transações
+// anteriores ao xmin do snapshot atual. Ler por id apenas perderia mudanças: uma
+// transação lenta pode gravar um id menor depois de outra, mais rápida, já lida.
+// Abaixo do xmin todas as transações já terminaram e nada mais entra atrás da posição.
+func (s *Stream) readChanges(ctx context.Context, lastXID, lastID int64) ([]Change, error) {
+	rows, err := s.source.Query(ctx, `
+		SELECT id, xid, table_name, op, row_id, old_data::text, new_data::text, changed_at
+		FROM cdc_changes
+		WHERE table_name = ANY($1) AND (xid, id) > ($2, $3)
+		  AND xid < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+		ORDER BY xid, id
+		LIMIT $4`,
+		s.names, lastXID, lastID, s.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Change, error) {
+		var c Change
+		err := row.Scan(&c.ID, &c.XID, &c.Table, &c.Op, &c.RowID, &c.Old, &c.New, &c.ChangedAt)
+		return c, err
+	})
+}
+
+// apply aplica uma mudança no destino, na mesma transação que avança a posição
+func (s *Stream) apply(ctx context.Context, change Change) error {
+	table, ok := s.tables[change.Table]
+	if !ok {
+		return fmt.Errorf("cdc: unknown table %q", change.Table)
+	}
+
+	tx, err := s.target.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // Sem efeito após o Commit
+
+	// O trigger do destino (sincronização reversa) ignora o que o sync grava
+	if _, err := tx.Exec(ctx, "SELECT set_config($1, 'sync', true)", originSetting); err != nil {
+		return err
+	}
+
+	state, err := compareRow(ctx, tx, table, change.RowID, change.Old, change.New)
+	if err != nil {
+		return err
+	}
+
+	outcome := OutcomeSkipped
+	switch decide(change.Op, state) {
+	case applyChange:
+		if err := write(ctx, tx, table, change, !s.keepSequences); err != nil {
+			return err
+		}
+		outcome = OutcomeApplied
+	case conflictingChange:
+		// Pode ser só atraso: o destino já está como a origem está agora (ex.:
+		// linha copiada pelo datamigrate depois desta mudança)
+		inSync, err := s.matchesSource(ctx, tx, table, change.RowID, state.exists)
+		if err != nil {
+			return err
+		}
+		if !inSync {
+			if err := s.conflict(ctx, tx, table, change); err != nil {
+				return err
+			}
+			outcome = OutcomeConflict
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `INSERT INTO cdc_offsets (stream, last_xid, last_id) VALUES ($1, $2, $3)
+		ON CONFLICT (stream) DO UPDATE SET last_xid = EXCLUDED.last_xid, last_id = EXCLUDED.last_id, updated_at = now()`,
+		s.name, change.XID, change.ID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	s.metrics.ChangeProcessed(s.name, change.Table, change.Op, outcome)
+	return nil
+}
+
+// matchesSource compara o destino com a linha atual da origem
+func (s *Stream) matchesSource(ctx context.Context, tx pgx.Tx, table datamigration.Table, rowID int64, exists bool) (bool, error) {
+	var current *string
+	err := s.source.QueryRow(ctx, rowJSONSQL(table), rowID).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return !exists, nil // Apagada dos dois lados
+	}
+	if err != nil || !exists {
+		return false, err
+	}
+	state, err := compareRow(ctx, tx, table, rowID, current, nil)
+	return state.matchesOld, err
+}
+
+// conflict registra o conflito e, com PolicyOverwrite, aplica a mudança mesmo assim
+func (s *Stream) conflict(ctx context.Context, tx pgx.Tx, table datamigration.Table, change Change) error {
+	var targetData *string
+	if err := tx.QueryRow(ctx, rowJSONSQL(table), change.RowID).Scan(&targetData); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	sourceData := change.New
+	if change.Op == "DELETE" {
+		sourceData = change.Old
+	}
+
+	resolution := "skipped"
+	if s.policy == PolicyOverwrite {
+		if err := write(ctx, tx, table, change, !s.keepSequences); err != nil {
+			return err
+		}
+		resolution = "overwritten"
+	}
+	slog.WarnContext(ctx, "cdc conflict", "stream", s.name, "table", change.Table, "row_id", change.RowID, "op", change.Op, "resolution", resolution)
+
+	_, err := tx.Exec(ctx, `INSERT INTO cdc_conflicts (stream, change_id, table_name, row_id, op, source_data, target_data, resolution)
+		VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7::jsonb, $8)`,
+		s.name, change.ID, change.Table, change.RowID, change.Op, sourceData, targetData, resolution)
+	return err
+}
+
+// reportLag mede as mudanças pendentes e a idade da mais antiga (relógio da origem)
+func (s *Stream) reportLag(ctx context.Context, lastXID, lastID int64) {
+	var pending int64
+	var seconds float64
+	err := s.source.QueryRow(ctx, `
+		SELECT count(*), COALESCE(EXTRACT(EPOCH FROM now() - min(changed_at)), 0)::float8
+		FROM cdc_changes WHERE table_name = ANY($1) AND (xid, id) > ($2, $3)`,
+		s.names, lastXID, lastID).Scan(&pending, &seconds)
+	if err != nil {
+		return
+	}
+	s.metrics.Lag(s.name, pending, seconds)
+}
+
+// rowState é a linha do destino comparada com as imagens da mudança
+type rowState struct {
+	exists     bool
+	matchesOld bool
+	matchesNew bool
+}
+
+type action int
+
+const (
+	applyChange action = iota
+	alreadyApplied
+	conflictingChange
+)
+
+// decide o que fazer com a mudança conforme o estado do destino: aplica se o
+// destino está como a origem estava antes; ignora se já está como depois;
+// qualquer outro estado é conflito
+func decide(op string, state rowState) action {
+	switch op {
+	case "INSERT":
+		if !state.exists {
+			return applyChange
+		}
+		if state.matchesNew {
+			return alreadyApplied
+		}
+	case "UPDATE":
+		if state.exists && state.matchesNew {
+			return alreadyApplied
+		}
+		if state.exists && state.matchesOld {
+			return applyChange
+		}
+	case "DELETE":
+		if !state.exists {
+			return alreadyApplied
+		}
+		if state.matchesOld {
+			return applyChange
+		}
+	}
+	return conflictingChange
+}
+
+// compareRow trava a linha do destino e compara com as imagens (JSON) recebidas.
+// A comparação é feita nos tipos da tabela, não no texto: o JSON do trigger
+// traz datas no fuso da sessão que fez a mudança.
+func compareRow(ctx context.Context, tx pgx.Tx, table datamigration.Table, rowID int64, oldImage, newImage *string) (rowState, error) {
+	state := rowState{exists: true}
+	err := tx.QueryRow(ctx, compareSQL(table), rowID, oldImage, newImage).Scan(&state.matchesOld, &state.matchesNew)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return rowState{}, nil
+	}
+	return state, err
+}
+
+// write grava a mudança no destino: upsert da linha nova ou remoção
+func write(ctx context.Context, tx pgx.Tx, table datamigration.Table, change Change, syncSequence bool) error {
+	if change.Op == "DELETE" {
+		_, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1", table.Name), change.RowID)
+		return err
+	}
+	if _, err := tx.Exec(ctx, upsertSQL(table), change.New); err != nil {
+		return err
+	}
+	if !syncSequence {
+		return nil
+	}
+	// ID vindo da outra base: a sequência local não pode gerá-lo de novo
+	_, err := tx.Exec(ctx, syncSequenceSQL(table), change.RowID)
+	return err
+}
+
+func compareSQL(table datamigration.Table) string {
+	return fmt.Sprintf(`SELECT %[2]s IS NOT DISTINCT FROM %[3]s, %[2]s IS NOT DISTINCT FROM %[4]s
+		FROM %[1]s d, jsonb_populate_record(NULL::%[1]s, $2::jsonb) o, jsonb_populate_record(NULL::%[1]s, $3::jsonb) n
+		WHERE d.id = $1 FOR UPDATE OF d`,
+		table.Name, rowOf("d", table.Columns), rowOf("o", table.Columns), rowOf("n", table.Columns))
+}
+
+func upsertSQL(table datamigration.Table) string {
+	columns := strings.Join(table.Columns, ", ")
+	var set []string
+	for _, column := range table.Columns[1:] {
+		set = append(set, column+" = EXCLUDED."+column)
+	}
+	return fmt.Sprintf("INSERT INTO %[1]s (%[2]s) SELECT %[2]s FROM jsonb_populate_record(NULL::%[1]s, $1::jsonb) ON CONFLICT (id) DO UPDATE SET %[3]s",
+		table.Name, columns, strings.Join(set, ", "))
+}
+
+func rowJSONSQL(table datamigration.Table) string {
+	return fmt.Sprintf("SELECT to_jsonb(r)::text FROM (SELECT %s FROM %s WHERE id = $1) r", strings.Join(table.Columns, ", "), table.Name)
+}
+
+// syncSequenceSQL avança a sequência do id até $1, se ela estiver atrás
+func syncSequenceSQL(table datamigration.Table) string {
+	return fmt.Sprintf(`SELECT setval(seq::regclass, $1) FROM (SELECT pg_get_serial_sequence('%s', 'id') AS seq) s
+		WHERE $1 > (SELECT COALESCE(last_value, 0) FROM pg_sequences WHERE format('%%I.%%I', schemaname, sequencename) = s.seq)`,
+		table.Name)
+}
+
+func rowOf(alias string, columns []string) string {
+	qualified := make([]string, len(columns))
+	for i, column := range columns {
+		qualified[i] = alias + "." + column
+	}
+	return "(" + strings.Join(qualified, ", ") + ")"
+}
@@ -0,0 +1,351 @@
+package cdc
+
+import (
+	"context"
+	"fintech-shared/pgtest"
+	"fintech-strangler/datamigration"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pgtest.Run(m))
+}
+
+// recordingMetrics guarda os resultados das mudanças processadas
+type recordingMetrics struct {
+	NopMetrics
+	outcomes []string
+}
+
+func (m *recordingMetrics) ChangeProcessed(stream, table, op, outcome string) {
+	m.outcomes = append(m.outcomes, op+" "+outcome)
+}
+
+var pixPayments = []datamigration.Table{datamigration.Tables[0]}
+
+// capturedChange é uma linha de cdc_changes, com o status das imagens
+type capturedChange struct {
+	op        string
+	rowID     int64
+	oldStatus *string
+	newStatus *string
+}
+
+func TestCapture(t *testing.T) {
+	ctx := context.Background()
+	source := pgtest.NewPool(t, "init-monolith.sql")
+	if err := InstallCapture(ctx, source, pixPayments); err != nil {
+		t.Fatal(err)
+	}
+	// Reinstalar não duplica os triggers
+	if err := InstallCapture(ctx, source, pixPayments); err != nil {
+		t.Fatal(err)
+	}
+
+	id := insertPayment(t, source, "PENDING")
+	exec(t, source, "UPDATE pix_payments SET status = 'CONFIRMED' WHERE id = $1", id)
+	exec(t, source, "DELETE FROM pix_payments WHERE id = $1", id)
+
+	// O que o sync grava (cdc.origin = sync) não é capturado
+	tx, err := source.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(ctx, "SELECT set_config($1, 'sync', true)", originSetting); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO pix_payments (amount, status) VALUES (1, 'PENDING')"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, confirmed := "PENDING", "CONFIRMED"
+	want := []capturedChange{
+		{op: "INSERT", rowID: id, newStatus: &pending},
+		{op: "UPDATE", rowID: id, oldStatus: &pending, newStatus: &confirmed},
+		{op: "DELETE", rowID: id, oldStatus: &confirmed},
+	}
+	got := capturedChanges(t, source)
+	if len(got) != len(want) {
+		t.Fatalf("captured %d changes, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].op != want[i].op || got[i].rowID != want[i].rowID ||
+			!sameStatus(got[i].oldStatus, want[i].oldStatus) || !sameStatus(got[i].newStatus, want[i].newStatus) {
+			t.Errorf("change %d = %s row %d (%v -> %v), want %s row %d", i, got[i].op, got[i].rowID,
+				deref(got[i].oldStatus), deref(got[i].newStatus), want[i].op, want[i].rowID)
+		}
+	}
+
+	// Sem os triggers, nada mais é capturado
+	if err := Uninstall(ctx, source, pixPayments); err != nil {
+		t.Fatal(err)
+	}
+	insertPayment(t, source, "PENDING")
+	if got := capturedChanges(t, source); len(got) != len(want) {
+		t.Errorf("captured %d changes after Uninstall, want %d", len(got), len(want))
+	}
+}
+
+func TestStream_SyncOnce(t *testing.T) {
+	ctx := context.Background()
+	source, target := newStreamPools(t)
+	// Captura também no destino (sincronização reversa): o que o sync aplica não volta
+	if err := InstallCapture(ctx, target, pixPayments); err != nil {
+		t.Fatal(err)
+	}
+	metrics := &recordingMetrics{}
+	stream := NewStream("monolith-to-payments", source, target, pixPayments, PolicySkip, 2, metrics)
+
+	first := insertPayment(t, source, "PENDING")
+	second := insertPayment(t, source, "PENDING")
+	exec(t, source, "UPDATE pix_payments SET status = 'CONFIRMED', risk_reasons = ARRAY['velocity'] WHERE id = $1", first)
+	exec(t, source, "DELETE FROM pix_payments WHERE id = $1", second)
+
+	syncAll(t, stream, 4)
+	assertOutcomes(t, metrics, "INSERT applied", "INSERT applied", "UPDATE applied", "DELETE applied")
+	assertSameRow(t, source, target, first)
+	if rowExists(t, target, second) {
+		t.Errorf("payment %d should have been deleted in target", second)
+	}
+
+	// Posição guardada no destino e mudanças aplicadas removidas da origem
+	var lastID int64
+	if err := target.QueryRow(ctx, "SELECT last_id FROM cdc_offsets WHERE stream = $1", stream.Name()).Scan(&lastID); err != nil {
+		t.Fatal(err)
+	}
+	if lastID == 0 {
+		t.Error("offset not saved")
+	}
+	if got := capturedChanges(t, source); len(got) != 0 {
+		t.Errorf("%d applied changes left in source", len(got))
+	}
+	if got := capturedChanges(t, target); len(got) != 0 {
+		t.Errorf("sync writes captured in target: %d changes", len(got))
+	}
+
+	// A sequência do destino avançou até os IDs recebidos
+	if id := insertPayment(t, target, "PENDING"); id <= second {
+		t.Errorf("target generated id %d, already used by source (%d)", id, second)
+	}
+
+	// Nada novo: nenhum processamento
+	if n, err := stream.SyncOnce(ctx); err != nil || n != 0 {
+		t.Errorf("SyncOnce = %d, %v; want 0, nil", n, err)
+	}
+}
+
+// Linha copiada pelo datamigrate depois da mudança: o destino já está igual
+func TestStream_AlreadyCopied(t *testing.T) {
+	ctx := context.Background()
+	source, target := newStreamPools(t)
+	metrics := &recordingMetrics{}
+	stream := NewStream("monolith-to-payments", source, target, pixPayments, PolicySkip, 10, metrics)
+
+	id := insertPayment(t, source, "PENDING")
+	exec(t, source, "UPDATE pix_payments SET status = 'CONFIRMED' WHERE id = $1", id)
+	copier := datamigration.NewCopier(source, map[string]*pgxpool.Pool{datamigration.TargetPayments: target}, 10)
+	if _, err := copier.Copy(ctx, pixPayments[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	syncAll(t, stream, 2)
+	// O INSERT encontra a linha já atualizada, mas igual à origem: não é conflito
+	assertOutcomes(t, metrics, "INSERT skipped", "UPDATE skipped")
+	assertSameRow(t, source, target, id)
+	if n := conflicts(t, target); n != 0 {
+		t.Errorf("%d conflicts recorded, want 0", n)
+	}
+}
+
+func TestStream_Conflict(t *testing.T) {
+	for _, tc := range []struct {
+		policy     string
+		resolution string
+		wantStatus string
+	}{
+		{policy: PolicySkip, resolution: "skipped", wantStatus: "TARGET"},
+		{policy: PolicyOverwrite, resolution: "overwritten", wantStatus: "SOURCE"},
+	} {
+		t.Run(tc.policy, func(t *testing.T) {
+			ctx := context.Background()
+			source, target := newStreamPools(t)
+			metrics := &recordingMetrics{}
+			stream := NewStream("monolith-to-payments", source, target, pixPayments, tc.policy, 10, metrics)
+
+			id := insertPayment(t, source, "PENDING")
+			syncAll(t, stream, 1)
+
+			// Os dois lados mudam a mesma linha
+			exec(t, target, "UPDATE pix_payments SET status = 'TARGET' WHERE id = $1", id)
+			exec(t, source, "UPDATE pix_payments SET status = 'SOURCE' WHERE id = $1", id)
+			syncAll(t, stream, 1)
+
+			assertOutcomes(t, metrics, "INSERT applied", "UPDATE conflict")
+			var status string
+			if err := target.QueryRow(ctx, "SELECT status FROM pix_payments WHERE id = $1", id).Scan(&status); err != nil {
+				t.Fatal(err)
+			}
+			if status != tc.wantStatus {
+				t.Errorf("target status = %s, want %s", status, tc.wantStatus)
+			}
+
+			var rowID int64
+			var op, resolution, sourceStatus, targetStatus string
+			err := target.QueryRow(ctx, `SELECT row_id, op, resolution, source_data->>'status', target_data->>'status'
+				FROM cdc_conflicts WHERE stream = $1`, stream.Name()).Scan(&rowID, &op, &resolution, &sourceStatus, &targetStatus)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rowID != id || op != "UPDATE" || resolution != tc.resolution || sourceStatus != "SOURCE" || targetStatus != "TARGET" {
+				t.Errorf("conflict = row %d %s %s (source %s, target %s)", rowID, op, resolution, sourceStatus, targetStatus)
+			}
+		})
+	}
+}
+
+// newStreamPools cria a origem (monólito, com captura) e o destino (payments-service)
+func newStreamPools(t *testing.T) (source, target *pgxpool.Pool) {
+	t.Helper()
+	ctx := context.Background()
+	source = pgtest.NewPool(t, "init-monolith.sql")
+	target = pgtest.NewPool(t, "init-payments.sql")
+	if err := InstallCapture(ctx, source, pixPayments); err != nil {
+		t.Fatal(err)
+	}
+	if err := InstallApply(ctx, target); err != nil {
+		t.Fatal(err)
+	}
+	return source, target
+}
+
+// syncAll processa want mudanças. O sync só lê transações abaixo do xmin do
+// servidor, que outros testes em paralelo podem segurar por um instante.
+func syncAll(t *testing.T, stream *Stream, want int) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	processed := 0
+	for processed < want {
+		n, err := stream.SyncOnce(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		processed += n
+		if n == 0 {
+			if time.Now().After(deadline) {
+				t.Fatalf("processed %d changes, want %d", processed, want)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	if processed != want {
+		t.Fatalf("processed %d changes, want %d", processed, want)
+	}
+}
+
+func assertOutcomes(t *testing.T, metrics *recordingMetrics, want ...string) {
+	t.Helper()
+	if len(metrics.outcomes) != len(want) {
+		t.Fatalf("outcomes = %v, want %v", metrics.outcomes, want)
+	}
+	for i := range want {
+		if metrics.outcomes[i] != want[i] {
+			t.Errorf("outcomes = %v, want %v", metrics.outcomes, want)
+			return
+		}
+	}
+}
+
+// assertSameRow compara a linha nos dois bancos, nos tipos da tabela
+func assertSameRow(t *testing.T, source, target *pgxpool.Pool, id int64) {
+	t.Helper()
+	var image string
+	if err := source.QueryRow(context.Background(), rowJSONSQL(pixPayments[0]), id).Scan(&image); err != nil {
+		t.Fatal(err)
+	}
+	var same bool
+	err := target.QueryRow(context.Background(), compareSQL(pixPayments[0]), id, image, nil).Scan(&same, new(bool))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !same {
+		t.Errorf("payment %d differs between source and target", id)
+	}
+}
+
+func insertPayment(t *testing.T, pool *pgxpool.Pool, status string) int64 {
+	t.Helper()
+	var id int64
+	err := pool.QueryRow(context.Background(),
+		"INSERT INTO pix_payments (merchant_id, payer_id, amount, status) VALUES ('merchant-1', 'payer-1', 10.50, $1) RETURNING id",
+		status).Scan(&id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func exec(t *testing.T, pool *pgxpool.Pool, sql string, args ...any) {
+	t.Helper()
+	if _, err := pool.Exec(context.Background(), sql, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func rowExists(t *testing.T, pool *pgxpool.Pool, id int64) bool {
+	t.Helper()
+	var exists bool
+	if err := pool.QueryRow(context.Background(), "SELECT EXISTS (SELECT 1 FROM pix_payments WHERE id = $1)", id).Scan(&exists); err != nil {
+		t.Fatal(err)
+	}
+	return exists
+}
+
+func conflicts(t *testing.T, pool *pgxpool.Pool) int {
+	t.Helper()
+	var n int
+	if err := pool.QueryRow(context.Background(), "SELECT count(*) FROM cdc_conflicts").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// capturedChanges lê cdc_changes na ordem da captura
+func capturedChanges(t *testing.T, pool *pgxpool.Pool) []capturedChange {
+	t.Helper()
+	rows, err := pool.Query(context.Background(), `SELECT op, row_id, old_data->>'status', new_data->>'status'
+		FROM cdc_changes ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var changes []capturedChange
+	for rows.Next() {
+		var c capturedChange
+		if err := rows.Scan(&c.op, &c.rowID, &c.oldStatus, &c.newStatus); err != nil {
+			t.Fatal(err)
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return changes
+}
+
+func sameStatus(a, b *string) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
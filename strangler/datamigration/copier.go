@@ -0,0 +1,252 @@
+package datamigration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrConflict indica IDs que já existem no destino com outro conteúdo
+var ErrConflict = errors.New("rows already exist in target with different content")
+
+// Copier copia as tabelas do banco do monólito para os bancos dos serviços,
+// em lotes, com checkpoint no destino para retomar de onde parou
+type Copier struct {
+	source    *pgxpool.Pool
+	targets   map[string]*pgxpool.Pool
+	batchSize int
+}
+
+func NewCopier(source *pgxpool.Pool, targets map[string]*pgxpool.Pool, batchSize int) *Copier {
+	return &Copier{source: source, targets: targets, batchSize: batchSize}
+}
+
+// Plan é o que a cópia de uma tabela faria (dry-run)
+type Plan struct {
+	Table      string
+	Target     string
+	SourceRows int64 // Linhas no monólito
+	ResumeFrom int64 // Último ID já copiado (checkpoint)
+	Pending    int64 // Linhas a copiar a partir do checkpoint
+	TargetRows int64 // Linhas já no destino
+	TargetMax  int64 // Maior ID no destino
+	// IDs do monólito a copiar podem colidir com os já criados no destino
+	MayConflict bool
+}
+
+// Result é o resultado da verificação de uma tabela
+type Result struct {
+	Table          string
+	UpToID         int64 // Verificado até o maior ID do monólito
+	SourceRows     int64
+	TargetRows     int64
+	SourceChecksum string
+	TargetChecksum string
+}
+
+func (r Result) OK() bool {
+	return r.SourceRows == r.TargetRows && r.SourceChecksum == r.TargetChecksum
+}
+
+// Plan calcula o que seria copiado, sem escrever nada
+func (c *Copier) Plan(ctx context.Context, table Table) (Plan, error) {
+	target, err := c.target(table)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan := Plan{Table: table.Name, Target: table.Target}
+
+	plan.ResumeFrom, _, err = c.checkpoint(ctx, target, table)
+	if err != nil {
+		return plan, err
+	}
+	var minPending *int64
+	err = c.source.QueryRow(ctx,
+		fmt.Sprintf("SELECT count(*), count(*) FILTER (WHERE id > $1), min(id) FILTER (WHERE id > $1) FROM %s", table.Name),
+		plan.ResumeFrom,
+	).Scan(&plan.SourceRows, &plan.Pending, &minPending)
+	if err != nil {
+		return plan, fmt.Errorf("source %s: %w", table.Name, err)
+	}
+	err = target.QueryRow(ctx, fmt.Sprintf("SELECT count(*), COALESCE(max(id), 0) FROM %s", table.Name)).
+		Scan(&plan.TargetRows, &plan.TargetMax)
+	if err != nil {
+		return plan, fmt.Errorf("target %s: %w", table.Name, err)
+	}
+	plan.MayConflict = minPending != nil && *minPending <= plan.TargetMax
+	return plan, nil
+}
+
+// Copy copia as linhas após o checkpoint, um lote por transação (lote e
+// checkpoint juntos), e retorna quantas linhas foram inseridas
+func (c *Copier) Copy(ctx context.Context, table Table) (int64, error) {
+	target, err := c.target(table)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := target.Exec(ctx, `CREATE TABLE IF NOT EXISTS data_migration_checkpoints (
+		table_name TEXT PRIMARY KEY,
+		last_id BIGINT NOT NULL,
+		rows_copied BIGINT NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return 0, err
+	}
+
+	lastID, total, err := c.checkpoint(ctx, target, table)
+	if err != nil {
+		return 0, err
+	}
+	if lastID > 0 {
+		slog.InfoContext(ctx, "resuming table copy", "table", table.Name, "last_id", lastID, "rows_copied", total)
+	}
+
+	var inserted int64
+	for {
+		ids, batch, err := c.readBatch(ctx, table, lastID)
+		if err != nil {
+			return inserted, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		n, err := c.writeBatch(ctx, target, table, ids, batch, total)
+		if err != nil {
+			return inserted, err
+		}
+		lastID = ids[len(ids)-1]
+		total += n
+		inserted += n
+		slog.InfoContext(ctx, "batch copied", "table", table.Name, "last_id", lastID, "inserted", n, "rows_copied", total)
+
+		if len(ids) < c.batchSize {
+			break
+		}
+	}
+
+	if _, err := target.Exec(ctx, table.syncSequenceSQL()); err != nil {
+		return inserted, fmt.Errorf("target %s: sync id sequence: %w", table.Name, err)
+	}
+	return inserted, nil
+}
+
+// Verify compara quantidade e checksum das linhas até o maior ID do monólito
+func (c *Copier) Verify(ctx context.Context, table Table) (Result, error) {
+	target, err := c.target(table)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Table: table.Name}
+	if err := c.source.QueryRow(ctx, fmt.Sprintf("SELECT COALESCE(max(id), 0) FROM %s", table.Name)).Scan(&result.UpToID); err != nil {
+		return result, fmt.Errorf("source %s: %w", table.Name, err)
+	}
+
+	if err := c.source.QueryRow(ctx, table.checksumSQL(), result.UpToID).Scan(&result.SourceRows, &result.SourceChecksum); err != nil {
+		return result, fmt.Errorf("source %s: %w", table.Name, err)
+	}
+	if err := target.QueryRow(ctx, table.checksumSQL(), result.UpToID).Scan(&result.TargetRows, &result.TargetChecksum); err != nil {
+		return result, fmt.Errorf("target %s: %w", table.Name, err)
+	}
+	return result, nil
+}
+
+func (c *Copier) target(table Table) (*pgxpool.Pool, error) {
+	target, ok := c.targets[table.Target]
+	if !ok {
+		return nil, fmt.Errorf("datamigration: no %s database configured for %s", table.Target, table.Name)
+	}
+	return target, nil
+}
+
+// checkpoint lê o último ID copiado da tabela (0 se a cópia não começou)
+func (c *Copier) checkpoint(ctx context.Context, target *pgxpool.Pool, table Table) (lastID, rowsCopied int64, err error) {
+	var exists bool
+	if err := target.QueryRow(ctx, "SELECT to_regclass('data_migration_checkpoints') IS NOT NULL").Scan(&exists); err != nil {
+		return 0, 0, err
+	}
+	if !exists {
+		return 0, 0, nil
+	}
+	err = target.QueryRow(ctx,
+		"SELECT last_id, rows_copied FROM data_migration_checkpoints WHERE table_name = $1", table.Name,
+	).Scan(&lastID, &rowsCopied)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, 0, nil
+	}
+	return lastID, rowsCopied, err
+}
+
+// readBatch lê do monólito o próximo lote após lastID, como array JSON
+func (c *Copier) readBatch(ctx context.Context, table Table, lastID int64) ([]int64, string, error) {
+	rows, err := c.source.Query(ctx, table.selectBatchSQL(), lastID, c.batchSize)
+	if err != nil {
+		return nil, "", fmt.Errorf("source %s: %w", table.Name, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	var batch strings.Builder
+	batch.WriteByte('[')
+	for rows.Next() {
+		var id int64
+		var row string
+		if err := rows.Scan(&id, &row); err != nil {
+			return nil, "", err
+		}
+		if len(ids) > 0 {
+			batch.WriteByte(',')
+		}
+		batch.WriteString(row)
+		ids = append(ids, id)
+	}
+	batch.WriteByte(']')
+	return ids, batch.String(), rows.Err()
+}
+
+// writeBatch grava o lote e o checkpoint na mesma transação
+func (c *Copier) writeBatch(ctx context.Context, target *pgxpool.Pool, table Table, ids []int64, batch string, total int64) (int64, error) {
+	tx, err := target.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // Sem efeito após o Commit
+
+	tag, err := tx.Exec(ctx, table.insertBatchSQL(), batch)
+	if err != nil {
+		return 0, fmt.Errorf("target %s: %w", table.Name, err)
+	}
+	inserted := tag.RowsAffected()
+
+	// Menos inserções que linhas: alguns IDs já existiam. Só aceita se forem iguais.
+	if inserted < int64(len(ids)) {
+		conflicts, err := c.conflicts(ctx, tx, table, batch)
+		if err != nil {
+			return 0, err
+		}
+		if len(conflicts) > 0 {
+			return 0, fmt.Errorf("%s: %w: ids %v", table.Name, ErrConflict, conflicts)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO data_migration_checkpoints (table_name, last_id, rows_copied) VALUES ($1, $2, $3)
+		ON CONFLICT (table_name) DO UPDATE SET last_id = EXCLUDED.last_id, rows_copied = EXCLUDED.rows_copied, updated_at = now()`,
+		table.Name, ids[len(ids)-1], total+inserted)
+	if err != nil {
+		return 0, err
+	}
+	return inserted, tx.Commit(ctx)
+}
+
+func (c *Copier) conflicts(ctx context.Context, tx pgx.Tx, table Table, batch string) ([]int64, error) {
+	rows, err := tx.Query(ctx, table.conflictsSQL(), batch)
+	if err != nil {
+		return nil, fmt.Errorf("target %s: %w", table.Name, err)
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int64])
+}
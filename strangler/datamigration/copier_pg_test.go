@@ -0,0 +1,165 @@
+package datamigration
+
+import (
+	"context"
+	"errors"
+	"fintech-shared/pgtest"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pgtest.Run(m))
+}
+
+func TestCopier(t *testing.T) {
+	ctx := context.Background()
+	source, targets := newCopierPools(t)
+	payment := insertPayment(t, source)
+	insertPayment(t, source)
+	insertPayment(t, source)
+	exec(t, source, `INSERT INTO notifications (payment_id, type, recipient, message, status) VALUES ($1, 'email', 'a@b.c', 'ok', 'SENT')`, payment)
+	var endpoint int64
+	if err := source.QueryRow(ctx, `INSERT INTO webhook_endpoints (merchant_id, url, secret, event_types)
+		VALUES ('merchant-1', 'https://example.com/hook', 's3cr3t', ARRAY['payment.confirmed']) RETURNING id`).Scan(&endpoint); err != nil {
+		t.Fatal(err)
+	}
+	exec(t, source, `INSERT INTO webhook_deliveries (endpoint_id, payment_id, event_type, attempt, status_code, success, next_retry_at)
+		VALUES ($1, $2, 'payment.confirmed', 1, 500, false, now())`, endpoint, payment)
+
+	// Lote menor que a tabela: a cópia passa por mais de um checkpoint
+	copier := NewCopier(source, targets, 2)
+	plan, err := copier.Plan(ctx, Tables[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.SourceRows != 3 || plan.Pending != 3 || plan.ResumeFrom != 0 || plan.TargetRows != 0 || plan.MayConflict {
+		t.Errorf("plan before copy = %+v", plan)
+	}
+
+	want := map[string]int64{"pix_payments": 3, "notifications": 1, "webhook_endpoints": 1, "webhook_deliveries": 1}
+	for _, table := range Tables {
+		inserted, err := copier.Copy(ctx, table)
+		if err != nil {
+			t.Fatalf("Copy(%s): %v", table.Name, err)
+		}
+		if inserted != want[table.Name] {
+			t.Errorf("Copy(%s) = %d rows, want %d", table.Name, inserted, want[table.Name])
+		}
+		assertVerified(t, copier, table)
+	}
+
+	plan, err = copier.Plan(ctx, Tables[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Pending != 0 || plan.ResumeFrom != plan.TargetMax || plan.TargetRows != 3 {
+		t.Errorf("plan after copy = %+v", plan)
+	}
+
+	// A sequência do destino continua depois dos IDs copiados
+	var next int64
+	if err := targets[TargetPayments].QueryRow(ctx, "SELECT nextval(pg_get_serial_sequence('pix_payments', 'id'))").Scan(&next); err != nil {
+		t.Fatal(err)
+	}
+	if next <= plan.TargetMax {
+		t.Errorf("target sequence at %d, already copied (max %d)", next, plan.TargetMax)
+	}
+
+	// Retomada: só o que entrou depois do checkpoint é copiado
+	latest := insertPayment(t, source)
+	inserted, err := copier.Copy(ctx, Tables[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inserted != 1 {
+		t.Errorf("resumed Copy = %d rows, want 1", inserted)
+	}
+	var lastID int64
+	if err := targets[TargetPayments].QueryRow(ctx,
+		"SELECT last_id FROM data_migration_checkpoints WHERE table_name = 'pix_payments'").Scan(&lastID); err != nil {
+		t.Fatal(err)
+	}
+	if lastID != latest {
+		t.Errorf("checkpoint = %d, want %d", lastID, latest)
+	}
+	assertVerified(t, copier, Tables[0])
+}
+
+func TestCopier_Conflict(t *testing.T) {
+	ctx := context.Background()
+	source, targets := newCopierPools(t)
+	insertPayment(t, source)
+	target := targets[TargetPayments]
+	copier := NewCopier(source, targets, 10)
+
+	// Linha idêntica já no destino (cópia anterior sem checkpoint): aceita
+	if _, err := copier.Copy(ctx, Tables[0]); err != nil {
+		t.Fatal(err)
+	}
+	exec(t, target, "DELETE FROM data_migration_checkpoints")
+	if inserted, err := copier.Copy(ctx, Tables[0]); err != nil || inserted != 0 {
+		t.Fatalf("Copy over identical rows = %d, %v; want 0, nil", inserted, err)
+	}
+
+	// Mesmo ID com outro conteúdo (criado pelo serviço): conflito, nada é gravado
+	exec(t, target, "DELETE FROM data_migration_checkpoints")
+	exec(t, target, "UPDATE pix_payments SET status = 'CONFIRMED'")
+	plan, err := copier.Plan(ctx, Tables[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.MayConflict {
+		t.Errorf("plan = %+v, want MayConflict", plan)
+	}
+	if _, err := copier.Copy(ctx, Tables[0]); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Copy = %v, want ErrConflict", err)
+	}
+	var checkpoints int
+	if err := target.QueryRow(ctx, "SELECT count(*) FROM data_migration_checkpoints").Scan(&checkpoints); err != nil {
+		t.Fatal(err)
+	}
+	if checkpoints != 0 {
+		t.Error("checkpoint saved for a conflicting batch")
+	}
+}
+
+// newCopierPools cria o banco do monólito e os dos serviços, pelos schemas de db/
+func newCopierPools(t *testing.T) (*pgxpool.Pool, map[string]*pgxpool.Pool) {
+	t.Helper()
+	return pgtest.NewPool(t, "init-monolith.sql"), map[string]*pgxpool.Pool{
+		TargetPayments:      pgtest.NewPool(t, "init-payments.sql"),
+		TargetNotifications: pgtest.NewPool(t, "init-notifications.sql"),
+	}
+}
+
+func assertVerified(t *testing.T, copier *Copier, table Table) {
+	t.Helper()
+	result, err := copier.Verify(context.Background(), table)
+	if err != nil {
+		t.Fatalf("Verify(%s): %v", table.Name, err)
+	}
+	if !result.OK() {
+		t.Errorf("Verify(%s) = %+v", table.Name, result)
+	}
+}
+
+func insertPayment(t *testing.T, pool *pgxpool.Pool) int64 {
+	t.Helper()
+	var id int64
+	err := pool.QueryRow(context.Background(), `INSERT INTO pix_payments (merchant_id, payer_id, amount, status, risk_reasons)
+		VALUES ('merchant-1', 'payer-1', 10.50, 'PENDING', ARRAY['velocity']) RETURNING id`).Scan(&id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func exec(t *testing.T, pool *pgxpool.Pool, sql string, args ...any) {
+	t.Helper()
+	if _, err := pool.Exec(context.Background(), sql, args...); err != nil {
+		t.Fatal(err)
+	}
+}
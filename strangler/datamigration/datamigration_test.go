@@ -0,0 +1,127 @@
+package datamigration
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	all, err := Select(nil)
+	if err != nil || len(all) != len(Tables) {
+		t.Fatalf("Select(nil) = %d tables, %v", len(all), err)
+	}
+
+	// Mantém a ordem da cópia, não a ordem pedida
+	selected, err := Select([]string{"webhook_deliveries", " webhook_endpoints"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 2 || selected[0].Name != "webhook_endpoints" || selected[1].Name != "webhook_deliveries" {
+		t.Errorf("selected = %v", selected)
+	}
+
+	if _, err := Select([]string{"payments"}); err == nil {
+		t.Error("expected error for unknown table")
+	}
+}
+
+// As colunas copiadas precisam existir no schema do monólito (origem) e no do
+// serviço de destino, conforme as migrações de cada um
+func TestTables_MatchSchemas(t *testing.T) {
+	source := readSchema(t, "../../monolith/infra/database/migrations/sql")
+	targets := map[string]string{
+		TargetPayments:      readSchema(t, "../../microservices/payments-service/infra/migrations/sql"),
+		TargetNotifications: readSchema(t, "../../microservices/notifications-service/infra/migrations/sql"),
+	}
+
+	for _, table := range Tables {
+		if table.Columns[0] != "id" {
+			t.Errorf("%s: first column must be id", table.Name)
+		}
+		for name, schema := range map[string]string{"monolith": source, table.Target: targets[table.Target]} {
+			columns := tableColumns(schema, table.Name)
+			if columns == nil {
+				t.Errorf("%s: table not found in %s schema", table.Name, name)
+				continue
+			}
+			for _, column := range table.Columns {
+				if !columns[column] {
+					t.Errorf("%s.%s: column not found in %s schema", table.Name, column, name)
+				}
+			}
+		}
+	}
+}
+
+func TestTable_SQL(t *testing.T) {
+	table := Table{Name: "notifications", Columns: []string{"id", "status"}}
+
+	if got, want := table.insertBatchSQL(), "INSERT INTO notifications (id, status) SELECT id, status FROM json_populate_recordset(NULL::notifications, $1::json) ON CONFLICT (id) DO NOTHING"; got != want {
+		t.Errorf("insertBatchSQL = %q", got)
+	}
+	if got := table.conflictsSQL(); !strings.Contains(got, "WHERE (s.id, s.status) IS DISTINCT FROM (d.id, d.status)") {
+		t.Errorf("conflictsSQL = %q", got)
+	}
+}
+
+func TestResult_OK(t *testing.T) {
+	ok := Result{SourceRows: 2, TargetRows: 2, SourceChecksum: "abc", TargetChecksum: "abc"}
+	if !ok.OK() {
+		t.Error("equal counts and checksums should be OK")
+	}
+	changed := ok
+	changed.TargetChecksum = "def"
+	if changed.OK() {
+		t.Error("different checksum should not be OK")
+	}
+}
+
+func readSchema(t *testing.T, dir string) string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no migrations in %s: %v", dir, err)
+	}
+	var schema strings.Builder
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		schema.Write(data)
+	}
+	return schema.String()
+}
+
+var (
+	columnLine = regexp.MustCompile(`^\s+([a-z_]+)\s+[A-Z]`)
+	addColumn  = regexp.MustCompile(`ADD COLUMN IF NOT EXISTS ([a-z_]+)`)
+)
+
+// tableColumns extrai as colunas do CREATE TABLE da tabela e as acrescentadas
+// pelos ALTER TABLE das migrações seguintes (nil se a tabela não existir)
+func tableColumns(schema, table string) map[string]bool {
+	_, body, found := strings.Cut(schema, "CREATE TABLE IF NOT EXISTS "+table+" (")
+	if !found {
+		return nil
+	}
+	body, _, _ = strings.Cut(body, "\n);")
+	columns := map[string]bool{}
+	for _, line := range strings.Split(body, "\n") {
+		if m := columnLine.FindStringSubmatch(line); m != nil {
+			columns[m[1]] = true
+		}
+	}
+
+	alters := regexp.MustCompile(`ALTER TABLE `+table+`\s`).Split(schema, -1)
+	for _, alter := range alters[1:] {
+		alter, _, _ = strings.Cut(alter, ";")
+		for _, m := range addColumn.FindAllStringSubmatch(alter, -1) {
+			columns[m[1]] = true
+		}
+	}
+	return columns
+}
@@ -0,0 +1,109 @@
+package datamigration
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bancos de destino, um por serviço
+const (
+	TargetPayments      = "payments"
+	TargetNotifications = "notifications"
+)
+
+// Table é uma tabela copiada do banco compartilhado do monólito para o banco
+// de um serviço, preservando os IDs
+type Table struct {
+	Name    string
+	Target  string
+	Columns []string // Colunas copiadas; a chave é sempre "id"
+}
+
+// Tables na ordem da cópia: webhook_deliveries referencia webhook_endpoints.
+// Ficam de fora payload e retried_at de webhook_deliveries: sem o corpo, a
+// linha copiada não entra na fila de retentativas do destino, e a retentativa
+// continua com o deployable que registrou a tentativa.
+var Tables = []Table{
+	{Name: "pix_payments", Target: TargetPayments, Columns: []string{
+		"id", "merchant_id", "payer_id", "amount", "status", "risk_decision", "risk_reasons", "review_deadline",
+		"review_decision", "reviewed_by", "review_notes", "reviewed_at", "created_at",
+	}},
+	{Name: "notifications", Target: TargetNotifications, Columns: []string{
+		"id", "payment_id", "type", "recipient", "message", "status", "created_at",
+	}},
+	{Name: "webhook_endpoints", Target: TargetPayments, Columns: []string{
+		"id", "merchant_id", "url", "secret", "event_types", "created_at",
+	}},
+	{Name: "webhook_deliveries", Target: TargetPayments, Columns: []string{
+		"id", "endpoint_id", "payment_id", "event_type", "attempt", "status_code", "success", "error",
+		"next_retry_at", "created_at",
+	}},
+}
+
+// Select retorna as tabelas pelo nome, na ordem da cópia (vazio = todas)
+func Select(names []string) ([]Table, error) {
+	if len(names) == 0 {
+		return Tables, nil
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[strings.TrimSpace(name)] = true
+	}
+	var selected []Table
+	for _, table := range Tables {
+		if wanted[table.Name] {
+			selected = append(selected, table)
+			delete(wanted, table.Name)
+		}
+	}
+	for name := range wanted {
+		return nil, fmt.Errorf("datamigration: unknown table %q", name)
+	}
+	return selected, nil
+}
+
+// selectBatchSQL lê o próximo lote após o checkpoint, cada linha já em JSON:
+// tipos (NUMERIC, TEXT[], TIMESTAMPTZ) chegam ao destino sem conversão no Go
+func (t Table) selectBatchSQL() string {
+	return fmt.Sprintf(
+		"SELECT r.id, row_to_json(r)::text FROM (SELECT %s FROM %s WHERE id > $1 ORDER BY id LIMIT $2) r ORDER BY r.id",
+		strings.Join(t.Columns, ", "), t.Name)
+}
+
+// insertBatchSQL grava o lote ($1: array JSON). Linhas já copiadas (retomada
+// após falha) são ignoradas; o conflictsSQL confere se são idênticas.
+func (t Table) insertBatchSQL() string {
+	columns := strings.Join(t.Columns, ", ")
+	return fmt.Sprintf(
+		"INSERT INTO %s (%s) SELECT %s FROM json_populate_recordset(NULL::%s, $1::json) ON CONFLICT (id) DO NOTHING",
+		t.Name, columns, columns, t.Name)
+}
+
+// conflictsSQL lista os IDs do lote que já existem no destino com outro conteúdo
+// (ex.: criados pelo microsserviço com o mesmo ID)
+func (t Table) conflictsSQL() string {
+	src := make([]string, len(t.Columns))
+	dst := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		src[i] = "s." + column
+		dst[i] = "d." + column
+	}
+	return fmt.Sprintf(
+		"SELECT s.id FROM json_populate_recordset(NULL::%s, $1::json) s JOIN %s d ON d.id = s.id WHERE (%s) IS DISTINCT FROM (%s) ORDER BY s.id",
+		t.Name, t.Name, strings.Join(src, ", "), strings.Join(dst, ", "))
+}
+
+// checksumSQL calcula quantidade e checksum das linhas até o ID $1, na ordem dos IDs
+func (t Table) checksumSQL() string {
+	return fmt.Sprintf(
+		"SELECT count(*), COALESCE(md5(string_agg(md5(row_to_json(r)::text), '' ORDER BY r.id)), '') FROM (SELECT %s FROM %s WHERE id <= $1) r",
+		strings.Join(t.Columns, ", "), t.Name)
+}
+
+// syncSequenceSQL avança a sequência do ID para depois do maior ID copiado:
+// o serviço não pode gerar um ID que já veio do monólito
+func (t Table) syncSequenceSQL() string {
+	return fmt.Sprintf(
+		"SELECT setval(pg_get_serial_sequence('%s', 'id'), max(id)) FROM %s HAVING max(id) IS NOT NULL",
+		t.Name, t.Name)
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Variáveis de ambiente com a conexão de cada banco
+const (
+	SourceURLEnv        = "SOURCE_DATABASE_URL" // Banco compartilhado do monólito
+	PaymentsURLEnv      = "PAYMENTS_DATABASE_URL"
+	NotificationsURLEnv = "NOTIFICATIONS_DATABASE_URL"
+)
+
+// Connect abre o pool do banco cuja URL está em envVar. Sessões em UTC: o
+// checksum do datamigrate compara o texto das linhas, e TIMESTAMPTZ é
+// formatado no fuso da sessão.
+func Connect(ctx context.Context, envVar string) (*pgxpool.Pool, error) {
+	dsn := os.Getenv(envVar)
+	if dsn == "" {
+		return nil, fmt.Errorf("%s is required", envVar)
+	}
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", envVar, err)
+	}
+	config.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", envVar, err)
+	}
+	return pool, nil
+}
+
+// TargetURLEnv retorna a variável com a URL do banco de destino ("payments" ou "notifications")
+func TargetURLEnv(target string) string {
+	switch target {
+	case "payments":
+		return PaymentsURLEnv
+	case "notifications":
+		return NotificationsURLEnv
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"fintech-strangler/cdc"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New()
+
+	var cm cdc.Metrics = m
+	cm.ChangeProcessed("monolith->payments", "pix_payments", "UPDATE", cdc.OutcomeConflict)
+	cm.Lag("monolith->payments", 3, 1.5)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		`cdc_changes_total{op="UPDATE",outcome="conflict",stream="monolith->payments",table="pix_payments"} 1`,
+		`cdc_pending_changes{stream="monolith->payments"} 3`,
+		`cdc_lag_seconds{stream="monolith->payments"} 1.5`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("/metrics is missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package proxy
+
+import (
+	"fintech-shared/logging"
+	"fintech-strangler/routing"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// BackendHeader informa na resposta qual backend atendeu a requisição
+const BackendHeader = "X-Strangler-Backend"
+
+// keyBackend é a chave de log com o backend escolhido
+const keyBackend = "backend"
+
+// Gateway encaminha cada requisição ao backend escolhido pelo Router
+// (monólito ou microsserviço), reescrevendo o caminho quando a rota pede
+type Gateway struct {
+	router    *routing.Router
+	transport http.RoundTripper
+	shadow    *shadower
+
+	mu      sync.Mutex
+	proxies map[string]*httputil.ReverseProxy // Por URL do backend
+}
+
+func NewGateway(router *routing.Router, transport http.RoundTripper) *Gateway {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Gateway{router: router, transport: transport, shadow: newShadower(transport), proxies: make(map[string]*httputil.ReverseProxy)}
+}
+
+// ShadowReport é o relatório das leituras espelhadas (rotas com shadow)
+func (g *Gateway) ShadowReport() http.Handler {
+	return g.shadow.report
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	decision, ok := g.router.Decide(r)
+	if !ok {
+		slog.WarnContext(ctx, "no route for request", "method", r.Method, "path", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	slog.InfoContext(ctx, "route decision",
+		"route", decision.Route,
+		keyBackend, decision.Backend,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"upstream_path", decision.Path,
+	)
+
+	out := r.Clone(ctx)
+	out.URL.Path = decision.Path
+	out.URL.RawPath = ""
+	// Mesmo request_id no gateway e no backend
+	out.Header.Set(logging.RequestIDHeader, logging.RequestID(ctx))
+
+	w.Header().Set(BackendHeader, decision.Backend)
+	// WebSocket e SSE não são espelhados: a conexão não tem uma resposta para comparar
+	if decision.Shadow == nil || r.Header.Get("Upgrade") != "" || acceptsEventStream(r) {
+		g.proxyFor(decision.Target).ServeHTTP(w, out)
+		return
+	}
+	// A cópia só sai depois da resposta principal, que segue sem alteração
+	capture := &responseCapture{ResponseWriter: w}
+	g.proxyFor(decision.Target).ServeHTTP(capture, out)
+	g.shadow.mirror(out, decision, capture)
+}
+
+// acceptsEventStream indica um cliente SSE (EventSource manda Accept: text/event-stream)
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), eventStream)
+}
+
+// proxyFor reaproveita o proxy do backend; uma URL nova (após reload) ganha o seu
+func (g *Gateway) proxyFor(target *url.URL) *httputil.ReverseProxy {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := target.String()
+	if p, ok := g.proxies[key]; ok {
+		return p
+	}
+	p := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.SetXForwarded()
+		},
+		Transport: g.transport,
+		// Repassa cada escrita na hora: SSE do monitor de pagamentos
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			slog.ErrorContext(r.Context(), "backend request failed", "backend_url", key, "path", r.URL.Path, "error", err)
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		},
+	}
+	g.proxies[key] = p
+	return p
+}
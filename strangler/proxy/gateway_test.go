@@ -0,0 +1,263 @@
+package proxy
+
+import (
+	"fintech-shared/logging"
+	"fintech-strangler/routing"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// backend responde com o próprio nome, o caminho e o request_id recebidos
+func backend(name string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, name+" "+r.URL.RequestURI()+" "+r.Header.Get(logging.RequestIDHeader))
+	}))
+}
+
+func newGateway(t *testing.T, monolithURL, serviceURL string) http.Handler {
+	t.Helper()
+	table, err := routing.Compile(routing.Config{
+		Backends: map[string]routing.BackendConfig{
+			"monolith":         {URL: monolithURL},
+			"payments-service": {URL: serviceURL},
+		},
+		Routes: []routing.RouteConfig{
+			{Name: "pix-legacy", Prefix: "/payments/pix", Targets: []routing.TargetConfig{
+				{Backend: "payments-service", Rewrite: "/pix"},
+			}},
+			{Name: "webhooks", Prefix: "/webhooks", Targets: []routing.TargetConfig{{Backend: "monolith"}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return logging.RequestIDMiddleware(NewGateway(routing.NewRouter(table), nil))
+}
+
+func TestGateway(t *testing.T) {
+	monolith, service := backend("monolith"), backend("payments-service")
+	defer monolith.Close()
+	defer service.Close()
+	gateway := newGateway(t, monolith.URL, service.URL)
+
+	tests := []struct {
+		path, want, backend string
+	}{
+		{"/payments/pix/42?x=1", "payments-service /pix/42?x=1 req-1", "payments-service"},
+		{"/payments/pix", "payments-service /pix req-1", "payments-service"},
+		{"/webhooks/3/deliveries", "monolith /webhooks/3/deliveries req-1", "monolith"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set(logging.RequestIDHeader, "req-1")
+		rec := httptest.NewRecorder()
+		gateway.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK || rec.Body.String() != tt.want {
+			t.Errorf("%s: got %d %q, want %q", tt.path, rec.Code, rec.Body.String(), tt.want)
+		}
+		if got := rec.Header().Get(BackendHeader); got != tt.backend {
+			t.Errorf("%s: %s = %q, want %q", tt.path, BackendHeader, got, tt.backend)
+		}
+	}
+}
+
+func TestGateway_NoRouteAndBackendDown(t *testing.T) {
+	monolith := backend("monolith")
+	defer monolith.Close()
+	service := backend("payments-service")
+	service.Close() // Backend fora do ar
+	gateway := newGateway(t, monolith.URL, service.URL)
+
+	rec := httptest.NewRecorder()
+	gateway.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/reviews", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("no route: status = %d, want 404", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	gateway.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/payments/pix/1", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("backend down: status = %d, want 502", rec.Code)
+	}
+}
+
+// jsonBackend responde sempre o mesmo JSON
+func jsonBackend(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	}))
+}
+
+func newShadowGateway(t *testing.T, monolithURL, serviceURL string) *Gateway {
+	t.Helper()
+	table, err := routing.Compile(routing.Config{
+		Backends: map[string]routing.BackendConfig{
+			"monolith":         {URL: monolithURL},
+			"payments-service": {URL: serviceURL},
+		},
+		Routes: []routing.RouteConfig{
+			{Name: "pix-payments", Prefix: "/payments/pix", Targets: []routing.TargetConfig{
+				{Backend: "monolith", Weight: 100},
+				{Backend: "payments-service", Weight: 0, Rewrite: "/pix"},
+			}, Shadow: routing.ShadowConfig{Percent: 100, Ignore: []string{"review.deadline"}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewGateway(routing.NewRouter(table), nil)
+}
+
+// waitReport espera a cópia (assíncrona) ser comparada
+func waitReport(t *testing.T, g *Gateway, done func(ShadowStats) bool) shadowReportResponse {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		report := g.shadow.report.snapshot()
+		if done(report.Routes["pix-payments"]) {
+			return report
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("shadow report = %+v", report)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGateway_Shadow(t *testing.T) {
+	monolith := jsonBackend(`{"id":1,"status":"COMPLETED","amount":10,"created_at":"2024-05-01T12:00:00-03:00","review":{"deadline":"2024-05-01T12:10:00Z"}}`)
+	defer monolith.Close()
+	service := jsonBackend(`{"amount":10.0,"id":1,"status":"PENDING","created_at":"2024-05-01T15:00:00Z","review":{"deadline":"2024-05-01T12:30:00Z"}}`)
+	defer service.Close()
+	gateway := newShadowGateway(t, monolith.URL, service.URL)
+
+	rec := httptest.NewRecorder()
+	gateway.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/payments/pix/1", nil))
+	// O cliente recebe a resposta do backend principal, sem alteração
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"COMPLETED"`) {
+		t.Fatalf("primary response = %d %s", rec.Code, rec.Body.String())
+	}
+
+	report := waitReport(t, gateway, func(s ShadowStats) bool { return s.Mismatched == 1 })
+	m := report.Mismatches[0]
+	if m.Backend != "monolith" || m.ShadowBackend != "payments-service" || m.ShadowPath != "/pix/1" {
+		t.Errorf("mismatch = %+v", m)
+	}
+	// Só o status diverge: ordem das chaves, 10 vs 10.0, fuso do created_at e o campo ignorado não contam
+	if len(m.Differences) != 1 || m.Differences[0].Field != "status" || m.Differences[0].Shadow != "PENDING" {
+		t.Errorf("differences = %+v", m.Differences)
+	}
+
+	// Escritas não são espelhadas
+	rec = httptest.NewRecorder()
+	gateway.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/payments/pix", nil))
+	time.Sleep(50 * time.Millisecond)
+	if s := gateway.shadow.report.snapshot().Routes["pix-payments"]; s.Matched+s.Mismatched+s.Failed != 1 {
+		t.Errorf("POST was shadowed: %+v", s)
+	}
+}
+
+func TestGateway_ShadowBackendDown(t *testing.T) {
+	monolith := jsonBackend(`[{"id":1}]`)
+	defer monolith.Close()
+	service := jsonBackend(`[]`)
+	service.Close()
+	gateway := newShadowGateway(t, monolith.URL, service.URL)
+
+	rec := httptest.NewRecorder()
+	gateway.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/payments/pix", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != `[{"id":1}]` {
+		t.Fatalf("primary response = %d %s", rec.Code, rec.Body.String())
+	}
+	waitReport(t, gateway, func(s ShadowStats) bool { return s.Failed == 1 })
+
+	// DELETE zera o relatório
+	rec = httptest.NewRecorder()
+	gateway.ShadowReport().ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/_strangler/shadow", nil))
+	if rec.Code != http.StatusNoContent || len(gateway.shadow.report.snapshot().Routes) != 0 {
+		t.Errorf("reset: status %d, report %+v", rec.Code, gateway.shadow.report.snapshot())
+	}
+}
+
+func TestGateway_ShadowSkipsEventStream(t *testing.T) {
+	monolith := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: status\ndata: {\"status\":\"COMPLETED\"}\n\n")
+	}))
+	defer monolith.Close()
+	var shadowed atomic.Int32
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		shadowed.Add(1)
+	}))
+	defer service.Close()
+	gateway := newShadowGateway(t, monolith.URL, service.URL)
+
+	// Cliente SSE (Accept) e resposta SSE sem o Accept: nenhum dos dois é espelhado
+	for _, accept := range []string{"text/event-stream", ""} {
+		req := httptest.NewRequest(http.MethodGet, "/payments/pix/1/monitor", nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		gateway.ServeHTTP(rec, req)
+		if !strings.Contains(rec.Body.String(), "COMPLETED") {
+			t.Fatalf("primary response = %d %s", rec.Code, rec.Body.String())
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := shadowed.Load(); n != 0 {
+		t.Errorf("event stream shadowed %d times", n)
+	}
+}
+
+func TestResponseCapture_EventStream(t *testing.T) {
+	capture := &responseCapture{ResponseWriter: httptest.NewRecorder()}
+	capture.Header().Set("Content-Type", "text/event-stream")
+	_, _ = io.WriteString(capture, "data: 1\n\n")
+	if !capture.stream || capture.body.Len() != 0 {
+		t.Errorf("stream = %v, buffered %d bytes", capture.stream, capture.body.Len())
+	}
+
+	capture = &responseCapture{ResponseWriter: httptest.NewRecorder()}
+	capture.Header().Set("Content-Type", "application/json")
+	_, _ = io.WriteString(capture, `{"id":1}`)
+	if capture.stream || capture.body.String() != `{"id":1}` {
+		t.Errorf("stream = %v, buffered %q", capture.stream, capture.body.String())
+	}
+}
+
+func TestCompareResponses(t *testing.T) {
+	tests := []struct {
+		name          string
+		primary       string
+		shadow        string
+		shadowStatus  int
+		wantDifferent []string
+	}{
+		{"equal", `{"a":1,"b":[1,2]}`, `{"b":[1,2],"a":1}`, 200, nil},
+		{"status", `{"a":1}`, `payment not found`, 404, []string{"status"}},
+		{"list length", `[{"id":1},{"id":2}]`, `[{"id":1}]`, 200, []string{"length"}},
+		{"list item", `[{"id":1,"status":"A"}]`, `[{"id":1,"status":"B"}]`, 200, []string{"[0].status"}},
+		{"missing field", `{"a":1,"risk":{"decision":"allow"}}`, `{"a":1}`, 200, []string{"risk"}},
+		{"null and missing", `{"a":1,"review":null}`, `{"a":1}`, 200, nil},
+		{"text body", `payment not found`, `payment not found`, 200, nil},
+		{"invalid shadow json", `{"a":1}`, `oops`, 200, []string{"body"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs := compareResponses(200, []byte(tt.primary), tt.shadowStatus, []byte(tt.shadow), nil)
+			var got []string
+			for _, d := range diffs {
+				got = append(got, d.Field)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantDifferent, ",") {
+				t.Errorf("differences = %+v, want fields %v", diffs, tt.wantDifferent)
+			}
+		})
+	}
+}
@@ -0,0 +1,414 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fintech-shared/logging"
+	"fintech-strangler/routing"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// eventStream é o Content-Type do SSE
+const eventStream = "text/event-stream"
+
+// ShadowHeader marca a cópia enviada ao backend espelhado (aparece nos logs dele)
+const ShadowHeader = "X-Strangler-Shadow"
+
+// Limites do espelhamento: a cópia nunca atrasa nem derruba a resposta principal
+const (
+	shadowTimeout     = 5 * time.Second
+	maxShadowInFlight = 16      // Cópias simultâneas; acima disso a cópia é descartada
+	maxCompareBody    = 1 << 20 // Respostas maiores não são comparadas
+	maxMismatches     = 100     // Divergências guardadas no relatório (as mais recentes)
+	maxDifferences    = 20      // Diferenças listadas por divergência
+)
+
+// shadower envia a cópia das leituras ao outro backend da rota, depois que a
+// resposta principal já foi entregue, e registra as divergências no relatório
+type shadower struct {
+	client *http.Client
+	slots  chan struct{}
+	report *ShadowReport
+}
+
+func newShadower(transport http.RoundTripper) *shadower {
+	return &shadower{
+		client: &http.Client{Transport: transport, Timeout: shadowTimeout},
+		slots:  make(chan struct{}, maxShadowInFlight),
+		report: NewShadowReport(),
+	}
+}
+
+// mirror espelha a requisição já respondida pelo backend principal
+func (s *shadower) mirror(r *http.Request, decision routing.Decision, primary *responseCapture) {
+	// SSE (monitor de pagamentos): não há resposta única para comparar
+	if primary.stream {
+		return
+	}
+	if primary.overflow {
+		s.report.dropped(decision.Route)
+		return
+	}
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		s.report.dropped(decision.Route)
+		return
+	}
+
+	// A requisição original não pode ser usada depois que o handler retorna
+	shadow := decision.Shadow
+	target := *shadow.Target
+	target.Path = strings.TrimSuffix(target.Path, "/") + shadow.Path
+	target.RawQuery = r.URL.RawQuery
+	header := r.Header.Clone()
+	header.Set(ShadowHeader, "true")
+	mismatch := Mismatch{
+		RequestID:     logging.RequestID(r.Context()),
+		Route:         decision.Route,
+		Method:        r.Method,
+		Path:          r.URL.Path,
+		Backend:       decision.Backend,
+		ShadowBackend: shadow.Backend,
+		ShadowPath:    shadow.Path,
+		Status:        primary.statusCode(),
+	}
+	body := primary.body.Bytes()
+
+	go func() {
+		defer func() { <-s.slots }()
+		ctx := logging.WithRequestID(context.Background(), mismatch.RequestID)
+		s.compare(ctx, target.String(), header, body, shadow.Ignore, mismatch)
+	}()
+}
+
+func (s *shadower) compare(ctx context.Context, url string, header http.Header, primaryBody []byte, ignore []string, mismatch Mismatch) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		s.report.failed(mismatch.Route)
+		return
+	}
+	req.Header = header
+	resp, err := s.client.Do(req)
+	if err != nil {
+		slog.WarnContext(ctx, "shadow request failed", "route", mismatch.Route, keyBackend, mismatch.ShadowBackend, "error", err)
+		s.report.failed(mismatch.Route)
+		return
+	}
+	defer resp.Body.Close()
+	shadowBody, err := io.ReadAll(io.LimitReader(resp.Body, maxCompareBody+1))
+	if err != nil {
+		s.report.failed(mismatch.Route)
+		return
+	}
+	if len(shadowBody) > maxCompareBody {
+		s.report.dropped(mismatch.Route)
+		return
+	}
+
+	mismatch.ShadowStatus = resp.StatusCode
+	mismatch.Differences = compareResponses(mismatch.Status, primaryBody, resp.StatusCode, shadowBody, ignore)
+	if len(mismatch.Differences) == 0 {
+		s.report.matched(mismatch.Route)
+		return
+	}
+	mismatch.Time = time.Now().UTC()
+	slog.WarnContext(ctx, "shadow response mismatch",
+		"route", mismatch.Route,
+		"path", mismatch.Path,
+		keyBackend, mismatch.Backend,
+		"shadow_backend", mismatch.ShadowBackend,
+		"differences", len(mismatch.Differences),
+	)
+	s.report.mismatched(mismatch)
+}
+
+// compareResponses compara status e corpo. Corpos JSON são comparados campo
+// a campo (ordem das chaves e formatação não contam); os demais, como texto.
+func compareResponses(status int, body []byte, shadowStatus int, shadowBody []byte, ignore []string) []Difference {
+	if status != shadowStatus {
+		return []Difference{{Field: "status", Primary: status, Shadow: shadowStatus}}
+	}
+	var primary, shadow any
+	if json.Unmarshal(body, &primary) != nil {
+		if !bytes.Equal(bytes.TrimSpace(body), bytes.TrimSpace(shadowBody)) {
+			return []Difference{{Field: "body", Primary: string(body), Shadow: string(shadowBody)}}
+		}
+		return nil
+	}
+	if json.Unmarshal(shadowBody, &shadow) != nil {
+		return []Difference{{Field: "body", Primary: "valid json", Shadow: string(shadowBody)}}
+	}
+	d := differ{ignore: ignore}
+	d.diff("", "", primary, shadow)
+	return d.found
+}
+
+// differ percorre os dois documentos JSON juntos. field é o caminho exibido
+// (com índices: "[0].status"); match é o usado no ignore (sem índices: "status").
+type differ struct {
+	ignore []string
+	found  []Difference
+}
+
+func (d *differ) diff(field, match string, a, b any) {
+	if len(d.found) >= maxDifferences {
+		return
+	}
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, key := range unionKeys(av, bv) {
+			if d.ignored(key, join(match, key)) {
+				continue
+			}
+			d.diff(join(field, key), join(match, key), av[key], bv[key])
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		if len(av) != len(bv) {
+			d.found = append(d.found, Difference{Field: join(field, "length"), Primary: len(av), Shadow: len(bv)})
+			return
+		}
+		for i := range av {
+			d.diff(fmt.Sprintf("%s[%d]", field, i), match, av[i], bv[i])
+		}
+		return
+	}
+	// Valores simples (ausente e null são equivalentes)
+	if !reflect.DeepEqual(a, b) && !sameInstant(a, b) {
+		if field == "" {
+			field = "body"
+		}
+		d.found = append(d.found, Difference{Field: field, Primary: a, Shadow: b})
+	}
+}
+
+// sameInstant trata como iguais datas do mesmo instante em fusos diferentes
+// (cada backend formata no fuso da sua conexão com o banco)
+func sameInstant(a, b any) bool {
+	as, ok := a.(string)
+	if !ok {
+		return false
+	}
+	bs, ok := b.(string)
+	if !ok {
+		return false
+	}
+	at, err := time.Parse(time.RFC3339Nano, as)
+	if err != nil {
+		return false
+	}
+	bt, err := time.Parse(time.RFC3339Nano, bs)
+	return err == nil && at.Equal(bt)
+}
+
+func (d *differ) ignored(key, path string) bool {
+	for _, name := range d.ignore {
+		if name == key || name == path {
+			return true
+		}
+	}
+	return false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// responseCapture guarda uma cópia da resposta principal enquanto ela é
+// entregue ao cliente. Repassa Flush (SSE).
+type responseCapture struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool // Passou de maxCompareBody: não será comparada
+	stream   bool // SSE: repassado sem cópia, não será comparado
+}
+
+func (c *responseCapture) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+		c.stream = strings.HasPrefix(c.Header().Get("Content-Type"), eventStream)
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *responseCapture) Write(p []byte) (int, error) {
+	if c.status == 0 {
+		c.WriteHeader(http.StatusOK)
+	}
+	if !c.overflow && !c.stream {
+		if c.body.Len()+len(p) > maxCompareBody {
+			c.overflow = true
+			c.body = bytes.Buffer{}
+		} else {
+			c.body.Write(p)
+		}
+	}
+	return c.ResponseWriter.Write(p)
+}
+
+func (c *responseCapture) Flush() {
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (c *responseCapture) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+func (c *responseCapture) statusCode() int {
+	if c.status == 0 {
+		return http.StatusOK
+	}
+	return c.status
+}
+
+// Difference é um campo com valores diferentes nos dois backends
+type Difference struct {
+	Field   string `json:"field"`
+	Primary any    `json:"primary"`
+	Shadow  any    `json:"shadow"`
+}
+
+// Mismatch é uma leitura cujas respostas divergiram
+type Mismatch struct {
+	Time          time.Time    `json:"time"`
+	RequestID     string       `json:"request_id"`
+	Route         string       `json:"route"`
+	Method        string       `json:"method"`
+	Path          string       `json:"path"`
+	Backend       string       `json:"backend"` // Backend que respondeu ao cliente
+	ShadowBackend string       `json:"shadow_backend"`
+	ShadowPath    string       `json:"shadow_path"`
+	Status        int          `json:"status"`
+	ShadowStatus  int          `json:"shadow_status"`
+	Differences   []Difference `json:"differences"`
+}
+
+// ShadowStats são os contadores do espelhamento de uma rota
+type ShadowStats struct {
+	Matched    int64 `json:"matched"`
+	Mismatched int64 `json:"mismatched"`
+	Failed     int64 `json:"failed"`  // A cópia não teve resposta (erro ou timeout)
+	Dropped    int64 `json:"dropped"` // Não comparadas: cópias demais em andamento ou resposta grande demais
+}
+
+// ShadowReport acumula o resultado do espelhamento. GET /_strangler/shadow
+// (porta de administração) devolve os contadores por rota e as divergências
+// mais recentes; DELETE zera.
+type ShadowReport struct {
+	mu         sync.Mutex
+	routes     map[string]*ShadowStats
+	mismatches []Mismatch
+}
+
+func NewShadowReport() *ShadowReport {
+	return &ShadowReport{routes: make(map[string]*ShadowStats)}
+}
+
+func (r *ShadowReport) stats(route string) *ShadowStats {
+	s, ok := r.routes[route]
+	if !ok {
+		s = &ShadowStats{}
+		r.routes[route] = s
+	}
+	return s
+}
+
+func (r *ShadowReport) matched(route string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats(route).Matched++
+}
+
+func (r *ShadowReport) failed(route string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats(route).Failed++
+}
+
+func (r *ShadowReport) dropped(route string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats(route).Dropped++
+}
+
+func (r *ShadowReport) mismatched(m Mismatch) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats(m.Route).Mismatched++
+	r.mismatches = append(r.mismatches, m)
+	if len(r.mismatches) > maxMismatches {
+		r.mismatches = r.mismatches[len(r.mismatches)-maxMismatches:]
+	}
+}
+
+type shadowReportResponse struct {
+	Routes     map[string]ShadowStats `json:"routes"`
+	Mismatches []Mismatch             `json:"mismatches"` // Mais recentes primeiro
+}
+
+func (r *ShadowReport) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(r.snapshot())
+	case http.MethodDelete:
+		r.mu.Lock()
+		r.routes = make(map[string]*ShadowStats)
+		r.mismatches = nil
+		r.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (r *ShadowReport) snapshot() shadowReportResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	resp := shadowReportResponse{Routes: make(map[string]ShadowStats, len(r.routes)), Mismatches: make([]Mismatch, 0, len(r.mismatches))}
+	for route, s := range r.routes {
+		resp.Routes[route] = *s
+	}
+	for i := len(r.mismatches) - 1; i >= 0; i-- {
+		resp.Mismatches = append(resp.Mismatches, r.mismatches[i])
+	}
+	return resp
+}
@@ -0,0 +1,173 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Modos de divisão do tráfego entre os backends de uma rota
+const (
+	// SplitByClient mantém cada cliente (API key, token ou IP) sempre no mesmo
+	// backend: enquanto os bancos não estão sincronizados, o pagamento criado
+	// num backend só existe nele
+	SplitByClient = "client"
+	// SplitByRequest sorteia o backend a cada requisição
+	SplitByRequest = "request"
+)
+
+// Config é o arquivo de rotas do gateway (YAML)
+type Config struct {
+	// Backends pelo nome; a URL aceita ${VAR} e ${VAR:-padrão}
+	Backends map[string]BackendConfig `yaml:"backends"`
+	// Rotas avaliadas na ordem do arquivo: vale a primeira que casar
+	Routes []RouteConfig `yaml:"routes"`
+	// Backend das requisições que não casam com nenhuma rota ("" = 404)
+	Default string `yaml:"default"`
+}
+
+type BackendConfig struct {
+	URL string `yaml:"url"`
+}
+
+type RouteConfig struct {
+	Name string `yaml:"name"`
+	// Prefixo do caminho, por segmento: "/pix" casa com /pix e /pix/1, não com /pixel
+	Prefix string `yaml:"prefix"`
+	// Métodos aceitos (vazio = todos)
+	Methods []string `yaml:"methods"`
+	// client (padrão) ou request
+	SplitBy string `yaml:"split_by"`
+	// Backends da rota com o percentual de cada um (soma 100)
+	Targets []TargetConfig `yaml:"targets"`
+	// Espelhamento das leituras no outro backend da rota, só para comparar
+	Shadow ShadowConfig `yaml:"shadow"`
+}
+
+type ShadowConfig struct {
+	// Percentual das leituras (GET) espelhadas (0 = desligado)
+	Percent int `yaml:"percent"`
+	// Campos ignorados na comparação: pelo nome ("created_at", em qualquer
+	// nível) ou pelo caminho ("review.deadline")
+	Ignore []string `yaml:"ignore"`
+}
+
+type TargetConfig struct {
+	Backend string `yaml:"backend"`
+	Weight  int    `yaml:"weight"`
+	// Prefixo que substitui o da rota no backend (vazio = mantém o caminho)
+	Rewrite string `yaml:"rewrite"`
+}
+
+// Load lê e valida o arquivo de rotas
+func Load(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("routing: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(expandEnv(string(data))), &cfg); err != nil {
+		return nil, fmt.Errorf("routing: invalid yaml in %s: %w", path, err)
+	}
+	return Compile(cfg)
+}
+
+// Compile valida a configuração e monta a tabela usada pelo Router
+func Compile(cfg Config) (*Table, error) {
+	table := &Table{backends: make(map[string]*url.URL, len(cfg.Backends)), defaultBackend: cfg.Default}
+	for name, backend := range cfg.Backends {
+		target, err := url.Parse(backend.URL)
+		if err != nil || target.Scheme == "" || target.Host == "" {
+			return nil, fmt.Errorf("routing: backend %q has invalid url %q", name, backend.URL)
+		}
+		table.backends[name] = target
+	}
+	if cfg.Default != "" && table.backends[cfg.Default] == nil {
+		return nil, fmt.Errorf("routing: default backend %q is not defined", cfg.Default)
+	}
+
+	for i, rc := range cfg.Routes {
+		route, err := compileRoute(rc, table.backends)
+		if err != nil {
+			name := rc.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("routing: route %s: %w", name, err)
+		}
+		table.routes = append(table.routes, route)
+	}
+	return table, nil
+}
+
+func compileRoute(rc RouteConfig, backends map[string]*url.URL) (route, error) {
+	r := route{name: rc.Name, prefix: strings.TrimSuffix(rc.Prefix, "/"), splitBy: rc.SplitBy}
+	if r.name == "" {
+		r.name = rc.Prefix
+	}
+	if !strings.HasPrefix(rc.Prefix, "/") {
+		return r, errors.New("prefix must start with /")
+	}
+	switch r.splitBy {
+	case "":
+		r.splitBy = SplitByClient
+	case SplitByClient, SplitByRequest:
+	default:
+		return r, fmt.Errorf("split_by must be %q or %q", SplitByClient, SplitByRequest)
+	}
+	for _, method := range rc.Methods {
+		r.methods = append(r.methods, strings.ToUpper(method))
+	}
+
+	if len(rc.Targets) == 0 {
+		return r, errors.New("at least one target is required")
+	}
+	total := 0
+	for _, tc := range rc.Targets {
+		if backends[tc.Backend] == nil {
+			return r, fmt.Errorf("backend %q is not defined", tc.Backend)
+		}
+		if tc.Weight < 0 {
+			return r, fmt.Errorf("target %s has negative weight", tc.Backend)
+		}
+		if tc.Rewrite != "" && !strings.HasPrefix(tc.Rewrite, "/") {
+			return r, fmt.Errorf("target %s: rewrite must start with /", tc.Backend)
+		}
+		total += tc.Weight
+		r.targets = append(r.targets, target{backend: tc.Backend, weight: tc.Weight, rewrite: strings.TrimSuffix(tc.Rewrite, "/")})
+	}
+	// Um único backend dispensa o peso
+	if len(r.targets) == 1 && total == 0 {
+		r.targets[0].weight = 100
+		total = 100
+	}
+	if total != 100 {
+		return r, fmt.Errorf("target weights sum to %d, want 100", total)
+	}
+
+	if rc.Shadow.Percent < 0 || rc.Shadow.Percent > 100 {
+		return r, fmt.Errorf("shadow percent must be between 0 and 100, got %d", rc.Shadow.Percent)
+	}
+	if rc.Shadow.Percent > 0 && !r.hasBackends(2) {
+		return r, errors.New("shadow needs targets on two different backends")
+	}
+	r.shadowPercent = rc.Shadow.Percent
+	r.shadowIgnore = rc.Shadow.Ignore
+	return r, nil
+}
+
+// expandEnv substitui ${VAR} e ${VAR:-padrão} pelas variáveis de ambiente,
+// para o mesmo arquivo servir local (localhost) e no docker compose
+func expandEnv(s string) string {
+	return os.Expand(s, func(key string) string {
+		name, fallback, hasFallback := strings.Cut(key, ":-")
+		if value := os.Getenv(name); value != "" || !hasFallback {
+			return value
+		}
+		return fallback
+	})
+}
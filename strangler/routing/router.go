@@ -0,0 +1,189 @@
+package routing
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// Table é a configuração de rotas já validada
+type Table struct {
+	backends       map[string]*url.URL
+	routes         []route
+	defaultBackend string
+}
+
+type route struct {
+	name    string
+	prefix  string
+	methods []string
+	splitBy string
+	targets []target
+
+	shadowPercent int
+	shadowIgnore  []string
+}
+
+type target struct {
+	backend string
+	weight  int
+	rewrite string
+}
+
+// Decision é o backend escolhido para uma requisição
+type Decision struct {
+	Route   string   // "" quando vale o backend padrão
+	Backend string   // Nome do backend
+	Target  *url.URL // URL base do backend
+	Path    string   // Caminho no backend, já reescrito
+	Shadow  *Shadow  // Cópia da requisição para comparação (nil = sem espelhamento)
+}
+
+// Shadow é o outro backend da rota, que recebe uma cópia da leitura só para
+// comparar a resposta com a do backend escolhido
+type Shadow struct {
+	Backend string
+	Target  *url.URL
+	Path    string
+	Ignore  []string // Campos ignorados na comparação (ver ShadowConfig)
+}
+
+// Router decide o backend de cada requisição. A tabela pode ser trocada a
+// quente (Update) sem afetar as requisições em andamento.
+type Router struct {
+	table atomic.Pointer[Table]
+}
+
+func NewRouter(table *Table) *Router {
+	r := &Router{}
+	r.table.Store(table)
+	return r
+}
+
+// Update troca a tabela de rotas
+func (r *Router) Update(table *Table) {
+	r.table.Store(table)
+}
+
+// Decide escolhe o backend da requisição: a primeira rota que casar com
+// caminho e método, ou o backend padrão. false se nenhum atender.
+func (r *Router) Decide(req *http.Request) (Decision, bool) {
+	table := r.table.Load()
+	path := req.URL.Path
+
+	for _, rt := range table.routes {
+		rest, ok := matchPrefix(path, rt.prefix)
+		if !ok || !rt.allows(req.Method) {
+			continue
+		}
+		t := rt.pick(req)
+		decision := Decision{Route: rt.name, Backend: t.backend, Target: table.backends[t.backend], Path: t.path(path, rest)}
+		if other, ok := rt.shadowFor(req, t); ok {
+			decision.Shadow = &Shadow{Backend: other.backend, Target: table.backends[other.backend], Path: other.path(path, rest), Ignore: rt.shadowIgnore}
+		}
+		return decision, true
+	}
+
+	if table.defaultBackend == "" {
+		return Decision{}, false
+	}
+	return Decision{Backend: table.defaultBackend, Target: table.backends[table.defaultBackend], Path: path}, true
+}
+
+// matchPrefix casa o prefixo por segmento e retorna o restante do caminho
+func matchPrefix(path, prefix string) (string, bool) {
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+	rest := path[len(prefix):]
+	if rest != "" && rest[0] != '/' {
+		return "", false
+	}
+	return rest, true
+}
+
+func (rt route) allows(method string) bool {
+	if len(rt.methods) == 0 {
+		return true
+	}
+	for _, m := range rt.methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// path é o caminho no backend: o prefixo da rota trocado pelo rewrite
+func (t target) path(path, rest string) string {
+	if t.rewrite == "" {
+		return path
+	}
+	return t.rewrite + rest
+}
+
+// hasBackends informa se a rota tem pelo menos n backends diferentes
+func (rt route) hasBackends(n int) bool {
+	seen := map[string]bool{}
+	for _, t := range rt.targets {
+		seen[t.backend] = true
+	}
+	return len(seen) >= n
+}
+
+// shadowFor sorteia se a requisição é espelhada e escolhe o backend da
+// cópia: o primeiro da rota diferente do escolhido. Só leituras (GET) são
+// espelhadas: repetir uma escrita criaria o pagamento duas vezes.
+func (rt route) shadowFor(req *http.Request, chosen target) (target, bool) {
+	if rt.shadowPercent == 0 || req.Method != http.MethodGet || rand.Intn(100) >= rt.shadowPercent {
+		return target{}, false
+	}
+	for _, t := range rt.targets {
+		if t.backend != chosen.backend {
+			return t, true
+		}
+	}
+	return target{}, false
+}
+
+// pick escolhe o backend conforme os pesos. Por cliente, o mesmo cliente cai
+// sempre na mesma faixa de 0 a 99 (em todas as rotas); com dois backends,
+// aumentar o peso de um só move clientes para ele, nunca de volta.
+func (rt route) pick(req *http.Request) target {
+	var bucket int
+	if rt.splitBy == SplitByRequest {
+		bucket = rand.Intn(100)
+	} else {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(clientKey(req)))
+		bucket = int(h.Sum32() % 100)
+	}
+
+	for _, t := range rt.targets {
+		if bucket < t.weight {
+			return t
+		}
+		bucket -= t.weight
+	}
+	return rt.targets[len(rt.targets)-1]
+}
+
+// clientKey identifica o cliente pela credencial (API key ou token) ou, sem
+// ela, pelo IP
+func clientKey(req *http.Request) string {
+	if key := req.Header.Get("X-API-Key"); key != "" {
+		return "key:" + key
+	}
+	if header := req.Header.Get("Authorization"); header != "" {
+		return "auth:" + header
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	return "ip:" + host
+}
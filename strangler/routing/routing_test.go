@@ -0,0 +1,257 @@
+package routing
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRoutes = `
+backends:
+  monolith:
+    url: ${TEST_MONOLITH_URL:-http://localhost:8080}
+  payments-service:
+    url: http://localhost:8081
+default: monolith
+routes:
+  - name: pix-legacy
+    prefix: /payments/pix
+    targets:
+      - backend: monolith
+        weight: 70
+      - backend: payments-service
+        weight: 30
+        rewrite: /pix
+  - name: pix
+    prefix: /pix
+    targets:
+      - backend: payments-service
+  - name: reviews-read
+    prefix: /reviews
+    methods: [get]
+    targets:
+      - backend: payments-service
+`
+
+func writeRoutes(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func loadRouter(t *testing.T, content string) *Router {
+	t.Helper()
+	table, err := Load(writeRoutes(t, content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewRouter(table)
+}
+
+func TestLoad_ExpandsEnv(t *testing.T) {
+	table, err := Load(writeRoutes(t, testRoutes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := table.backends["monolith"].String(); got != "http://localhost:8080" {
+		t.Errorf("monolith url = %q, want fallback", got)
+	}
+
+	t.Setenv("TEST_MONOLITH_URL", "http://monolith-api:8080")
+	table, err = Load(writeRoutes(t, testRoutes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := table.backends["monolith"].String(); got != "http://monolith-api:8080" {
+		t.Errorf("monolith url = %q, want from env", got)
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	backends := map[string]BackendConfig{"monolith": {URL: "http://localhost:8080"}}
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"bad url", Config{Backends: map[string]BackendConfig{"x": {URL: "localhost"}}}, "invalid url"},
+		{"unknown default", Config{Backends: backends, Default: "other"}, "default backend"},
+		{"unknown backend", Config{Backends: backends, Routes: []RouteConfig{
+			{Prefix: "/pix", Targets: []TargetConfig{{Backend: "other"}}},
+		}}, "not defined"},
+		{"weights", Config{Backends: backends, Routes: []RouteConfig{
+			{Prefix: "/pix", Targets: []TargetConfig{{Backend: "monolith", Weight: 50}}},
+		}}, "sum to 50"},
+		{"no targets", Config{Backends: backends, Routes: []RouteConfig{{Prefix: "/pix"}}}, "at least one target"},
+		{"prefix", Config{Backends: backends, Routes: []RouteConfig{
+			{Prefix: "pix", Targets: []TargetConfig{{Backend: "monolith"}}},
+		}}, "must start with /"},
+		{"split_by", Config{Backends: backends, Routes: []RouteConfig{
+			{Prefix: "/pix", SplitBy: "random", Targets: []TargetConfig{{Backend: "monolith"}}},
+		}}, "split_by"},
+		{"shadow percent", Config{Backends: backends, Routes: []RouteConfig{
+			{Prefix: "/pix", Targets: []TargetConfig{{Backend: "monolith"}}, Shadow: ShadowConfig{Percent: 101}},
+		}}, "between 0 and 100"},
+		{"shadow single backend", Config{Backends: backends, Routes: []RouteConfig{
+			{Prefix: "/pix", Targets: []TargetConfig{{Backend: "monolith"}}, Shadow: ShadowConfig{Percent: 10}},
+		}}, "two different backends"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouter_Decide(t *testing.T) {
+	router := loadRouter(t, strings.NewReplacer("weight: 70", "weight: 100", "weight: 30", "weight: 0").Replace(testRoutes))
+
+	tests := []struct {
+		method, path      string
+		backend, upstream string
+		route             string
+	}{
+		{http.MethodPost, "/payments/pix", "monolith", "/payments/pix", "pix-legacy"},
+		{http.MethodGet, "/pix/42", "payments-service", "/pix/42", "pix"},
+		{http.MethodGet, "/reviews", "payments-service", "/reviews", "reviews-read"},
+		// Método fora da rota: vai para o padrão
+		{http.MethodPost, "/reviews/1/approve", "monolith", "/reviews/1/approve", ""},
+		// Prefixo casa por segmento
+		{http.MethodGet, "/pixel", "monolith", "/pixel", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		d, ok := router.Decide(req)
+		if !ok || d.Backend != tt.backend || d.Path != tt.upstream || d.Route != tt.route {
+			t.Errorf("%s %s: decision = %+v, want %s %s (%s)", tt.method, tt.path, d, tt.backend, tt.upstream, tt.route)
+		}
+	}
+}
+
+func TestRouter_DecideWithoutDefault(t *testing.T) {
+	router := loadRouter(t, strings.Replace(testRoutes, "default: monolith", "", 1))
+	if d, ok := router.Decide(httptest.NewRequest(http.MethodGet, "/webhooks", nil)); ok {
+		t.Errorf("decision = %+v, want none", d)
+	}
+}
+
+func TestRouter_SplitByClient(t *testing.T) {
+	router := loadRouter(t, testRoutes)
+
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		var first Decision
+		// O mesmo cliente cai sempre no mesmo backend
+		for j := 0; j < 3; j++ {
+			req := httptest.NewRequest(http.MethodGet, "/payments/pix/7", nil)
+			req.Header.Set("X-API-Key", key)
+			d, _ := router.Decide(req)
+			if j == 0 {
+				first = d
+			} else if d != first {
+				t.Fatalf("client %s moved from %s to %s", key, first.Backend, d.Backend)
+			}
+		}
+		counts[first.Backend]++
+		// Caminho reescrito para o esquema do microsserviço
+		if first.Backend == "payments-service" && first.Path != "/pix/7" {
+			t.Errorf("upstream path = %q, want /pix/7", first.Path)
+		}
+	}
+	if counts["payments-service"] < 230 || counts["payments-service"] > 370 {
+		t.Errorf("payments-service got %d of 1000 clients, want about 300", counts["payments-service"])
+	}
+}
+
+func TestRouter_IncreasingWeightOnlyMovesClientsForward(t *testing.T) {
+	before := loadRouter(t, testRoutes)
+	after := loadRouter(t, strings.NewReplacer("weight: 70", "weight: 40", "weight: 30", "weight: 60").Replace(testRoutes))
+
+	for i := 0; i < 500; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/payments/pix", nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer token-%d", i))
+		b, _ := before.Decide(req)
+		a, _ := after.Decide(req)
+		if b.Backend == "payments-service" && a.Backend != "payments-service" {
+			t.Fatalf("client %d moved back to %s", i, a.Backend)
+		}
+	}
+}
+
+func TestRouter_Shadow(t *testing.T) {
+	router := loadRouter(t, strings.Replace(testRoutes, "        rewrite: /pix\n", "        rewrite: /pix\n    shadow:\n      percent: 100\n      ignore: [created_at]\n", 1))
+
+	for i := 0; i < 50; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/payments/pix/7?x=1", nil)
+		req.Header.Set("X-API-Key", fmt.Sprintf("key-%d", i))
+		d, _ := router.Decide(req)
+		if d.Shadow == nil {
+			t.Fatalf("client %d: GET without shadow", i)
+		}
+		// A cópia vai sempre para o outro backend, com o caminho dele
+		want := map[string]string{"monolith": "payments-service /pix/7", "payments-service": "monolith /payments/pix/7"}[d.Backend]
+		if got := d.Shadow.Backend + " " + d.Shadow.Path; got != want || d.Shadow.Ignore[0] != "created_at" {
+			t.Fatalf("primary %s: shadow = %+v, want %s", d.Backend, d.Shadow, want)
+		}
+	}
+
+	// Escritas nunca são espelhadas
+	if d, _ := router.Decide(httptest.NewRequest(http.MethodPost, "/payments/pix", nil)); d.Shadow != nil {
+		t.Errorf("POST shadowed to %s", d.Shadow.Backend)
+	}
+	// Rota sem shadow
+	if d, _ := router.Decide(httptest.NewRequest(http.MethodGet, "/pix/1", nil)); d.Shadow != nil {
+		t.Errorf("route without shadow got %+v", d.Shadow)
+	}
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	path := writeRoutes(t, testRoutes)
+	watcher, router, err := NewWatcher(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/pix/1", nil)
+
+	// Arquivo inválido: mantém as rotas anteriores
+	writeFile(t, path, "routes: [")
+	if err := watcher.check(); err == nil {
+		t.Fatal("expected error for invalid yaml")
+	}
+	if d, _ := router.Decide(req); d.Backend != "payments-service" {
+		t.Errorf("backend = %s after invalid reload, want payments-service", d.Backend)
+	}
+
+	// Arquivo válido: passa a valer a nova tabela
+	writeFile(t, path, strings.Replace(testRoutes, "      - backend: payments-service\n  - name: reviews", "      - backend: monolith\n        rewrite: /payments/pix\n  - name: reviews", 1))
+	if err := watcher.check(); err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := router.Decide(req); d.Backend != "monolith" || d.Path != "/payments/pix/1" {
+		t.Errorf("decision = %+v after reload", d)
+	}
+}
+
+// writeFile reescreve o arquivo com data de modificação nova (o watcher compara data e tamanho)
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
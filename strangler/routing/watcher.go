@@ -0,0 +1,91 @@
+package routing
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Watcher recarrega o arquivo de rotas quando ele muda. Um arquivo inválido é
+// ignorado: o gateway segue com a última tabela válida.
+type Watcher struct {
+	path   string
+	router *Router
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher carrega o arquivo e cria o Router com a tabela inicial
+func NewWatcher(path string) (*Watcher, *Router, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	table, err := Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	router := NewRouter(table)
+	return &Watcher{path: path, router: router, modTime: info.ModTime(), size: info.Size()}, router, nil
+}
+
+// Reload relê o arquivo, mesmo sem mudança (ex.: SIGHUP)
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return err
+	}
+	return w.reload(info)
+}
+
+// Start verifica o arquivo a cada intervalo até o ctx ser cancelado
+func (w *Watcher) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := w.check(); err != nil {
+					slog.Error("routing config reload failed, keeping previous routes", "path", w.path, "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// check recarrega só se o arquivo mudou (data de modificação ou tamanho)
+func (w *Watcher) check() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return nil
+	}
+	return w.reload(info)
+}
+
+func (w *Watcher) reload(info os.FileInfo) error {
+	// Registra a versão vista mesmo se inválida, para não repetir o erro a cada verificação
+	w.modTime, w.size = info.ModTime(), info.Size()
+	table, err := Load(w.path)
+	if err != nil {
+		return err
+	}
+	w.router.Update(table)
+	slog.Info("routing config reloaded", "path", w.path, "routes", len(table.routes))
+	return nil
+}